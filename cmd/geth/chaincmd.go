@@ -163,6 +163,26 @@ be gzipped.`,
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
 The export-preimages command export hash preimages to an RLP encoded stream`,
+	}
+	exportEpochsCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportEpochs),
+		Name:      "export-epochs",
+		Usage:     "Export the epoch headers and their validator set seals into an RLP stream",
+		ArgsUsage: "<dumpfile>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AlfajoresFlag,
+			utils.BaklavaFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The export-epochs command writes the last header of every epoch, together with
+its epoch validator set seal, to an RLP encoded stream. The stream can be checked
+from the genesis block alone with the consensus/istanbul/epochproof package, which
+makes it suitable for bootstrapping mobile and bridge clients. If the file ends
+with .gz, the output will be gzipped.`,
 	}
 	dumpCommand = cli.Command{
 		Action:    utils.MigrateFlags(dump),
@@ -384,6 +404,25 @@ func exportPreimages(ctx *cli.Context) error {
 	return nil
 }
 
+// exportEpochs dumps the epoch headers of the canonical chain into a file.
+func exportEpochs(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, _ := utils.MakeChain(ctx, stack)
+	start := time.Now()
+
+	if err := utils.ExportEpochs(chain, ctx.Args().First()); err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
 func parseDumpConfig(ctx *cli.Context, stack *node.Node) (*state.DumpConfig, ethdb.Database, common.Hash, error) {
 	db := utils.MakeChainDatabase(ctx, stack, true)
 	var header *types.Header
@@ -78,6 +78,9 @@ var (
 		utils.TxPoolNoLocalsFlag,
 		utils.TxPoolJournalFlag,
 		utils.TxPoolRejournalFlag,
+		utils.TxPoolRemoteJournalFlag,
+		utils.TxPoolRemoteJournalSizeFlag,
+		utils.TxPoolRemoteJournalLifetimeFlag,
 		utils.TxPoolPriceLimitFlag,
 		utils.TxPoolPriceBumpFlag,
 		utils.TxPoolAccountSlotsFlag,
@@ -85,11 +88,14 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolConditionalTxsFlag,
+		utils.TxPoolConditionalSlotsFlag,
 		utils.SyncModeFlag,
 		utils.ExitWhenSyncedFlag,
 		utils.GCModeFlag,
 		utils.SnapshotFlag,
 		utils.TxLookupLimitFlag,
+		utils.TraceIndexFlag,
 		utils.LightServeFlag,
 		utils.LightIngressFlag,
 		utils.LightEgressFlag,
@@ -102,6 +108,7 @@ var (
 		utils.UltraLightOnlyAnnounceFlag,
 		utils.LightNoSyncServeFlag,
 		utils.WhitelistFlag,
+		utils.TrustedEpochFlag,
 		utils.EtherbaseFlag,
 		utils.TxFeeRecipientFlag,
 		utils.BLSbaseFlag,
@@ -140,10 +147,10 @@ var (
 		utils.CeloStatsURLFlag,
 		utils.LegacyEthStatsURLFlag,
 		utils.NoCompactionFlag,
-		// utils.GpoBlocksFlag,
-		// utils.GpoPercentileFlag,
-		// utils.GpoMaxGasPriceFlag,
-		// utils.GpoIgnoreGasPriceFlag,
+		utils.GpoBlocksFlag,
+		utils.GpoPercentileFlag,
+		utils.GpoMaxGasPriceFlag,
+		utils.GpoIgnoreGasPriceFlag,
 		configFileFlag,
 		utils.LegacyIstanbulRequestTimeoutFlag,
 		utils.LegacyIstanbulBlockPeriodFlag,
@@ -230,6 +237,7 @@ func init() {
 		exportCommand,
 		importPreimagesCommand,
 		exportPreimagesCommand,
+		exportEpochsCommand,
 		removedbCommand,
 		dumpCommand,
 		dumpGenesisCommand,
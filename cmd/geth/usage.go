@@ -47,10 +47,12 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.ExitWhenSyncedFlag,
 			utils.GCModeFlag,
 			utils.TxLookupLimitFlag,
+			utils.TraceIndexFlag,
 			utils.CeloStatsURLFlag,
 			utils.IdentityFlag,
 			utils.LightKDFFlag,
 			utils.WhitelistFlag,
+			utils.TrustedEpochFlag,
 			utils.TxFeeRecipientFlag,
 		},
 	},
@@ -82,6 +84,9 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.TxPoolNoLocalsFlag,
 			utils.TxPoolJournalFlag,
 			utils.TxPoolRejournalFlag,
+			utils.TxPoolRemoteJournalFlag,
+			utils.TxPoolRemoteJournalSizeFlag,
+			utils.TxPoolRemoteJournalLifetimeFlag,
 			utils.TxPoolPriceLimitFlag,
 			utils.TxPoolPriceBumpFlag,
 			utils.TxPoolAccountSlotsFlag,
@@ -89,6 +94,8 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
 			utils.TxPoolLifetimeFlag,
+			utils.TxPoolConditionalTxsFlag,
+			utils.TxPoolConditionalSlotsFlag,
 		},
 	},
 	{
@@ -175,6 +182,15 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.CeloFeeCurrencyLimits,
 		},
 	},
+	{
+		Name: "GAS PRICE ORACLE",
+		Flags: []cli.Flag{
+			utils.GpoBlocksFlag,
+			utils.GpoPercentileFlag,
+			utils.GpoMaxGasPriceFlag,
+			utils.GpoIgnoreGasPriceFlag,
+		},
+	},
 	{
 		Name: "VIRTUAL MACHINE",
 		Flags: []cli.Flag{
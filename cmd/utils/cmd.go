@@ -19,6 +19,7 @@ package utils
 
 import (
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -29,6 +30,7 @@ import (
 	"time"
 
 	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/consensus/istanbul/epochproof"
 	"github.com/celo-org/celo-blockchain/core"
 	"github.com/celo-org/celo-blockchain/core/rawdb"
 	"github.com/celo-org/celo-blockchain/core/types"
@@ -244,6 +246,57 @@ func ExportChain(blockchain *core.BlockChain, fn string) error {
 	return nil
 }
 
+// ExportEpochs exports the last header of every epoch of the canonical chain,
+// together with its epoch validator set seal, as a stream of RLP encoded
+// epoch proofs that a light verifier can check starting from genesis.
+func ExportEpochs(blockchain *core.BlockChain, fn string) error {
+	log.Info("Exporting epoch headers", "file", fn)
+
+	config := blockchain.Config()
+	if config.Istanbul == nil || config.Istanbul.Epoch == 0 {
+		return errors.New("chain is not an istanbul chain")
+	}
+	epochSize := config.Istanbul.Epoch
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	var (
+		head     = blockchain.CurrentHeader().Number.Uint64()
+		count    = 0
+		reported = time.Now()
+	)
+	for number := epochSize; number <= head; number += epochSize {
+		header := blockchain.GetHeaderByNumber(number)
+		if header == nil {
+			return fmt.Errorf("export failed on #%d: not found", number)
+		}
+		proof := epochproof.EpochProof{Header: header, Seal: &types.EmptyEpochSnarkData}
+		if block := blockchain.GetBlock(header.Hash(), number); block != nil && block.EpochSnarkData() != nil {
+			proof.Seal = block.EpochSnarkData()
+		}
+		if err := rlp.Encode(writer, &proof); err != nil {
+			return err
+		}
+		count++
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Exporting epoch headers", "exported", count, "number", number)
+			reported = time.Now()
+		}
+	}
+	log.Info("Exported epoch headers", "file", fn, "count", count)
+	return nil
+}
+
 // ExportAppendChain exports a blockchain into the specified file, appending to
 // the file if data already exists in it.
 func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, last uint64) error {
@@ -19,6 +19,7 @@ package utils
 
 import (
 	"crypto/ecdsa"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -45,6 +46,7 @@ import (
 	"github.com/celo-org/celo-blockchain/eth"
 	"github.com/celo-org/celo-blockchain/eth/downloader"
 	"github.com/celo-org/celo-blockchain/eth/ethconfig"
+	"github.com/celo-org/celo-blockchain/eth/gasprice"
 	"github.com/celo-org/celo-blockchain/eth/tracers"
 	"github.com/celo-org/celo-blockchain/ethdb"
 	"github.com/celo-org/celo-blockchain/ethstats"
@@ -213,6 +215,10 @@ var (
 		Usage: "Number of recent blocks to maintain transactions index for (default = about one year, 0 = entire chain)",
 		Value: ethconfig.Defaults.TxLookupLimit,
 	}
+	TraceIndexFlag = cli.BoolFlag{
+		Name:  "traceindex",
+		Usage: "Index the call traces of each block and serve them with the trace API, from the head when first enabled (from the genesis with --gcmode=archive)",
+	}
 	LightKDFFlag = cli.BoolFlag{
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -221,6 +227,10 @@ var (
 		Name:  "whitelist",
 		Usage: "Comma separated block number-to-hash mappings to enforce (<number>=<hash>)",
 	}
+	TrustedEpochFlag = cli.StringFlag{
+		Name:  "sync.trustedepoch",
+		Usage: `Epoch number, hash of its last header and the validator set it elects to start verifying from during lightest and fast sync, as a JSON object ({"epoch":…,"hash":…,"validators":[{"address":…,"blsPublicKey":…}]}) or the path of a file containing it`,
+	}
 	EtherbaseFlag = cli.StringFlag{
 		Name:  "etherbase",
 		Usage: "Public address for transaction broadcasting and block mining rewards (default = first account)",
@@ -316,9 +326,24 @@ var (
 	}
 	TxPoolRejournalFlag = cli.DurationFlag{
 		Name:  "txpool.rejournal",
-		Usage: "Time interval to regenerate the local transaction journal",
+		Usage: "Time interval to regenerate the local and remote transaction journals",
 		Value: core.DefaultTxPoolConfig.Rejournal,
 	}
+	TxPoolRemoteJournalFlag = cli.StringFlag{
+		Name:  "txpool.remotejournal",
+		Usage: "Disk journal for remote transactions to survive node restarts, only written every rejournal interval and on shutdown (disabled if empty)",
+		Value: core.DefaultTxPoolConfig.RemoteJournal,
+	}
+	TxPoolRemoteJournalSizeFlag = cli.Uint64Flag{
+		Name:  "txpool.remotejournalsize",
+		Usage: "Maximum number of remote transactions to journal",
+		Value: core.DefaultTxPoolConfig.RemoteJournalSize,
+	}
+	TxPoolRemoteJournalLifetimeFlag = cli.DurationFlag{
+		Name:  "txpool.remotejournallifetime",
+		Usage: "Maximum age of journaled remote transactions to reload on startup",
+		Value: core.DefaultTxPoolConfig.RemoteJournalLifetime,
+	}
 	TxPoolPriceLimitFlag = cli.Uint64Flag{
 		Name:  "txpool.pricelimit",
 		Usage: "Minimum gas price limit to enforce for acceptance into the pool",
@@ -354,6 +379,16 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: ethconfig.Defaults.TxPool.Lifetime,
 	}
+	TxPoolConditionalTxsFlag = cli.Uint64Flag{
+		Name:  "txpool.conditionaltxs",
+		Usage: "Maximum number of conditional transactions for all accounts",
+		Value: ethconfig.Defaults.TxPool.ConditionalTxs,
+	}
+	TxPoolConditionalSlotsFlag = cli.Uint64Flag{
+		Name:  "txpool.conditionalslots",
+		Usage: "Maximum number of storage slots checked by all conditional transactions",
+		Value: ethconfig.Defaults.TxPool.ConditionalSlots,
+	}
 
 	// Performance tuning settings
 
@@ -663,27 +698,27 @@ var (
 		Value: DirectoryString("."),
 	}
 
-	// // Gas price oracle settings
-	// GpoBlocksFlag = cli.IntFlag{
-	// 	Name:  "gpo.blocks",
-	// 	Usage: "Number of recent blocks to check for gas prices",
-	// 	// Value: ethconfig.Defaults.GPO.Blocks,
-	// }
-	// GpoPercentileFlag = cli.IntFlag{
-	// 	Name:  "gpo.percentile",
-	// 	Usage: "Suggested gas price is the given percentile of a set of recent transaction gas prices",
-	// 	// Value: ethconfig.Defaults.GPO.Percentile,
-	// }
-	// GpoMaxGasPriceFlag = cli.Int64Flag{
-	// 	Name:  "gpo.maxprice",
-	// 	Usage: "Maximum gas price will be recommended by gpo",
-	// 	// Value: ethconfig.Defaults.GPO.MaxPrice.Int64(),
-	// }
-	// GpoIgnoreGasPriceFlag = cli.Int64Flag{
-	// 	Name:  "gpo.ignoreprice",
-	// 	Usage: "Gas price below which gpo will ignore transactions",
-	// 	// Value: ethconfig.Defaults.GPO.IgnorePrice.Int64(),
-	// }
+	// Gas price oracle settings
+	GpoBlocksFlag = cli.IntFlag{
+		Name:  "gpo.blocks",
+		Usage: "Number of recent blocks to check for gas tips",
+		Value: ethconfig.Defaults.GPO.Blocks,
+	}
+	GpoPercentileFlag = cli.IntFlag{
+		Name:  "gpo.percentile",
+		Usage: "Suggested gas tip is the given percentile of a set of recent transaction gas tips",
+		Value: ethconfig.Defaults.GPO.Percentile,
+	}
+	GpoMaxGasPriceFlag = cli.Int64Flag{
+		Name:  "gpo.maxprice",
+		Usage: "Maximum gas tip in CELO wei that will be recommended by gpo",
+		Value: ethconfig.Defaults.GPO.MaxPrice.Int64(),
+	}
+	GpoIgnoreGasPriceFlag = cli.Int64Flag{
+		Name:  "gpo.ignoreprice",
+		Usage: "Gas tip in CELO wei below which gpo will ignore transactions",
+		Value: ethconfig.Defaults.GPO.IgnorePrice.Int64(),
+	}
 
 	// Metrics flags
 
@@ -1367,25 +1402,25 @@ func setDataDir(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
-// func setGPO(ctx *cli.Context, cfg *gasprice.Config, light bool) {
-// 	// If we are running the light client, apply another group
-// 	// settings for gas oracle.
-// 	if light {
-// 		*cfg = ethconfig.LightClientGPO
-// 	}
-// 	if ctx.GlobalIsSet(GpoBlocksFlag.Name) {
-// 		cfg.Blocks = ctx.GlobalInt(GpoBlocksFlag.Name)
-// 	}
-// 	if ctx.GlobalIsSet(GpoPercentileFlag.Name) {
-// 		cfg.Percentile = ctx.GlobalInt(GpoPercentileFlag.Name)
-// 	}
-// 	if ctx.GlobalIsSet(GpoMaxGasPriceFlag.Name) {
-// 		cfg.MaxPrice = big.NewInt(ctx.GlobalInt64(GpoMaxGasPriceFlag.Name))
-// 	}
-// 	if ctx.GlobalIsSet(GpoIgnoreGasPriceFlag.Name) {
-// 		cfg.IgnorePrice = big.NewInt(ctx.GlobalInt64(GpoIgnoreGasPriceFlag.Name))
-// 	}
-// }
+func setGPO(ctx *cli.Context, cfg *gasprice.Config, light bool) {
+	// If we are running the light client, apply another group
+	// settings for gas oracle.
+	if light {
+		*cfg = ethconfig.LightClientGPO
+	}
+	if ctx.GlobalIsSet(GpoBlocksFlag.Name) {
+		cfg.Blocks = ctx.GlobalInt(GpoBlocksFlag.Name)
+	}
+	if ctx.GlobalIsSet(GpoPercentileFlag.Name) {
+		cfg.Percentile = ctx.GlobalInt(GpoPercentileFlag.Name)
+	}
+	if ctx.GlobalIsSet(GpoMaxGasPriceFlag.Name) {
+		cfg.MaxPrice = big.NewInt(ctx.GlobalInt64(GpoMaxGasPriceFlag.Name))
+	}
+	if ctx.GlobalIsSet(GpoIgnoreGasPriceFlag.Name) {
+		cfg.IgnorePrice = big.NewInt(ctx.GlobalInt64(GpoIgnoreGasPriceFlag.Name))
+	}
+}
 
 func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLocalsFlag.Name) {
@@ -1407,6 +1442,15 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.GlobalDuration(TxPoolRejournalFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolRemoteJournalFlag.Name) {
+		cfg.RemoteJournal = ctx.GlobalString(TxPoolRemoteJournalFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolRemoteJournalSizeFlag.Name) {
+		cfg.RemoteJournalSize = ctx.GlobalUint64(TxPoolRemoteJournalSizeFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolRemoteJournalLifetimeFlag.Name) {
+		cfg.RemoteJournalLifetime = ctx.GlobalDuration(TxPoolRemoteJournalLifetimeFlag.Name)
+	}
 	if ctx.GlobalIsSet(TxPoolPriceLimitFlag.Name) {
 		cfg.PriceLimit = ctx.GlobalUint64(TxPoolPriceLimitFlag.Name)
 	}
@@ -1428,6 +1472,12 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolConditionalTxsFlag.Name) {
+		cfg.ConditionalTxs = ctx.GlobalUint64(TxPoolConditionalTxsFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolConditionalSlotsFlag.Name) {
+		cfg.ConditionalSlots = ctx.GlobalUint64(TxPoolConditionalSlotsFlag.Name)
+	}
 }
 
 func setMiner(ctx *cli.Context, cfg *miner.Config) {
@@ -1494,6 +1544,33 @@ func setWhitelist(ctx *cli.Context, cfg *ethconfig.Config) {
 	}
 }
 
+func setTrustedEpoch(ctx *cli.Context, cfg *ethconfig.Config) {
+	value := ctx.GlobalString(TrustedEpochFlag.Name)
+	if value == "" {
+		return
+	}
+	// The validator set of an epoch is usually too large to be typed inline,
+	// so the trusted epoch is read from a file unless given as a JSON object
+	data := []byte(value)
+	if !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		var err error
+		if data, err = ioutil.ReadFile(value); err != nil {
+			Fatalf("Failed to read trusted epoch file: %v", err)
+		}
+	}
+	trusted := new(params.TrustedEpoch)
+	if err := json.Unmarshal(data, trusted); err != nil {
+		Fatalf("Invalid trusted epoch: %v", err)
+	}
+	if err := trusted.Validate(); err != nil {
+		Fatalf("Invalid trusted epoch: %v", err)
+	}
+	if mode := ctx.GlobalString(SyncModeFlag.Name); mode != "lightest" && mode != "fast" {
+		log.Warn("Trusted epoch is only used by lightest and fast sync", "flag", TrustedEpochFlag.Name, "syncmode", mode)
+	}
+	cfg.TrustedEpoch = trusted
+}
+
 func setIstanbul(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(LegacyIstanbulRequestTimeoutFlag.Name) {
 		log.Warn("Flag value is ignored, and obtained from genesis config", "flag", LegacyIstanbulRequestTimeoutFlag.Name)
@@ -1699,6 +1776,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	setTxPool(ctx, &cfg.TxPool)
 	setMiner(ctx, &cfg.Miner)
 	setWhitelist(ctx, cfg)
+	setTrustedEpoch(ctx, cfg)
 	setIstanbul(ctx, stack, cfg)
 	setLes(ctx, cfg)
 	cfg.NetworkId = params.MainnetNetworkId
@@ -1726,6 +1804,8 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.GlobalIsSet(SyncModeFlag.Name) {
 		cfg.SyncMode = *GlobalTextMarshaler(ctx, SyncModeFlag.Name).(*downloader.SyncMode)
 	}
+	// Light and lightest nodes don't have the block bodies to sample many blocks.
+	setGPO(ctx, &cfg.GPO, !cfg.SyncMode.SyncFullBlockChain())
 	if ctx.GlobalIsSet(NetworkIdFlag.Name) {
 		cfg.NetworkId = ctx.GlobalUint64(NetworkIdFlag.Name)
 	}
@@ -1755,6 +1835,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.GlobalIsSet(TxLookupLimitFlag.Name) {
 		cfg.TxLookupLimit = ctx.GlobalUint64(TxLookupLimitFlag.Name)
 	}
+	if ctx.GlobalIsSet(TraceIndexFlag.Name) {
+		cfg.TraceIndex = ctx.GlobalBool(TraceIndexFlag.Name)
+	}
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheTrieFlag.Name) {
 		cfg.TrieCleanCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheTrieFlag.Name) / 100
 	}
@@ -13,13 +13,13 @@ type ticker interface {
 }
 
 func NewDefaultTicker(period time.Duration) *DefaultTicker {
-	return &DefaultTicker{*time.NewTicker(period)}
+	return &DefaultTicker{time.NewTicker(period)}
 }
 
 // DefaultTicker is an implementation of ticker which simply delegates to
 // time.Ticker.
 type DefaultTicker struct {
-	t time.Ticker
+	t *time.Ticker
 }
 
 func (d *DefaultTicker) tickChan() <-chan time.Time {
@@ -62,6 +62,14 @@ type ChainContext interface {
 	NewEVMRunner(header *types.Header, state vm.StateDB) vm.EVMRunner
 }
 
+// ReadOnlyChain is implemented by chains that blocks are finalized against
+// without persisting anything, like the one used to trace blocks.
+type ReadOnlyChain interface {
+	// ReadOnly reports whether finalizing a block must leave the database
+	// untouched.
+	ReadOnly() bool
+}
+
 // Engine is an algorithm agnostic consensus engine.
 type Engine interface {
 	// Author retrieves the Ethereum address of the account that minted the given
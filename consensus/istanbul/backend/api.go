@@ -43,15 +43,23 @@ type API struct {
 
 // getHeaderByNumber retrieves the header requested block or current if unspecified.
 func (api *API) getHeaderByNumber(number *rpc.BlockNumber) (*types.Header, error) {
+	return headerByNumber(api.chain, number)
+}
+
+// headerByNumber retrieves the header requested block or current if unspecified.
+func headerByNumber(chain consensus.ChainHeaderReader, number *rpc.BlockNumber) (*types.Header, error) {
 	var header *types.Header
 	if number == nil || *number == rpc.LatestBlockNumber {
-		header = api.chain.CurrentHeader()
+		header = chain.CurrentHeader()
 	} else if *number == rpc.PendingBlockNumber {
 		return nil, fmt.Errorf("can't use pending block within istanbul")
 	} else if *number == rpc.EarliestBlockNumber {
-		header = api.chain.GetHeaderByNumber(0)
+		header = chain.GetHeaderByNumber(0)
+	} else if *number == rpc.LatestEpochBlockNumber {
+		head := chain.CurrentHeader().Number.Uint64()
+		header = chain.GetHeaderByNumber(istanbul.GetLatestEpochLastBlockNumber(head, chain.Config().Istanbul.Epoch))
 	} else {
-		header = api.chain.GetHeaderByNumber(uint64(*number))
+		header = chain.GetHeaderByNumber(uint64(*number))
 	}
 
 	if header == nil {
@@ -75,6 +83,15 @@ func (api *API) getParentHeaderByNumber(number *rpc.BlockNumber) (*types.Header,
 		}
 	} else if *number == rpc.EarliestBlockNumber {
 		return nil, errUnknownBlock
+	} else if *number == rpc.LatestEpochBlockNumber {
+		header, err := headerByNumber(api.chain, number)
+		if err != nil {
+			return nil, err
+		}
+		if header.Number.Uint64() == 0 {
+			return nil, errUnknownBlock
+		}
+		parent = header.Number.Uint64() - 1
 	} else {
 		parent = uint64(*number - 1)
 	}
@@ -92,6 +109,8 @@ func (api *API) GetSnapshot(number *rpc.BlockNumber) (*Snapshot, error) {
 	var header *types.Header
 	if number == nil || *number == rpc.LatestBlockNumber {
 		header = api.chain.CurrentHeader()
+	} else if *number == rpc.LatestEpochBlockNumber {
+		header, _ = headerByNumber(api.chain, number)
 	} else {
 		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
 	}
@@ -46,6 +46,7 @@ import (
 	"github.com/celo-org/celo-blockchain/core"
 	"github.com/celo-org/celo-blockchain/core/state"
 	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/core/vm"
 	blscrypto "github.com/celo-org/celo-blockchain/crypto/bls"
 	"github.com/celo-org/celo-blockchain/ethdb"
 	"github.com/celo-org/celo-blockchain/event"
@@ -346,7 +347,8 @@ type Backend struct {
 	uptimeMonitor uptime.Builder
 
 	// Test hooks
-	abortCommitHook func(result *istanbulCore.StateProcessResult) bool // Method to call upon committing a proposal
+	abortCommitHook            func(result *istanbulCore.StateProcessResult) bool                                                   // Method to call upon committing a proposal
+	distributeEpochRewardsHook func(header *types.Header, state *state.StateDB, vmRunner vm.EVMRunner) (*types.EpochRewards, error) // Method to call instead of distributing the epoch rewards
 }
 
 func (sb *Backend) isCoreStarted() bool {
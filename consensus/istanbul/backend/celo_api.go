@@ -0,0 +1,414 @@
+// Copyright 2017 The celo Authors
+// This file is part of the celo library.
+//
+// The celo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The celo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the celo library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/common/hexutil"
+	"github.com/celo-org/celo-blockchain/consensus"
+	"github.com/celo-org/celo-blockchain/consensus/istanbul"
+	"github.com/celo-org/celo-blockchain/contracts/election"
+	"github.com/celo-org/celo-blockchain/contracts/random"
+	"github.com/celo-org/celo-blockchain/contracts/validators"
+	"github.com/celo-org/celo-blockchain/core/rawdb"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/core/vm"
+	blscrypto "github.com/celo-org/celo-blockchain/crypto/bls"
+	"github.com/celo-org/celo-blockchain/rpc"
+)
+
+// errEpochRewardsNotRecorded is returned when the rewards breakdown of an epoch was
+// not recorded by this node, e.g. because its last block was not executed locally.
+var errEpochRewardsNotRecorded = errors.New("epoch rewards not recorded")
+
+// CeloAPI is a user facing RPC API exposing Celo specific protocol state
+// (elections, epochs, rewards) computed by the istanbul backend.
+type CeloAPI struct {
+	chain    consensus.ChainHeaderReader
+	istanbul *Backend
+}
+
+// ElectedValidator is a validator signer chosen by the election together with
+// the group it was elected through.
+type ElectedValidator struct {
+	Address    common.Address `json:"address"`
+	Group      common.Address `json:"group"`
+	GroupVotes *hexutil.Big   `json:"groupVotes"`
+}
+
+// NextEpochElection is the result of running the validator election against
+// the state of a given block.
+type NextEpochElection struct {
+	BlockNumber hexutil.Uint64     `json:"blockNumber"`
+	Epoch       hexutil.Uint64     `json:"epoch"`
+	Elected     []ElectedValidator `json:"elected"`
+	Added       []common.Address   `json:"added"`
+	Removed     []common.Address   `json:"removed"`
+}
+
+// GetNextEpochElection runs the validator election against the state of the given
+// block and returns the signers that would be elected for the next epoch, along
+// with their diff against the validator set currently in charge.
+func (api *CeloAPI) GetNextEpochElection(number *rpc.BlockNumber) (*NextEpochElection, error) {
+	header, err := headerByNumber(api.chain, number)
+	if err != nil {
+		return nil, err
+	}
+	state, err := api.istanbul.stateAt(header.Hash())
+	if err != nil {
+		return nil, err
+	}
+	vmRunner := api.istanbul.chain.NewEVMRunner(header, state)
+	current := istanbul.MapValidatorsToAddresses(api.istanbul.GetValidators(header.Number, header.Hash()))
+	return nextEpochElection(vmRunner, header.Number.Uint64(), api.istanbul.EpochSize(), current)
+}
+
+// nextEpochElection runs the validator election through vmRunner, and diffs the
+// elected signers against the current validator set.
+func nextEpochElection(vmRunner vm.EVMRunner, number, epochSize uint64, current []common.Address) (*NextEpochElection, error) {
+	electedSigners, err := election.ElectNValidatorSigners(vmRunner, 0)
+	if err != nil {
+		return nil, err
+	}
+	groupVotes, err := election.GetGroupVoteTotals(vmRunner)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NextEpochElection{
+		BlockNumber: hexutil.Uint64(number),
+		Epoch:       hexutil.Uint64(istanbul.GetEpochNumber(number, epochSize) + 1),
+		Elected:     make([]ElectedValidator, 0, len(electedSigners)),
+		Added:       []common.Address{},
+		Removed:     []common.Address{},
+	}
+	elected := make(map[common.Address]bool, len(electedSigners))
+	for _, signer := range electedSigners {
+		group, err := validators.GetAffiliationFromSigner(vmRunner, signer)
+		if err != nil {
+			return nil, err
+		}
+		elected[signer] = true
+		result.Elected = append(result.Elected, ElectedValidator{
+			Address:    signer,
+			Group:      group,
+			GroupVotes: (*hexutil.Big)(groupVotes[group]),
+		})
+	}
+
+	inCurrent := make(map[common.Address]bool, len(current))
+	for _, val := range current {
+		inCurrent[val] = true
+		if !elected[val] {
+			result.Removed = append(result.Removed, val)
+		}
+	}
+	for _, signer := range electedSigners {
+		if !inCurrent[signer] {
+			result.Added = append(result.Added, signer)
+		}
+	}
+	return result, nil
+}
+
+// ValidatorEpochReward is the epoch payment of a single validator, in cUSD.
+type ValidatorEpochReward struct {
+	Validator common.Address `json:"validator"`
+	Group     common.Address `json:"group"`
+	Reward    *hexutil.Big   `json:"reward"`
+}
+
+// GroupEpochReward aggregates the rewards earned through a validator group.
+type GroupEpochReward struct {
+	Group            common.Address `json:"group"`
+	ValidatorRewards *hexutil.Big   `json:"validatorRewards"`
+	VoterRewards     *hexutil.Big   `json:"voterRewards"`
+}
+
+// EpochRewards is the breakdown of the rewards distributed at the end of an epoch.
+type EpochRewards struct {
+	Epoch       hexutil.Uint64 `json:"epoch"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+
+	TargetValidatorReward        *hexutil.Big `json:"targetValidatorReward"`
+	TargetVoterRewards           *hexutil.Big `json:"targetVoterRewards"`
+	TargetCommunityReward        *hexutil.Big `json:"targetCommunityReward"`
+	TargetCarbonOffsettingReward *hexutil.Big `json:"targetCarbonOffsettingReward"`
+
+	Validators                  []ValidatorEpochReward `json:"validators"`
+	Groups                      []GroupEpochReward     `json:"groups"`
+	TotalValidatorRewards       *hexutil.Big           `json:"totalValidatorRewards"`
+	TotalValidatorRewardsInCelo *hexutil.Big           `json:"totalValidatorRewardsInCelo"`
+	TotalVoterRewards           *hexutil.Big           `json:"totalVoterRewards"`
+
+	CommunityRecipient      common.Address `json:"communityRecipient"`
+	CommunityReward         *hexutil.Big   `json:"communityReward"`
+	CarbonOffsettingPartner common.Address `json:"carbonOffsettingPartner"`
+	CarbonOffsettingReward  *hexutil.Big   `json:"carbonOffsettingReward"`
+	ReserveFallback         bool           `json:"reserveFallback"`
+}
+
+// GetEpochRewards returns the rewards distributed while finalizing the last block
+// of the given epoch. The breakdown is only available for epochs whose last block
+// was executed by this node.
+func (api *CeloAPI) GetEpochRewards(epoch hexutil.Uint64) (*EpochRewards, error) {
+	if epoch == 0 {
+		return nil, errors.New("no rewards are distributed in epoch 0")
+	}
+	header := api.chain.GetHeaderByNumber(istanbul.GetEpochLastBlockNumber(uint64(epoch), api.istanbul.EpochSize()))
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	rewards := rawdb.ReadEpochRewards(api.istanbul.db, uint64(epoch), header.Root)
+	if rewards == nil {
+		return nil, errEpochRewardsNotRecorded
+	}
+	return newRPCEpochRewards(header, rewards), nil
+}
+
+func newRPCEpochRewards(header *types.Header, rewards *types.EpochRewards) *EpochRewards {
+	result := &EpochRewards{
+		Epoch:                        hexutil.Uint64(rewards.Epoch),
+		BlockNumber:                  hexutil.Uint64(header.Number.Uint64()),
+		BlockHash:                    header.Hash(),
+		TargetValidatorReward:        (*hexutil.Big)(rewards.TargetValidatorReward),
+		TargetVoterRewards:           (*hexutil.Big)(rewards.TargetVoterRewards),
+		TargetCommunityReward:        (*hexutil.Big)(rewards.TargetCommunityReward),
+		TargetCarbonOffsettingReward: (*hexutil.Big)(rewards.TargetCarbonOffsettingReward),
+		Validators:                   make([]ValidatorEpochReward, 0, len(rewards.Validators)),
+		Groups:                       make([]GroupEpochReward, 0, len(rewards.Groups)),
+		TotalValidatorRewards:        (*hexutil.Big)(rewards.TotalValidatorRewards),
+		TotalValidatorRewardsInCelo:  (*hexutil.Big)(rewards.TotalValidatorRewardsInCelo),
+		TotalVoterRewards:            (*hexutil.Big)(rewards.TotalVoterRewards),
+		CommunityRecipient:           rewards.CommunityRecipient,
+		CommunityReward:              (*hexutil.Big)(rewards.CommunityReward),
+		CarbonOffsettingPartner:      rewards.CarbonOffsettingPartner,
+		CarbonOffsettingReward:       (*hexutil.Big)(rewards.CarbonOffsettingReward),
+		ReserveFallback:              rewards.ReserveFallback,
+	}
+	for _, val := range rewards.Validators {
+		result.Validators = append(result.Validators, ValidatorEpochReward{
+			Validator: val.Validator,
+			Group:     val.Group,
+			Reward:    (*hexutil.Big)(val.Reward),
+		})
+	}
+	for _, group := range rewards.Groups {
+		result.Groups = append(result.Groups, GroupEpochReward{
+			Group:            group.Group,
+			ValidatorRewards: (*hexutil.Big)(group.ValidatorRewards),
+			VoterRewards:     (*hexutil.Big)(group.VoterRewards),
+		})
+	}
+	return result
+}
+
+// blockReader is implemented by chains able to serve full blocks, such as
+// core.BlockChain.
+type blockReader interface {
+	GetBlock(hash common.Hash, number uint64) *types.Block
+}
+
+// EpochOrBlock identifies an epoch either directly by its number, or through a
+// block it contains, given as a block tag, number or hash (e.g. "latest" or
+// {"blockNumber": "0x10"}).
+type EpochOrBlock struct {
+	Epoch *hexutil.Uint64
+	Block *rpc.BlockNumberOrHash
+}
+
+// UnmarshalJSON parses a plain quantity as an epoch number and anything else as
+// an rpc.BlockNumberOrHash.
+func (e *EpochOrBlock) UnmarshalJSON(data []byte) error {
+	var epoch hexutil.Uint64
+	if err := json.Unmarshal(data, &epoch); err == nil {
+		e.Epoch = &epoch
+		return nil
+	}
+	var block rpc.BlockNumberOrHash
+	if err := json.Unmarshal(data, &block); err != nil {
+		return err
+	}
+	e.Block = &block
+	return nil
+}
+
+// EpochInfo describes the boundaries and the validator set of an epoch.
+type EpochInfo struct {
+	Epoch                   hexutil.Uint64                  `json:"epoch"`
+	FirstBlock              hexutil.Uint64                  `json:"firstBlock"`
+	LastBlock               hexutil.Uint64                  `json:"lastBlock"`
+	Validators              []common.Address                `json:"validators"`
+	ValidatorsBLSPublicKeys []blscrypto.SerializedPublicKey `json:"validatorsBLSPublicKeys"`
+	// The fields below are only set once the last block of the epoch is known.
+	EpochValidatorSetSeal *types.EpochSnarkData `json:"epochValidatorSetSeal"`
+	Randomness            *types.Randomness     `json:"randomness"`
+}
+
+// epochNumber resolves the epoch referred to by epochOrBlock.
+func (api *CeloAPI) epochNumber(epochOrBlock EpochOrBlock) (uint64, error) {
+	if epochOrBlock.Epoch != nil {
+		return uint64(*epochOrBlock.Epoch), nil
+	}
+	var header *types.Header
+	if epochOrBlock.Block == nil {
+		header = api.chain.CurrentHeader()
+	} else if number, ok := epochOrBlock.Block.Number(); ok {
+		var err error
+		if header, err = headerByNumber(api.chain, &number); err != nil {
+			return 0, err
+		}
+	} else if hash, ok := epochOrBlock.Block.Hash(); ok {
+		header = api.chain.GetHeaderByHash(hash)
+	}
+	if header == nil {
+		return 0, errUnknownBlock
+	}
+	return istanbul.GetEpochNumber(header.Number.Uint64(), api.istanbul.EpochSize()), nil
+}
+
+// GetEpochInfo returns the boundaries of the given epoch, the validator set in
+// charge during it, and the epoch validator set seal and randomness of its last
+// block. The epoch may be given by number or by any block it contains.
+func (api *CeloAPI) GetEpochInfo(epochOrBlock EpochOrBlock) (*EpochInfo, error) {
+	epoch, err := api.epochNumber(epochOrBlock)
+	if err != nil {
+		return nil, err
+	}
+	epochSize := api.istanbul.EpochSize()
+	info := &EpochInfo{
+		Epoch:     hexutil.Uint64(epoch),
+		LastBlock: hexutil.Uint64(istanbul.GetEpochLastBlockNumber(epoch, epochSize)),
+	}
+	// The validator set of an epoch is the one elected at the end of the previous
+	// one, or the genesis validators for epoch 0 and 1.
+	var validatorsFrom uint64
+	if epoch > 0 {
+		firstBlock, err := istanbul.GetEpochFirstBlockNumber(epoch, epochSize)
+		if err != nil {
+			return nil, err
+		}
+		info.FirstBlock = hexutil.Uint64(firstBlock)
+		validatorsFrom = firstBlock - 1
+	}
+	validatorsHeader := api.chain.GetHeaderByNumber(validatorsFrom)
+	if validatorsHeader == nil {
+		return nil, errUnknownBlock
+	}
+	validators := api.istanbul.GetValidators(validatorsHeader.Number, validatorsHeader.Hash())
+	info.Validators = istanbul.MapValidatorsToAddresses(validators)
+	info.ValidatorsBLSPublicKeys = istanbul.MapValidatorsToPublicKeys(validators)
+
+	if lastHeader := api.chain.GetHeaderByNumber(uint64(info.LastBlock)); lastHeader != nil {
+		if chain, ok := api.chain.(blockReader); ok {
+			if block := chain.GetBlock(lastHeader.Hash(), lastHeader.Number.Uint64()); block != nil {
+				info.EpochValidatorSetSeal = block.EpochSnarkData()
+				info.Randomness = block.Randomness()
+			}
+		}
+	}
+	return info, nil
+}
+
+// BlockRandomness exposes the random beacon values of a block, together with the
+// data needed to audit the proposer's reveal against its previous commitment.
+type BlockRandomness struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	Proposer    common.Address `json:"proposer"`
+	// Randomness is the beacon value after applying the block.
+	Randomness common.Hash `json:"randomness"`
+	Revealed   common.Hash `json:"revealed"`
+	Committed  common.Hash `json:"committed"`
+	// ParentCommitment is the proposer's commitment before this block, which the
+	// revealed value has to open.
+	ParentCommitment common.Hash `json:"parentCommitment"`
+	// ParentCommitmentBlockHash is the parent hash of the block in which
+	// ParentCommitment was made, only known if this node made the commitment.
+	ParentCommitmentBlockHash *common.Hash `json:"parentCommitmentBlockHash"`
+	RevealMatchesCommitment   bool         `json:"revealMatchesCommitment"`
+}
+
+// GetRandomness returns the random beacon values of the given block.
+func (api *CeloAPI) GetRandomness(number *rpc.BlockNumber) (*BlockRandomness, error) {
+	header, err := headerByNumber(api.chain, number)
+	if err != nil {
+		return nil, err
+	}
+	if header.Number.Uint64() == 0 {
+		return nil, errors.New("genesis block has no randomness")
+	}
+	chain, ok := api.chain.(blockReader)
+	if !ok {
+		return nil, errors.New("chain does not serve block bodies")
+	}
+	block := chain.GetBlock(header.Hash(), header.Number.Uint64())
+	if block == nil || block.Randomness() == nil {
+		return nil, errUnknownBlock
+	}
+	proposer, err := api.istanbul.Author(header)
+	if err != nil {
+		return nil, err
+	}
+	result := &BlockRandomness{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		BlockHash:   header.Hash(),
+		Proposer:    proposer,
+		Revealed:    block.Randomness().Revealed,
+		Committed:   block.Randomness().Committed,
+	}
+
+	state, err := api.istanbul.stateAt(header.Hash())
+	if err != nil {
+		return nil, err
+	}
+	vmRunner := api.istanbul.chain.NewEVMRunner(header, state)
+	if !random.IsRunning(vmRunner) {
+		return result, nil
+	}
+	if result.Randomness, err = random.Random(vmRunner); err != nil {
+		return nil, err
+	}
+
+	parent := api.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return nil, errUnknownBlock
+	}
+	parentState, err := api.istanbul.stateAt(parent.Hash())
+	if err != nil {
+		return nil, err
+	}
+	parentVMRunner := api.istanbul.chain.NewEVMRunner(parent, parentState)
+	if result.ParentCommitment, err = random.GetLastCommitment(parentVMRunner, proposer); err != nil {
+		return nil, err
+	}
+	if (result.ParentCommitment != common.Hash{}) {
+		if parentHash := rawdb.ReadRandomCommitmentCache(api.istanbul.db, result.ParentCommitment); (parentHash != common.Hash{}) {
+			result.ParentCommitmentBlockHash = &parentHash
+		}
+		commitment, err := random.ComputeCommitment(parentVMRunner, result.Revealed)
+		if err != nil {
+			return nil, err
+		}
+		result.RevealMatchesCommitment = commitment == result.ParentCommitment
+	}
+	return result, nil
+}
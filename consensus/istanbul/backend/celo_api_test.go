@@ -0,0 +1,218 @@
+package backend
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/common/hexutil"
+	"github.com/celo-org/celo-blockchain/consensus/istanbul"
+	"github.com/celo-org/celo-blockchain/contracts/config"
+	"github.com/celo-org/celo-blockchain/contracts/testutil"
+	"github.com/celo-org/celo-blockchain/core/rawdb"
+	"github.com/celo-org/celo-blockchain/core/state"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/core/vm"
+	"github.com/celo-org/celo-blockchain/rpc"
+	. "github.com/onsi/gomega"
+)
+
+func TestEpochOrBlockUnmarshal(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var e EpochOrBlock
+	g.Expect(json.Unmarshal([]byte(`"0x3"`), &e)).To(Succeed())
+	g.Expect(e.Block).To(BeNil())
+	g.Expect(*e.Epoch).To(Equal(hexutil.Uint64(3)))
+
+	e = EpochOrBlock{}
+	g.Expect(json.Unmarshal([]byte(`"latestEpoch"`), &e)).To(Succeed())
+	g.Expect(e.Epoch).To(BeNil())
+	number, ok := e.Block.Number()
+	g.Expect(ok).To(BeTrue())
+	g.Expect(number).To(Equal(rpc.LatestEpochBlockNumber))
+
+	e = EpochOrBlock{}
+	g.Expect(json.Unmarshal([]byte(`{"blockNumber":"0x10"}`), &e)).To(Succeed())
+	number, ok = e.Block.Number()
+	g.Expect(ok).To(BeTrue())
+	g.Expect(number).To(Equal(rpc.BlockNumber(16)))
+
+	g.Expect(json.Unmarshal([]byte(`"someString"`), &EpochOrBlock{})).NotTo(Succeed())
+}
+
+func TestGetEpochInfo(t *testing.T) {
+	g := NewGomegaWithT(t)
+	chain, engine := newBlockChain(4, true)
+	defer stopEngine(engine)
+	defer chain.Stop()
+	api := &CeloAPI{chain: chain, istanbul: engine}
+
+	genesisValidators := istanbul.MapValidatorsToAddresses(engine.GetValidators(common.Big0, chain.Genesis().Hash()))
+
+	epoch := hexutil.Uint64(1)
+	info, err := api.GetEpochInfo(EpochOrBlock{Epoch: &epoch})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(info.Epoch).To(Equal(epoch))
+	g.Expect(info.FirstBlock).To(Equal(hexutil.Uint64(1)))
+	g.Expect(info.LastBlock).To(Equal(hexutil.Uint64(engine.EpochSize())))
+	g.Expect(info.Validators).To(Equal(genesisValidators))
+	g.Expect(info.ValidatorsBLSPublicKeys).To(HaveLen(len(genesisValidators)))
+	// The last block of the epoch has not been produced yet
+	g.Expect(info.EpochValidatorSetSeal).To(BeNil())
+	g.Expect(info.Randomness).To(BeNil())
+
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	info, err = api.GetEpochInfo(EpochOrBlock{Block: &latest})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(info.Epoch).To(Equal(hexutil.Uint64(0)))
+	g.Expect(info.LastBlock).To(Equal(hexutil.Uint64(0)))
+	g.Expect(info.Randomness).NotTo(BeNil())
+}
+
+func TestGetRandomness(t *testing.T) {
+	g := NewGomegaWithT(t)
+	chain, engine := newBlockChain(1, true)
+	defer stopEngine(engine)
+	defer chain.Stop()
+	api := &CeloAPI{chain: chain, istanbul: engine}
+
+	earliest := rpc.EarliestBlockNumber
+	_, err := api.GetRandomness(&earliest)
+	g.Expect(err).To(HaveOccurred())
+
+	block, err := makeBlock(nil, chain, engine, chain.Genesis())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	latest := rpc.LatestBlockNumber
+	randomness, err := api.GetRandomness(&latest)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(randomness.BlockHash).To(Equal(block.Hash()))
+	g.Expect(randomness.Proposer).To(Equal(engine.Address()))
+	g.Expect(randomness.Revealed).To(Equal(block.Randomness().Revealed))
+	g.Expect(randomness.Committed).To(Equal(block.Randomness().Committed))
+}
+
+func TestGetEpochRewards(t *testing.T) {
+	g := NewGomegaWithT(t)
+	chain, engine := newBlockChain(1, true)
+	defer stopEngine(engine)
+	defer chain.Stop()
+	api := &CeloAPI{chain: chain, istanbul: engine}
+	// Don't wait between blocks to reach the end of the epoch
+	engine.config.BlockPeriod = 0
+
+	var (
+		validator = engine.Address()
+		group     = common.HexToAddress("0x61")
+		recipient = common.HexToAddress("0x62")
+	)
+	// The test genesis has no core contracts to distribute rewards with
+	engine.distributeEpochRewardsHook = func(header *types.Header, state *state.StateDB, vmRunner vm.EVMRunner) (*types.EpochRewards, error) {
+		return &types.EpochRewards{
+			Epoch:                 istanbul.GetEpochNumber(header.Number.Uint64(), engine.EpochSize()),
+			TargetValidatorReward: big.NewInt(100),
+			Validators:            []types.ValidatorEpochReward{{Validator: validator, Group: group, Reward: big.NewInt(90)}},
+			Groups:                []types.GroupEpochReward{{Group: group, ValidatorRewards: big.NewInt(90), VoterRewards: big.NewInt(30)}},
+			TotalValidatorRewards: big.NewInt(90),
+			TotalVoterRewards:     big.NewInt(30),
+			CommunityRecipient:    recipient,
+			CommunityReward:       big.NewInt(7),
+		}, nil
+	}
+
+	_, err := api.GetEpochRewards(1)
+	g.Expect(err).To(MatchError(errUnknownBlock))
+
+	block := chain.Genesis()
+	for block.NumberU64() < engine.EpochSize()-1 {
+		block, err = makeBlock(nil, chain, engine, block)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	// Assembling a candidate for the last block of the epoch doesn't record its
+	// rewards
+	header := makeHeader(block, engine.config)
+	header.Coinbase = validator
+	g.Expect(engine.Prepare(chain, header)).To(Succeed())
+	state, err := chain.StateAt(block.Root())
+	g.Expect(err).NotTo(HaveOccurred())
+	candidate, err := engine.FinalizeAndAssemble(chain, header, state, nil, nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rawdb.ReadEpochRewards(engine.db, 1, candidate.Root())).To(BeNil())
+
+	// Importing it does
+	block, err = makeBlock(nil, chain, engine, block)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(engine.IsLastBlockOfEpoch(block.Header())).To(BeTrue())
+
+	rewards, err := api.GetEpochRewards(1)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rewards.Epoch).To(Equal(hexutil.Uint64(1)))
+	g.Expect(rewards.BlockNumber).To(Equal(hexutil.Uint64(block.NumberU64())))
+	g.Expect(rewards.BlockHash).To(Equal(block.Hash()))
+	g.Expect(rewards.TargetValidatorReward).To(Equal((*hexutil.Big)(big.NewInt(100))))
+	g.Expect(rewards.Validators).To(Equal([]ValidatorEpochReward{{Validator: validator, Group: group, Reward: (*hexutil.Big)(big.NewInt(90))}}))
+	g.Expect(rewards.Groups).To(Equal([]GroupEpochReward{{Group: group, ValidatorRewards: (*hexutil.Big)(big.NewInt(90)), VoterRewards: (*hexutil.Big)(big.NewInt(30))}}))
+	g.Expect(rewards.TotalVoterRewards).To(Equal((*hexutil.Big)(big.NewInt(30))))
+	g.Expect(rewards.CommunityRecipient).To(Equal(recipient))
+	g.Expect(rewards.CommunityReward).To(Equal((*hexutil.Big)(big.NewInt(7))))
+
+	// Epoch 2 hasn't ended yet
+	_, err = api.GetEpochRewards(2)
+	g.Expect(err).To(MatchError(errUnknownBlock))
+	_, err = api.GetEpochRewards(0)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestGetNextEpochElection(t *testing.T) {
+	g := NewGomegaWithT(t)
+	chain, engine := newBlockChain(1, true)
+	defer stopEngine(engine)
+	defer chain.Stop()
+	api := &CeloAPI{chain: chain, istanbul: engine}
+
+	// The test genesis has no election contract
+	latest := rpc.LatestBlockNumber
+	_, err := api.GetNextEpochElection(&latest)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestNextEpochElection(t *testing.T) {
+	g := NewGomegaWithT(t)
+	var (
+		kept, added, removed = common.HexToAddress("0x51"), common.HexToAddress("0x52"), common.HexToAddress("0x53")
+		groupA, groupB       = common.HexToAddress("0x61"), common.HexToAddress("0x62")
+	)
+	runner := testutil.NewMockEVMRunner()
+	registry := testutil.NewRegistryMock()
+	runner.RegisterContract(config.RegistrySmartContractAddress, registry)
+
+	electionMock := testutil.NewElectionMock()
+	electionMock.Elected = []common.Address{kept, added}
+	electionMock.Groups = []common.Address{groupA, groupB}
+	electionMock.GroupVotes = []*big.Int{big.NewInt(100), big.NewInt(50)}
+	registry.AddContract(config.ElectionRegistryId, common.HexToAddress("0x10"))
+	runner.RegisterContract(common.HexToAddress("0x10"), electionMock)
+
+	registry.AddContract(config.AccountsRegistryId, common.HexToAddress("0x11"))
+	runner.RegisterContract(common.HexToAddress("0x11"), testutil.NewAccountsMock())
+
+	validatorsMock := testutil.NewValidatorsMock()
+	validatorsMock.Affiliations[kept] = groupA
+	validatorsMock.Affiliations[added] = groupB
+	registry.AddContract(config.ValidatorsRegistryId, common.HexToAddress("0x12"))
+	runner.RegisterContract(common.HexToAddress("0x12"), validatorsMock)
+
+	result, err := nextEpochElection(runner, 150, 100, []common.Address{kept, removed})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.BlockNumber).To(Equal(hexutil.Uint64(150)))
+	g.Expect(result.Epoch).To(Equal(hexutil.Uint64(3)))
+	g.Expect(result.Elected).To(Equal([]ElectedValidator{
+		{Address: kept, Group: groupA, GroupVotes: (*hexutil.Big)(big.NewInt(100))},
+		{Address: added, Group: groupB, GroupVotes: (*hexutil.Big)(big.NewInt(50))},
+	}))
+	g.Expect(result.Added).To(Equal([]common.Address{added}))
+	g.Expect(result.Removed).To(Equal([]common.Address{removed}))
+}
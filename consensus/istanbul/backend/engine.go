@@ -27,6 +27,7 @@ import (
 	"github.com/celo-org/celo-blockchain/consensus"
 	"github.com/celo-org/celo-blockchain/consensus/istanbul"
 	istanbulCore "github.com/celo-org/celo-blockchain/consensus/istanbul/core"
+	"github.com/celo-org/celo-blockchain/consensus/istanbul/epochproof"
 	"github.com/celo-org/celo-blockchain/consensus/istanbul/uptime"
 	"github.com/celo-org/celo-blockchain/consensus/istanbul/validator"
 	"github.com/celo-org/celo-blockchain/contracts/blockchain_parameters"
@@ -34,10 +35,13 @@ import (
 	"github.com/celo-org/celo-blockchain/contracts/gold_token"
 	"github.com/celo-org/celo-blockchain/core"
 	ethCore "github.com/celo-org/celo-blockchain/core"
+	"github.com/celo-org/celo-blockchain/core/rawdb"
 	"github.com/celo-org/celo-blockchain/core/state"
 	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/core/vm"
 	blscrypto "github.com/celo-org/celo-blockchain/crypto/bls"
 	"github.com/celo-org/celo-blockchain/log"
+	"github.com/celo-org/celo-blockchain/params"
 	"github.com/celo-org/celo-blockchain/rlp"
 	"github.com/celo-org/celo-blockchain/rpc"
 	"github.com/celo-org/celo-blockchain/trie"
@@ -50,6 +54,8 @@ const (
 	inmemoryPeers                 = 40
 	inmemoryMessages              = 1024
 	mobileAllowedClockSkew uint64 = 5
+	sealBatchSize                 = 128 // Number of headers whose seal signatures are verified together
+	minSealBatchSize              = 16  // Fewer headers than this are verified one by one
 )
 
 var (
@@ -57,10 +63,10 @@ var (
 	errInvalidProposal = errors.New("invalid proposal")
 	// errInvalidSignature is returned when given signature is not signed by given
 	// address.
-	errInvalidSignature = errors.New("invalid signature")
+	errInvalidSignature = epochproof.ErrInvalidSignature
 	// errInsufficientSeals is returned when there is not enough signatures to
 	// pass the quorum check.
-	errInsufficientSeals = errors.New("not enough seals to reach quorum")
+	errInsufficientSeals = epochproof.ErrInsufficientSeals
 	// errUnknownBlock is returned when the list of validators or header is requested for a block
 	// that is not part of the local blockchain.
 	errUnknownBlock = errors.New("unknown block")
@@ -76,7 +82,7 @@ var (
 	// be modified via out-of-range or non-contiguous headers.
 	errInvalidVotingChain = errors.New("invalid voting chain")
 	// errInvalidAggregatedSeal is returned if the aggregated seal is invalid.
-	errInvalidAggregatedSeal = errors.New("invalid aggregated seal")
+	errInvalidAggregatedSeal = epochproof.ErrInvalidAggregatedSeal
 	// errEmptyAggregatedSeal is returned if the aggregated seal is missing.
 	errEmptyAggregatedSeal = errors.New("empty aggregated seal")
 	// errNonEmptyAggregatedSeal is returned if the aggregated seal is not empty during preprepase proposal phase.
@@ -87,6 +93,9 @@ var (
 	errInvalidValidatorSetDiff = errors.New("invalid validator set diff")
 	// errNotAValidator is returned when the node is not configured as a validator
 	errNotAValidator = errors.New("Not configured as a validator")
+	// errTrustedEpochMismatch is returned if a header at the trusted epoch block
+	// number does not have the trusted hash.
+	errTrustedEpochMismatch = errors.New("header does not match the trusted epoch")
 )
 
 var (
@@ -106,13 +115,13 @@ func (sb *Backend) Author(header *types.Header) (common.Address, error) {
 // VerifyHeader checks whether a header conforms to the consensus rules of a
 // given engine. Verifies the seal regardless of given "seal" argument.
 func (sb *Backend) VerifyHeader(chain consensus.ChainHeaderReader, header *types.Header, seal bool) error {
-	return sb.verifyHeader(chain, header, false, nil)
+	return sb.verifyHeader(chain, header, false, nil, nil)
 }
 
 // verifyHeaderFromProposal checks whether a header conforms to the consensus rules from the
 // preprepare istanbul phase.
 func (sb *Backend) verifyHeaderFromProposal(chain consensus.ChainHeaderReader, header *types.Header) error {
-	return sb.verifyHeader(chain, header, true, nil)
+	return sb.verifyHeader(chain, header, true, nil, nil)
 }
 
 // verifyHeader checks whether a header conforms to the consensus rules.The
@@ -121,11 +130,24 @@ func (sb *Backend) verifyHeaderFromProposal(chain consensus.ChainHeaderReader, h
 // a batch of new headers.
 // If emptyAggregatedSeal is set, the aggregatedSeal will be checked to be completely empty. Otherwise
 // it will be checked as a normal aggregated seal.
-func (sb *Backend) verifyHeader(chain consensus.ChainHeaderReader, header *types.Header, emptyAggregatedSeal bool, parents []*types.Header) error {
+// If batch is set, the signatures of the aggregated seals are queued in it instead of being verified.
+func (sb *Backend) verifyHeader(chain consensus.ChainHeaderReader, header *types.Header, emptyAggregatedSeal bool, parents []*types.Header, batch *epochproof.SealBatch) error {
 	if header.Number == nil {
 		return errUnknownBlock
 	}
 
+	// The trusted epoch header is the root of the validator set chain when the
+	// full header chain isn't available, so there is nothing to verify it against.
+	// Any other chain must simply agree with it.
+	if trusted := sb.config.TrustedEpoch; trusted != nil && header.Number.Uint64() == trusted.BlockNumber(sb.config.Epoch) {
+		if header.Hash() != trusted.Hash {
+			return errTrustedEpochMismatch
+		}
+		if !chain.Config().FullHeaderChainAvailable {
+			return nil
+		}
+	}
+
 	// If the full chain isn't available (as on mobile devices), don't reject future blocks
 	// This is due to potential clock skew
 	allowedFutureBlockTime := uint64(now().Unix())
@@ -143,7 +165,7 @@ func (sb *Backend) verifyHeader(chain consensus.ChainHeaderReader, header *types
 		return errInvalidExtraDataFormat
 	}
 
-	return sb.verifyCascadingFields(chain, header, emptyAggregatedSeal, parents)
+	return sb.verifyCascadingFields(chain, header, emptyAggregatedSeal, parents, batch)
 }
 
 // A sanity check for lightest mode. Checks that the correct epoch block exists for this header
@@ -173,7 +195,7 @@ func (sb *Backend) checkEpochBlockExists(chain consensus.ChainHeaderReader, head
 // database. This is useful for concurrently verifying a batch of new headers.
 // If emptyAggregatedSeal is set, the aggregatedSeal will be checked to be completely empty. Otherwise
 // it will be checked as a normal aggregated seal.
-func (sb *Backend) verifyCascadingFields(chain consensus.ChainHeaderReader, header *types.Header, emptyAggregatedSeal bool, parents []*types.Header) error {
+func (sb *Backend) verifyCascadingFields(chain consensus.ChainHeaderReader, header *types.Header, emptyAggregatedSeal bool, parents []*types.Header, batch *epochproof.SealBatch) error {
 	// The genesis block is the always valid dead-end
 	number := header.Number.Uint64()
 	if number == 0 {
@@ -246,40 +268,122 @@ func (sb *Backend) verifyCascadingFields(chain consensus.ChainHeaderReader, head
 		return err
 	}
 
-	return sb.verifyAggregatedSeals(chain, header, emptyAggregatedSeal, parents)
+	return sb.verifyAggregatedSeals(chain, header, emptyAggregatedSeal, parents, batch)
 }
 
 // VerifyHeaders is similar to VerifyHeader, but verifies a batch of headers
 // concurrently. The method returns a quit channel to abort the operations and
 // a results channel to retrieve the async verifications (the order is that of
 // the input slice).
+// When enough headers are given, as during sync, the signatures of their
+// aggregated seals are verified together, sealBatchSize headers at a time.
+// When none of the seals is to be verified, as during fast sync, the headers
+// before the trusted epoch are accepted as they are: they are authenticated by
+// the trusted epoch header they lead to.
 func (sb *Backend) VerifyHeaders(chain consensus.ChainHeaderReader, headers []*types.Header, seals []bool) (chan<- struct{}, <-chan error) {
 	abort := make(chan struct{})
 	results := make(chan error, len(headers))
+	trusted := 0
+	if sb.config.TrustedEpoch != nil && chain.Config().FullHeaderChainAvailable && !anySeal(seals) {
+		number := sb.config.TrustedEpoch.BlockNumber(sb.config.Epoch)
+		for trusted < len(headers) && headers[trusted].Number.Uint64() < number {
+			trusted++
+		}
+	}
 	go func() {
+		for i := 0; i < trusted; i++ {
+			select {
+			case <-abort:
+				return
+			case results <- nil:
+			}
+		}
 		errored := false
-		for i, header := range headers {
-			var err error
-			if errored {
-				err = consensus.ErrUnknownAncestor
-			} else {
-				err = sb.verifyHeader(chain, header, false, headers[:i])
+		for start := trusted; start < len(headers); start += sealBatchSize {
+			end := start + sealBatchSize
+			if end > len(headers) {
+				end = len(headers)
 			}
-
-			if err != nil {
-				errored = true
+			var errs []error
+			if end-start >= minSealBatchSize && !errored {
+				errs = sb.verifyHeadersBatch(chain, headers, start, end)
 			}
+			for i := start; i < end; i++ {
+				var err error
+				switch {
+				case errored:
+					err = consensus.ErrUnknownAncestor
+				case errs != nil:
+					err = errs[i-start]
+				default:
+					err = sb.verifyHeader(chain, headers[i], false, headers[:i], nil)
+				}
+				if err != nil {
+					errored = true
+				}
 
-			select {
-			case <-abort:
-				return
-			case results <- err:
+				select {
+				case <-abort:
+					return
+				case results <- err:
+				}
 			}
 		}
 	}()
 	return abort, results
 }
 
+// anySeal reports whether any of the given seals is to be verified.
+func anySeal(seals []bool) bool {
+	for _, seal := range seals {
+		if seal {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyHeadersBatch verifies headers[start:end], checking the signatures of
+// all their aggregated seals at once. If a seal is invalid, the header it
+// belongs to fails with ErrInvalidSignature and all later ones with
+// ErrUnknownAncestor.
+func (sb *Backend) verifyHeadersBatch(chain consensus.ChainHeaderReader, headers []*types.Header, start, end int) []error {
+	var (
+		errored  = false
+		batch    = new(epochproof.SealBatch)
+		errs     = make([]error, end-start)
+		batchEnd = make([]int, end-start) // batch length after each header
+	)
+	for i := start; i < end; i++ {
+		if errored {
+			errs[i-start] = consensus.ErrUnknownAncestor
+		} else if err := sb.verifyHeader(chain, headers[i], false, headers[:i], batch); err != nil {
+			errs[i-start] = err
+			errored = true
+		}
+		batchEnd[i-start] = batch.Len()
+	}
+	bad, err := batch.Verify()
+	if err == nil {
+		return errs
+	}
+	for i := range errs {
+		if batchEnd[i] <= bad {
+			continue
+		}
+		// If the header owning the seal failed already, its error stands.
+		if errs[i] == nil {
+			sb.logger.Error("Unable to verify aggregated signature", "number", headers[start+i].Number, "err", err)
+			errs[i] = err
+			for j := i + 1; j < len(errs); j++ {
+				errs[j] = consensus.ErrUnknownAncestor
+			}
+		}
+		break
+	}
+	return errs
+}
+
 // verifySigner checks whether the signer is in parent's validator set
 func (sb *Backend) verifySigner(chain consensus.ChainHeaderReader, header *types.Header, parents []*types.Header) error {
 	// Verifying the genesis block is not supported
@@ -311,7 +415,7 @@ func (sb *Backend) verifySigner(chain consensus.ChainHeaderReader, header *types
 // signed on by the block's validators and the parent block's validators respectively
 // If emptyAggregatedSeal is set, the aggregatedSeal will be checked to be completely empty. Otherwise
 // it will be checked as a normal aggregated seal.
-func (sb *Backend) verifyAggregatedSeals(chain consensus.ChainHeaderReader, header *types.Header, emptyAggregatedseal bool, parents []*types.Header) error {
+func (sb *Backend) verifyAggregatedSeals(chain consensus.ChainHeaderReader, header *types.Header, emptyAggregatedseal bool, parents []*types.Header, batch *epochproof.SealBatch) error {
 	number := header.Number.Uint64()
 	// We don't need to verify committed seals in the genesis block
 	if number == 0 {
@@ -342,7 +446,7 @@ func (sb *Backend) verifyAggregatedSeals(chain consensus.ChainHeaderReader, head
 			return errEmptyAggregatedSeal
 		}
 
-		err = sb.verifyAggregatedSeal(header.Hash(), validators, extra.AggregatedSeal)
+		err = sb.checkAggregatedSeal(batch, header.Hash(), validators, extra.AggregatedSeal)
 		if err != nil {
 			return err
 		}
@@ -375,39 +479,31 @@ func (sb *Backend) verifyAggregatedSeals(chain consensus.ChainHeaderReader, head
 		// parent.Hash() would correspond to the previous epoch
 		// block in ultralight, while the extra.ParentCommit is made on the block which was
 		// immediately before the current block.
-		return sb.verifyAggregatedSeal(header.ParentHash, parentValidators, extra.ParentAggregatedSeal)
+		return sb.checkAggregatedSeal(batch, header.ParentHash, parentValidators, extra.ParentAggregatedSeal)
 	}
 
 	return nil
 }
 
-func (sb *Backend) verifyAggregatedSeal(headerHash common.Hash, validators istanbul.ValidatorSet, aggregatedSeal types.IstanbulAggregatedSeal) error {
-	logger := sb.logger.New("func", "Backend.verifyAggregatedSeal()")
-	if len(aggregatedSeal.Signature) != types.IstanbulExtraBlsSignature {
-		return errInvalidAggregatedSeal
+// checkAggregatedSeal verifies an aggregated seal, or only its format and quorum
+// if batch is set, leaving the signature to be verified with the rest of the batch.
+func (sb *Backend) checkAggregatedSeal(batch *epochproof.SealBatch, headerHash common.Hash, validators istanbul.ValidatorSet, aggregatedSeal types.IstanbulAggregatedSeal) error {
+	if batch == nil {
+		return sb.verifyAggregatedSeal(headerHash, validators, aggregatedSeal)
 	}
+	return batch.Add(headerHash, validators, aggregatedSeal)
+}
 
-	proposalSeal := istanbulCore.PrepareCommittedSeal(headerHash, aggregatedSeal.Round)
-	// Find which public keys signed from the provided validator set
-	publicKeys := []blscrypto.SerializedPublicKey{}
-	for i := 0; i < validators.Size(); i++ {
-		if aggregatedSeal.Bitmap.Bit(i) == 1 {
-			pubKey := validators.GetByIndex(uint64(i)).BLSPublicKey()
-			publicKeys = append(publicKeys, pubKey)
-		}
-	}
-	// The length of a valid seal should be greater than the minimum quorum size
-	if len(publicKeys) < validators.MinQuorumSize() {
-		logger.Error("Aggregated seal does not aggregate enough seals", "numSeals", len(publicKeys), "minimum quorum size", validators.MinQuorumSize())
-		return errInsufficientSeals
-	}
-	err := blscrypto.VerifyAggregatedSignature(publicKeys, proposalSeal, []byte{}, aggregatedSeal.Signature, false, false)
-	if err != nil {
+func (sb *Backend) verifyAggregatedSeal(headerHash common.Hash, validators istanbul.ValidatorSet, aggregatedSeal types.IstanbulAggregatedSeal) error {
+	logger := sb.logger.New("func", "Backend.verifyAggregatedSeal()")
+	err := epochproof.VerifyAggregatedSeal(headerHash, validators, aggregatedSeal)
+	switch err {
+	case errInsufficientSeals:
+		logger.Error("Aggregated seal does not aggregate enough seals", "minimum quorum size", validators.MinQuorumSize())
+	case errInvalidSignature:
 		logger.Error("Unable to verify aggregated signature", "err", err)
-		return errInvalidSignature
 	}
-
-	return nil
+	return err
 }
 
 // VerifySeal checks whether the crypto seal on a header is valid according to
@@ -531,6 +627,16 @@ func (sb *Backend) LookbackWindow(header *types.Header, state *state.StateDB) ui
 // Note: The block header and state database might be updated to reflect any
 // consensus rules that happen at finalization (e.g. block rewards).
 func (sb *Backend) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction) {
+	epochRewards := sb.finalize(chain, header, state, txs)
+	// Re-finalizing a block, e.g. to trace it, must not overwrite its records
+	if epochRewards != nil && !isReadOnly(chain) {
+		rawdb.WriteEpochRewards(sb.db, epochRewards.Epoch, header.Root, epochRewards)
+	}
+}
+
+// finalize applies the post-transaction state modifications of Finalize and
+// returns the epoch rewards distributed, if any.
+func (sb *Backend) finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction) *types.EpochRewards {
 	start := time.Now()
 	defer sb.finalizationTimer.UpdateSince(start)
 
@@ -545,7 +651,7 @@ func (sb *Backend) Finalize(chain consensus.ChainHeaderReader, header *types.Hea
 	state.Prepare(common.Hash{}, len(txs))
 
 	snapshot := state.Snapshot()
-	vmRunner := sb.chain.NewEVMRunner(header, state)
+	vmRunner := sb.newEVMRunner(chain, header, state)
 	err := gold_token.SetInitialTotalSupplyIfUnset(sb.db, vmRunner)
 	if err != nil {
 		state.RevertToSnapshot(snapshot)
@@ -560,18 +666,42 @@ func (sb *Backend) Finalize(chain consensus.ChainHeaderReader, header *types.Hea
 		}
 	}
 
+	var epochRewards *types.EpochRewards
 	lastBlockOfEpoch := istanbul.IsLastBlockOfEpoch(header.Number.Uint64(), sb.config.Epoch)
 	if lastBlockOfEpoch {
+		distribute := sb.distributeEpochRewards
+		if sb.distributeEpochRewardsHook != nil {
+			distribute = sb.distributeEpochRewardsHook
+		}
 		snapshot = state.Snapshot()
-		err = sb.distributeEpochRewards(header, state)
+		epochRewards, err = distribute(header, state, vmRunner)
 		if err != nil {
 			sb.logger.Error("Failed to distribute epoch rewards", "blockNumber", header.Number, "err", err)
 			state.RevertToSnapshot(snapshot)
+			epochRewards = nil
 		}
 	}
 
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
 	logger.Debug("Finalized", "duration", now().Sub(start), "lastInEpoch", lastBlockOfEpoch)
+	return epochRewards
+}
+
+// newEVMRunner creates the EVMRunner for the system calls made while finalizing
+// a block. It is created by the given chain if it can, so that callers like the
+// tracing API can observe those calls, and by the backend's chain otherwise.
+func (sb *Backend) newEVMRunner(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB) vm.EVMRunner {
+	if chain, ok := chain.(consensus.ChainContext); ok {
+		return chain.NewEVMRunner(header, state)
+	}
+	return sb.chain.NewEVMRunner(header, state)
+}
+
+// isReadOnly reports whether blocks finalized against the given chain must not
+// be persisted.
+func isReadOnly(chain consensus.ChainHeaderReader) bool {
+	readOnly, ok := chain.(consensus.ReadOnlyChain)
+	return ok && readOnly.ReadOnly()
 }
 
 // FinalizeAndAssemble runs any post-transaction state modifications (e.g. block
@@ -580,8 +710,10 @@ func (sb *Backend) Finalize(chain consensus.ChainHeaderReader, header *types.Hea
 // Note: The block header and state database might be updated to reflect any
 // consensus rules that happen at finalization (e.g. block rewards).
 func (sb *Backend) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, receipts []*types.Receipt, randomness *types.Randomness) (*types.Block, error) {
-
-	sb.Finalize(chain, header, state, txs)
+	// The epoch rewards of a candidate block aren't recorded: they are when the
+	// block is processed for import, so only blocks that made it to the chain
+	// leave a record
+	sb.finalize(chain, header, state, txs)
 	// Add the block receipt with logs from the non-transaction core contract calls (if there were any)
 	receipts = core.AddBlockReceipt(receipts, state, header.Hash())
 
@@ -658,6 +790,11 @@ func (sb *Backend) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 		Version:   "1.0",
 		Service:   &API{chain: chain, istanbul: sb},
 		Public:    true,
+	}, {
+		Namespace: "celo",
+		Version:   "1.0",
+		Service:   &CeloAPI{chain: chain, istanbul: sb},
+		Public:    true,
 	}}
 }
 
@@ -863,6 +1000,18 @@ func (sb *Backend) snapshot(chain consensus.ChainHeaderReader, number uint64, ha
 			break
 		}
 
+		// Without the full header chain, epochs before the trusted one are never synced
+		if trusted := sb.config.TrustedEpoch; trusted != nil && !chain.Config().FullHeaderChainAvailable && numberIter == trusted.BlockNumber(sb.config.Epoch) {
+			s, err := sb.trustedEpochSnapshot(trusted)
+			if err != nil {
+				return nil, err
+			}
+			log.Trace("Created validator set snapshot from the trusted epoch", "number", numberIter, "hash", trusted.Hash)
+			snap = s
+			sb.recentSnapshots.Add(numberIter, snap)
+			break
+		}
+
 		var blockHash common.Hash
 		if numberIter == number && hash != (common.Hash{}) {
 			blockHash = hash
@@ -984,6 +1133,19 @@ func (sb *Backend) snapshot(chain consensus.ChainHeaderReader, number uint64, ha
 	return returnSnap, nil
 }
 
+// trustedEpochSnapshot creates the validator set snapshot of the trusted epoch header.
+func (sb *Backend) trustedEpochSnapshot(trusted *params.TrustedEpoch) (*Snapshot, error) {
+	validators := make([]istanbul.ValidatorData, len(trusted.Validators))
+	for i, v := range trusted.Validators {
+		if len(v.BLSPublicKey) != blscrypto.PUBLICKEYBYTES {
+			return nil, fmt.Errorf("trusted epoch validator %s: invalid BLS public key length %d", v.Address, len(v.BLSPublicKey))
+		}
+		validators[i].Address = v.Address
+		copy(validators[i].BLSPublicKey[:], v.BLSPublicKey)
+	}
+	return newSnapshot(sb.config.Epoch, trusted.BlockNumber(sb.config.Epoch), trusted.Hash, validator.NewSet(validators)), nil
+}
+
 func (sb *Backend) addParentSeal(chain consensus.ChainHeaderReader, header *types.Header) error {
 	number := header.Number.Uint64()
 	logger := sb.logger.New("func", "addParentSeal", "number", number)
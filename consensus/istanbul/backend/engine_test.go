@@ -27,9 +27,13 @@ import (
 	"github.com/celo-org/celo-blockchain/consensus"
 	"github.com/celo-org/celo-blockchain/consensus/istanbul"
 	"github.com/celo-org/celo-blockchain/consensus/istanbul/core"
+	"github.com/celo-org/celo-blockchain/consensus/istanbul/validator"
+	"github.com/celo-org/celo-blockchain/contracts/testutil"
 	bccore "github.com/celo-org/celo-blockchain/core"
 	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/core/vm"
 	blscrypto "github.com/celo-org/celo-blockchain/crypto/bls"
+	"github.com/celo-org/celo-blockchain/params"
 	"github.com/celo-org/celo-blockchain/rlp"
 	. "github.com/onsi/gomega"
 )
@@ -273,6 +277,68 @@ func TestVerifyHeaders(t *testing.T) {
 		}
 	})
 
+	t.Run("Batch case", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		for i, err := range engine.verifyHeadersBatch(chain, headers, 0, size) {
+			g.Expect(err).NotTo(HaveOccurred(), "header %d", i)
+		}
+
+		// Give header 5 the seal of header 4: it is well formed, but signs the wrong hash.
+		invalid := append([]*types.Header{}, headers...)
+		invalid[5] = types.CopyHeader(headers[5])
+		extra, err := headers[4].IstanbulExtra()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(writeAggregatedSeal(invalid[5], extra.AggregatedSeal, false)).To(Succeed())
+		for i, err := range engine.verifyHeadersBatch(chain, invalid, 0, size) {
+			switch {
+			case i < 5:
+				g.Expect(err).NotTo(HaveOccurred(), "header %d", i)
+			case i == 5:
+				g.Expect(err).To(BeIdenticalTo(errInvalidSignature))
+			default:
+				g.Expect(err).To(BeIdenticalTo(consensus.ErrUnknownAncestor), "header %d", i)
+			}
+		}
+	})
+
+	t.Run("Trusted epoch case", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		// Give header 5 the seal of header 4, the last header is the trusted one
+		invalid := append([]*types.Header{}, headers...)
+		invalid[5] = types.CopyHeader(headers[5])
+		extra, err := headers[4].IstanbulExtra()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(writeAggregatedSeal(invalid[5], extra.AggregatedSeal, false)).To(Succeed())
+		engine.config.TrustedEpoch = &params.TrustedEpoch{Epoch: 1, Hash: headers[size-1].Hash()}
+		defer func() { engine.config.TrustedEpoch = nil }()
+		g.Expect(headers[size-1].Number.Uint64()).To(Equal(engine.config.TrustedEpoch.BlockNumber(engine.config.Epoch)))
+
+		verify := func(seals []bool) []error {
+			_, results := engine.VerifyHeaders(chain, invalid, seals)
+			errs := make([]error, size)
+			for i := range errs {
+				errs[i] = <-results
+			}
+			return errs
+		}
+		// Without seals to verify, the headers before the trusted epoch are accepted
+		for i, err := range verify(make([]bool, size)) {
+			g.Expect(err).NotTo(HaveOccurred(), "header %d", i)
+		}
+		seals := make([]bool, size)
+		seals[size-1] = true
+		for i, err := range verify(seals) {
+			switch {
+			case i < 5:
+				g.Expect(err).NotTo(HaveOccurred(), "header %d", i)
+			case i == 5:
+				g.Expect(err).To(HaveOccurred())
+			default:
+				g.Expect(err).To(BeIdenticalTo(consensus.ErrUnknownAncestor), "header %d", i)
+			}
+		}
+	})
+
 	t.Run("Error Header cases", func(t *testing.T) {
 		// error header cases
 		headers[2].Number = big.NewInt(100)
@@ -326,6 +392,46 @@ func TestVerifyHeaderWithoutFullChain(t *testing.T) {
 	})
 }
 
+func TestVerifyHeaderWithTrustedEpoch(t *testing.T) {
+	chain, engine := newBlockChain(1, false)
+	defer stopEngine(engine)
+	defer chain.Stop()
+
+	epochSize := engine.config.Epoch
+	header := makeBlockWithoutSeal(chain, engine, chain.Genesis()).Header()
+	header.Number = new(big.Int).SetUint64(3 * epochSize)
+	val := istanbul.ValidatorData{Address: common.HexToAddress("0x01")}
+	val.BLSPublicKey[0] = 1
+	engine.config.TrustedEpoch = &params.TrustedEpoch{
+		Epoch: 3,
+		Hash:  header.Hash(),
+		Validators: []params.TrustedEpochValidator{
+			{Address: val.Address, BLSPublicKey: val.BLSPublicKey[:]},
+		},
+	}
+	defer func() { engine.config.TrustedEpoch = nil }()
+
+	t.Run("should accept the trusted epoch header without full chain available", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		g.Expect(engine.VerifyHeader(chain, header, false)).To(Succeed())
+	})
+
+	t.Run("should reject a different header at the trusted epoch block", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		other := types.CopyHeader(header)
+		other.Time++
+		g.Expect(engine.VerifyHeader(chain, other, false)).To(BeIdenticalTo(errTrustedEpochMismatch))
+	})
+
+	t.Run("should start snapshots from the trusted validator set", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		snap, err := engine.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(snap.Hash).To(Equal(header.Hash()))
+		g.Expect(validator.MapValidatorsToData(snap.ValSet.List())).To(Equal([]istanbul.ValidatorData{val}))
+	})
+}
+
 func TestPrepareExtra(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -473,3 +579,43 @@ func TestWriteAggregatedSeal(t *testing.T) {
 	err = writeAggregatedSeal(h, invalidAggregatedSeal, true)
 	g.Expect(err).To(BeIdenticalTo(errInvalidAggregatedSeal))
 }
+
+// tracingChain finalizes blocks like the tracing API's chain does, making the
+// system calls through its own runner without persisting anything.
+type tracingChain struct {
+	*bccore.BlockChain
+	runner vm.EVMRunner
+}
+
+func (c *tracingChain) NewEVMRunner(header *types.Header, state vm.StateDB) vm.EVMRunner {
+	return c.runner
+}
+
+func (c *tracingChain) ReadOnly() bool { return true }
+
+// headerChain only gives access to headers.
+type headerChain struct {
+	consensus.ChainHeaderReader
+}
+
+func TestFinalizeChainContext(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	chain, engine := newBlockChain(1, true)
+	defer stopEngine(engine)
+	defer chain.Stop()
+	state, err := chain.State()
+	g.Expect(err).NotTo(HaveOccurred())
+	header := chain.CurrentHeader()
+
+	// System calls are made through the runner of chains that can create one
+	runner := testutil.NewMockEVMRunner()
+	tracing := &tracingChain{BlockChain: chain, runner: runner}
+	g.Expect(engine.newEVMRunner(tracing, header, state)).To(BeIdenticalTo(runner))
+	g.Expect(engine.newEVMRunner(headerChain{chain}, header, state)).NotTo(BeIdenticalTo(runner))
+
+	// Only read-only chains leave the database untouched
+	g.Expect(isReadOnly(tracing)).To(BeTrue())
+	g.Expect(isReadOnly(chain)).To(BeFalse())
+	g.Expect(isReadOnly(headerChain{chain})).To(BeFalse())
+}
@@ -36,45 +36,46 @@ import (
 	"github.com/celo-org/celo-blockchain/core/vm"
 )
 
-func (sb *Backend) distributeEpochRewards(header *types.Header, state *state.StateDB) error {
+// distributeEpochRewards pays out the epoch rewards and returns a record of what was
+// distributed, or nil if reward distribution is frozen.
+func (sb *Backend) distributeEpochRewards(header *types.Header, state *state.StateDB, vmRunner vm.EVMRunner) (*types.EpochRewards, error) {
 	start := time.Now()
 	defer sb.rewardDistributionTimer.UpdateSince(start)
 	logger := sb.logger.New("func", "Backend.distributeEpochPaymentsAndRewards", "blocknum", header.Number.Uint64())
 
-	vmRunner := sb.chain.NewEVMRunner(header, state)
 	if !sb.ChainConfig().IsGingerbread(header.Number) {
 		// Check if reward distribution has been frozen and return early without error if it is.
 		if frozen, err := freezer.IsFrozen(vmRunner, config.EpochRewardsRegistryId); err != nil {
 			logger.Warn("Failed to determine if epoch rewards are frozen", "err", err)
 		} else if frozen {
 			logger.Debug("Epoch rewards are frozen, skipping distribution")
-			return nil
+			return nil, nil
 		}
 	}
 
 	// Get necessary Addresses First
 	reserveAddress, err := contracts.GetRegisteredAddress(vmRunner, config.ReserveRegistryId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	stableTokenAddress, err := contracts.GetRegisteredAddress(vmRunner, config.StableTokenRegistryId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	carbonOffsettingPartnerAddress, err := epoch_rewards.GetCarbonOffsettingPartnerAddress(vmRunner)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = epoch_rewards.UpdateTargetVotingYield(vmRunner)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	validatorReward, totalVoterRewards, communityReward, carbonOffsettingPartnerReward, err := epoch_rewards.CalculateTargetEpochRewards(vmRunner)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if carbonOffsettingPartnerAddress == common.ZeroAddress {
@@ -82,6 +83,15 @@ func (sb *Backend) distributeEpochRewards(header *types.Header, state *state.Sta
 	}
 
 	logger.Debug("Calculated target rewards", "validatorReward", validatorReward, "totalVoterRewards", totalVoterRewards, "communityReward", communityReward)
+	rewards := &types.EpochRewards{
+		Epoch:                        istanbul.GetEpochNumber(header.Number.Uint64(), sb.EpochSize()),
+		TargetValidatorReward:        validatorReward,
+		TargetVoterRewards:           totalVoterRewards,
+		TargetCommunityReward:        communityReward,
+		TargetCarbonOffsettingReward: carbonOffsettingPartnerReward,
+		CommunityReward:              big.NewInt(0),
+		CarbonOffsettingReward:       big.NewInt(0),
+	}
 
 	// The validator set that signs off on the last block of the epoch is the one that we need to
 	// iterate over.
@@ -90,61 +100,93 @@ func (sb *Backend) distributeEpochRewards(header *types.Header, state *state.Sta
 
 		err := errors.New("Unable to fetch validator set to update scores and distribute rewards")
 		logger.Error(err.Error())
-		return err
+		return nil, err
 	}
 
-	uptimes, err := sb.updateValidatorScores(header, state, valSet)
+	uptimes, err := sb.updateValidatorScores(header, state, valSet, vmRunner)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	totalValidatorRewards, err := sb.distributeValidatorRewards(vmRunner, valSet, validatorReward)
+	validatorRewards, totalValidatorRewards, err := sb.distributeValidatorRewards(vmRunner, valSet, validatorReward)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	rewards.TotalValidatorRewards = totalValidatorRewards
 
 	// TODO(HF) Use vmRunner instead of current block's one
 	currentBlockVMRunner, err := sb.chain.NewEVMRunnerForCurrentBlock()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	currencyManager := currency.NewManager(currentBlockVMRunner)
 
 	// Validator rewards were paid in cUSD, convert that amount to CELO and add it to the Reserve
 	stableTokenCurrency, err := currencyManager.GetCurrency(&stableTokenAddress)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	totalValidatorRewardsConvertedToCelo := stableTokenCurrency.ToCELO(totalValidatorRewards)
+	rewards.TotalValidatorRewardsInCelo = totalValidatorRewardsConvertedToCelo
 
 	if err = gold_token.Mint(vmRunner, reserveAddress, totalValidatorRewardsConvertedToCelo); err != nil {
-		return err
+		return nil, err
 	}
 
 	if sb.ChainConfig().IsGingerbread(header.Number) {
-		if err := sb.distributeCommunityRewards(vmRunner, communityReward); err != nil {
-			return err
+		if rewards.CommunityRecipient, err = sb.distributeCommunityRewards(vmRunner, communityReward); err != nil {
+			return nil, err
 		}
 	} else {
-		if err := sb.distributeCommunityRewardsWithReserveFallback(vmRunner, communityReward); err != nil {
-			return err
+		if rewards.CommunityRecipient, rewards.ReserveFallback, err = sb.distributeCommunityRewardsWithReserveFallback(vmRunner, communityReward); err != nil {
+			return nil, err
 		}
 	}
+	if rewards.CommunityRecipient != common.ZeroAddress {
+		rewards.CommunityReward = communityReward
+	}
 
-	if err := sb.distributeVoterRewards(vmRunner, valSet, totalVoterRewards, uptimes); err != nil {
-		return err
+	valGroups, groups, voterRewards, totalVoterRewardsDistributed, err := sb.distributeVoterRewards(vmRunner, valSet, totalVoterRewards, uptimes)
+	if err != nil {
+		return nil, err
 	}
+	rewards.TotalVoterRewards = totalVoterRewardsDistributed
 
 	if carbonOffsettingPartnerReward.Cmp(new(big.Int)) != 0 {
 		if err = gold_token.Mint(vmRunner, carbonOffsettingPartnerAddress, carbonOffsettingPartnerReward); err != nil {
-			return err
+			return nil, err
+		}
+		rewards.CarbonOffsettingPartner = carbonOffsettingPartnerAddress
+		rewards.CarbonOffsettingReward = carbonOffsettingPartnerReward
+	}
+
+	groupIndex := make(map[common.Address]int, len(groups))
+	for i, group := range groups {
+		groupIndex[group] = i
+		rewards.Groups = append(rewards.Groups, types.GroupEpochReward{
+			Group:            group,
+			ValidatorRewards: big.NewInt(0),
+			VoterRewards:     voterRewards[i],
+		})
+	}
+	for i, val := range valSet {
+		reward, ok := validatorRewards[val.Address()]
+		if !ok {
+			continue
 		}
+		group := valGroups[i]
+		rewards.Validators = append(rewards.Validators, types.ValidatorEpochReward{
+			Validator: val.Address(),
+			Group:     group,
+			Reward:    reward,
+		})
+		rewards.Groups[groupIndex[group]].ValidatorRewards.Add(rewards.Groups[groupIndex[group]].ValidatorRewards, reward)
 	}
 
-	return nil
+	return rewards, nil
 }
 
-func (sb *Backend) updateValidatorScores(header *types.Header, state *state.StateDB, valSet []istanbul.Validator) ([]*big.Int, error) {
+func (sb *Backend) updateValidatorScores(header *types.Header, state *state.StateDB, valSet []istanbul.Validator, vmRunner vm.EVMRunner) ([]*big.Int, error) {
 	epoch := istanbul.GetEpochNumber(header.Number.Uint64(), sb.EpochSize())
 	logger := sb.logger.New("func", "Backend.updateValidatorScores", "blocknum", header.Number.Uint64(), "epoch", epoch, "epochsize", sb.EpochSize())
 
@@ -156,7 +198,6 @@ func (sb *Backend) updateValidatorScores(header *types.Header, state *state.Stat
 		return nil, err
 	}
 
-	vmRunner := sb.chain.NewEVMRunner(header, state)
 	for i, val := range valSet {
 		logger.Trace("Updating validator score", "uptime", uptimes[i], "address", val.Address())
 		err := validators.UpdateValidatorScore(vmRunner, val.Address(), uptimes[i])
@@ -167,7 +208,10 @@ func (sb *Backend) updateValidatorScores(header *types.Header, state *state.Stat
 	return uptimes, nil
 }
 
-func (sb *Backend) distributeValidatorRewards(vmRunner vm.EVMRunner, valSet []istanbul.Validator, maxReward *big.Int) (*big.Int, error) {
+// distributeValidatorRewards pays every validator in valSet its epoch payment. It
+// returns the payment of each validator that was paid successfully and their total.
+func (sb *Backend) distributeValidatorRewards(vmRunner vm.EVMRunner, valSet []istanbul.Validator, maxReward *big.Int) (map[common.Address]*big.Int, *big.Int, error) {
+	validatorRewards := make(map[common.Address]*big.Int, len(valSet))
 	totalValidatorRewards := big.NewInt(0)
 	for _, val := range valSet {
 		sb.logger.Debug("Distributing epoch reward for validator", "address", val.Address())
@@ -176,64 +220,75 @@ func (sb *Backend) distributeValidatorRewards(vmRunner vm.EVMRunner, valSet []is
 			sb.logger.Error("Error in distributing rewards to validator", "address", val.Address(), "err", err)
 			continue
 		}
+		validatorRewards[val.Address()] = validatorReward
 		totalValidatorRewards.Add(totalValidatorRewards, validatorReward)
 	}
-	return totalValidatorRewards, nil
+	return validatorRewards, totalValidatorRewards, nil
 }
 
-func (sb *Backend) distributeCommunityRewards(vmRunner vm.EVMRunner, communityReward *big.Int) error {
+// distributeCommunityRewards mints the community reward to governance and returns
+// the address it was minted to, which is the zero address if nothing was minted.
+func (sb *Backend) distributeCommunityRewards(vmRunner vm.EVMRunner, communityReward *big.Int) (common.Address, error) {
 	governanceAddress, err := contracts.GetRegisteredAddress(vmRunner, config.GovernanceRegistryId)
 	if err != nil {
-		return err
+		return common.ZeroAddress, err
 	}
 	if governanceAddress != common.ZeroAddress {
 		// TODO: How to split eco fund here
-		return gold_token.Mint(vmRunner, governanceAddress, communityReward)
+		return governanceAddress, gold_token.Mint(vmRunner, governanceAddress, communityReward)
 	}
-	return nil
+	return common.ZeroAddress, nil
 }
 
-func (sb *Backend) distributeCommunityRewardsWithReserveFallback(vmRunner vm.EVMRunner, communityReward *big.Int) error {
+// distributeCommunityRewardsWithReserveFallback mints the community reward to the
+// reserve if it is low, or to governance otherwise. It returns the address it was
+// minted to and whether the reserve fallback was used.
+func (sb *Backend) distributeCommunityRewardsWithReserveFallback(vmRunner vm.EVMRunner, communityReward *big.Int) (common.Address, bool, error) {
 	governanceAddress, err := contracts.GetRegisteredAddress(vmRunner, config.GovernanceRegistryId)
 	if err != nil {
-		return err
+		return common.ZeroAddress, false, err
 	}
 	reserveAddress, err := contracts.GetRegisteredAddress(vmRunner, config.ReserveRegistryId)
 	if err != nil {
-		return err
+		return common.ZeroAddress, false, err
 	}
 	lowReserve, err := epoch_rewards.IsReserveLow(vmRunner)
 	if err != nil {
-		return err
+		return common.ZeroAddress, false, err
 	}
 
 	if lowReserve && reserveAddress != common.ZeroAddress {
-		return gold_token.Mint(vmRunner, reserveAddress, communityReward)
+		return reserveAddress, true, gold_token.Mint(vmRunner, reserveAddress, communityReward)
 	} else if governanceAddress != common.ZeroAddress {
 		// TODO: How to split eco fund here
-		return gold_token.Mint(vmRunner, governanceAddress, communityReward)
+		return governanceAddress, false, gold_token.Mint(vmRunner, governanceAddress, communityReward)
 	}
-	return nil
+	return common.ZeroAddress, false, nil
 }
 
-func (sb *Backend) distributeVoterRewards(vmRunner vm.EVMRunner, valSet []istanbul.Validator, maxTotalRewards *big.Int, uptimes []*big.Int) error {
+// distributeVoterRewards distributes the voter rewards among the groups that elected
+// a validator in valSet. It returns the group of each validator in valSet, the groups
+// rewarded along with their rewards, and the total amount distributed.
+func (sb *Backend) distributeVoterRewards(vmRunner vm.EVMRunner, valSet []istanbul.Validator, maxTotalRewards *big.Int, uptimes []*big.Int) ([]common.Address, []common.Address, []*big.Int, *big.Int, error) {
 
 	lockedGoldAddress, err := contracts.GetRegisteredAddress(vmRunner, config.LockedGoldRegistryId)
 	if err != nil {
-		return err
+		return nil, nil, nil, nil, err
 	} else if lockedGoldAddress == common.ZeroAddress {
-		return errors.New("Unable to fetch locked gold address for epoch rewards distribution")
+		return nil, nil, nil, nil, errors.New("Unable to fetch locked gold address for epoch rewards distribution")
 	}
 
 	// Select groups that elected at least one validator aggregate their uptimes.
 	var groups []common.Address
+	valGroups := make([]common.Address, len(valSet))
 	groupUptimes := make(map[common.Address][]*big.Int)
 	groupElectedValidator := make(map[common.Address]bool)
 	for i, val := range valSet {
 		group, err := validators.GetMembershipInLastEpoch(vmRunner, val.Address())
 		if err != nil {
-			return err
+			return nil, nil, nil, nil, err
 		}
+		valGroups[i] = group
 		if _, ok := groupElectedValidator[group]; !ok {
 			groups = append(groups, group)
 			sb.logger.Debug("Group elected validator", "group", group.String())
@@ -242,10 +297,10 @@ func (sb *Backend) distributeVoterRewards(vmRunner vm.EVMRunner, valSet []istanb
 		groupUptimes[group] = append(groupUptimes[group], uptimes[i])
 	}
 
-	electionRewards, err := election.DistributeEpochRewards(vmRunner, groups, maxTotalRewards, groupUptimes)
+	electionRewards, groupRewards, err := election.DistributeEpochRewards(vmRunner, groups, maxTotalRewards, groupUptimes)
 	if err != nil {
-		return err
+		return nil, nil, nil, nil, err
 	}
 
-	return gold_token.Mint(vmRunner, lockedGoldAddress, electionRewards)
+	return valGroups, groups, groupRewards, electionRewards, gold_token.Mint(vmRunner, lockedGoldAddress, electionRewards)
 }
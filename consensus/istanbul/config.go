@@ -70,6 +70,9 @@ type Config struct {
 
 	// Load test config
 	LoadTestCSVFile string `toml:",omitempty"` // If non-empty, specifies the file to write out csv metrics about the block production cycle to.
+
+	// Sync configs
+	TrustedEpoch *params.TrustedEpoch `toml:"-"` // Epoch header accepted without verification as the root of the validator set chain
 }
 
 // ProxyConfig represents the configuration for validator's proxies
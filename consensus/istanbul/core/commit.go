@@ -27,11 +27,6 @@ import (
 	blscrypto "github.com/celo-org/celo-blockchain/crypto/bls"
 )
 
-// maxValidators represents the maximum number of validators the SNARK circuit supports
-// The prover code will then pad any proofs to this maximum to ensure consistent proof structure
-// TODO: Eventually make this governable
-const maxValidators = uint32(150)
-
 func (c *core) sendCommit() {
 	logger := c.newLogger("func", "sendCommit")
 	logger.Trace("Sending commit")
@@ -55,20 +50,9 @@ func (c *core) generateEpochValidatorSetData(blockNumber uint64, round uint8, bl
 		return nil, nil, false, errNotLastBlockInEpoch
 	}
 
-	// Serialize the public keys for the validators in the validator set.
-	blsPubKeys := []blscrypto.SerializedPublicKey{}
-	for _, v := range newValSet.List() {
-		blsPubKeys = append(blsPubKeys, v.BLSPublicKey())
-	}
-
-	maxNonSigners := uint32(newValSet.Size() - newValSet.MinQuorumSize())
-
 	// Before the Donut fork, use the snark data encoding with epoch entropy.
 	if !c.backend.ChainConfig().IsDonut(big.NewInt(int64(blockNumber))) {
-		message, extraData, err := blscrypto.EncodeEpochSnarkData(
-			blsPubKeys, maxNonSigners,
-			uint16(istanbul.GetEpochNumber(blockNumber, c.config.Epoch)),
-		)
+		message, extraData, err := istanbul.EncodeEpochValidatorSetData(blockNumber, c.config.Epoch, round, blockHash, common.Hash{}, newValSet, false)
 		// This is before the Donut hardfork, so signify this doesn't use CIP22.
 		return message, extraData, false, err
 	}
@@ -79,14 +63,7 @@ func (c *core) generateEpochValidatorSetData(blockNumber uint64, round uint8, bl
 		return nil, nil, false, errors.New("unknown block")
 	}
 
-	maxNonSigners = maxValidators - uint32(newValSet.MinQuorumSize())
-	message, extraData, err := blscrypto.EncodeEpochSnarkDataCIP22(
-		blsPubKeys, maxNonSigners, maxValidators,
-		uint16(istanbul.GetEpochNumber(blockNumber, c.config.Epoch)),
-		round,
-		blscrypto.EpochEntropyFromHash(blockHash),
-		blscrypto.EpochEntropyFromHash(parentEpochBlockHash),
-	)
+	message, extraData, err := istanbul.EncodeEpochValidatorSetData(blockNumber, c.config.Epoch, round, blockHash, parentEpochBlockHash, newValSet, true)
 	// This is after the Donut hardfork, so signify this uses CIP22.
 	return message, extraData, true, err
 }
@@ -0,0 +1,282 @@
+// Copyright 2017 The Celo Authors
+// This file is part of the celo library.
+//
+// The celo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The celo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the celo library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package epochproof verifies the chain of validator sets of an istanbul
+// network from the last header of each epoch, the way lightest sync does.
+// It needs nothing but the genesis header (or a trusted epoch header) and is
+// meant to be embedded by mobile and bridge clients.
+package epochproof
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/consensus/istanbul"
+	istanbulCore "github.com/celo-org/celo-blockchain/consensus/istanbul/core"
+	"github.com/celo-org/celo-blockchain/consensus/istanbul/validator"
+	"github.com/celo-org/celo-blockchain/core/types"
+	blscrypto "github.com/celo-org/celo-blockchain/crypto/bls"
+	"github.com/celo-org/celo-blockchain/params"
+	"github.com/celo-org/celo-blockchain/rlp"
+)
+
+var (
+	// ErrInvalidAggregatedSeal is returned if the aggregated seal is malformed.
+	ErrInvalidAggregatedSeal = errors.New("invalid aggregated seal")
+	// ErrInsufficientSeals is returned when there is not enough signatures to
+	// pass the quorum check.
+	ErrInsufficientSeals = errors.New("not enough seals to reach quorum")
+	// ErrInvalidSignature is returned when the aggregated signature does not
+	// verify against the signers' public keys.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrInvalidEpochSeal is returned when the epoch validator set seal does not
+	// verify against the new validator set.
+	ErrInvalidEpochSeal = errors.New("invalid epoch validator set seal")
+	// ErrUnexpectedEpochHeader is returned when a header is not the last block of
+	// the epoch following the last verified one.
+	ErrUnexpectedEpochHeader = errors.New("unexpected epoch header")
+	// ErrInvalidValidatorSetDiff is returned if the header contains an invalid
+	// validator set diff.
+	ErrInvalidValidatorSetDiff = errors.New("invalid validator set diff")
+)
+
+// EpochProof is the last header of an epoch together with the epoch validator
+// set seal committed for it. A stream of RLP encoded proofs is the format
+// written by `geth export-epochs`.
+type EpochProof struct {
+	Header *types.Header
+	Seal   *types.EpochSnarkData
+}
+
+// VerifyAggregatedSeal checks that the aggregated seal on headerHash was signed
+// by a quorum of the given validator set.
+func VerifyAggregatedSeal(headerHash common.Hash, validators istanbul.ValidatorSet, aggregatedSeal types.IstanbulAggregatedSeal) error {
+	signature, err := aggregatedSealSignature(headerHash, validators, aggregatedSeal)
+	if err != nil {
+		return err
+	}
+	if err := blscrypto.VerifyAggregatedSignature(signature.PublicKeys, signature.Message, signature.ExtraData, signature.Signature, false, false); err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// aggregatedSealSignature checks the format and quorum of an aggregated seal and
+// returns the signature that remains to be verified.
+func aggregatedSealSignature(headerHash common.Hash, validators istanbul.ValidatorSet, aggregatedSeal types.IstanbulAggregatedSeal) (blscrypto.AggregatedSignature, error) {
+	if len(aggregatedSeal.Signature) != types.IstanbulExtraBlsSignature {
+		return blscrypto.AggregatedSignature{}, ErrInvalidAggregatedSeal
+	}
+	publicKeys := signerPublicKeys(validators, aggregatedSeal.Bitmap)
+	// The length of a valid seal should be greater than the minimum quorum size
+	if len(publicKeys) < validators.MinQuorumSize() {
+		return blscrypto.AggregatedSignature{}, ErrInsufficientSeals
+	}
+	return blscrypto.AggregatedSignature{
+		PublicKeys: publicKeys,
+		Message:    istanbulCore.PrepareCommittedSeal(headerHash, aggregatedSeal.Round),
+		ExtraData:  []byte{},
+		Signature:  aggregatedSeal.Signature,
+	}, nil
+}
+
+// SealBatch collects aggregated seals from many headers so that their signatures
+// can be verified together, which is considerably cheaper than verifying them
+// one at a time. Like blscrypto.BatchVerifyAggregatedSignatures, it proves that
+// every header was signed by its seal's signers, but lets through seals whose
+// invalid signatures cancel each other out.
+type SealBatch struct {
+	signatures []blscrypto.AggregatedSignature
+}
+
+// Add checks the format and quorum of an aggregated seal immediately and queues
+// its signature to be checked by Verify.
+func (b *SealBatch) Add(headerHash common.Hash, validators istanbul.ValidatorSet, aggregatedSeal types.IstanbulAggregatedSeal) error {
+	signature, err := aggregatedSealSignature(headerHash, validators, aggregatedSeal)
+	if err != nil {
+		return err
+	}
+	b.signatures = append(b.signatures, signature)
+	return nil
+}
+
+// Len returns the number of seals queued in the batch.
+func (b *SealBatch) Len() int { return len(b.signatures) }
+
+// Verify checks the signatures of all queued seals. If the batch does not
+// verify, the seals are checked one by one to locate the first invalid one, and
+// its index is returned along with ErrInvalidSignature.
+func (b *SealBatch) Verify() (int, error) {
+	if blscrypto.BatchVerifyAggregatedSignatures(b.signatures, false, false) == nil {
+		return 0, nil
+	}
+	for i, signature := range b.signatures {
+		if err := blscrypto.VerifyAggregatedSignature(signature.PublicKeys, signature.Message, signature.ExtraData, signature.Signature, false, false); err != nil {
+			return i, ErrInvalidSignature
+		}
+	}
+	// Every seal is valid on its own, so the batch itself failed to run.
+	return 0, nil
+}
+
+// signerPublicKeys returns the public keys of the validators marked in bitmap.
+func signerPublicKeys(validators istanbul.ValidatorSet, bitmap *big.Int) []blscrypto.SerializedPublicKey {
+	publicKeys := []blscrypto.SerializedPublicKey{}
+	if bitmap == nil {
+		return publicKeys
+	}
+	for i := 0; i < validators.Size(); i++ {
+		if bitmap.Bit(i) == 1 {
+			publicKeys = append(publicKeys, validators.GetByIndex(uint64(i)).BLSPublicKey())
+		}
+	}
+	return publicKeys
+}
+
+// Verifier tracks the validator set of the latest verified epoch and extends
+// it one epoch header at a time.
+type Verifier struct {
+	config    *params.ChainConfig
+	epochSize uint64
+
+	number uint64
+	hash   common.Hash
+	valSet istanbul.ValidatorSet
+}
+
+// New creates a verifier that starts from the validator set in the genesis header.
+func New(config *params.ChainConfig, genesis *types.Header) (*Verifier, error) {
+	if genesis.Number.Sign() != 0 {
+		return nil, fmt.Errorf("header %d is not a genesis header", genesis.Number)
+	}
+	extra, err := genesis.IstanbulExtra()
+	if err != nil {
+		return nil, err
+	}
+	validators, err := istanbul.CombineIstanbulExtraToValidatorData(extra.AddedValidators, extra.AddedValidatorsPublicKeys)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromTrusted(config, 0, genesis.Hash(), validators)
+}
+
+// NewFromTrusted creates a verifier that starts from a trusted epoch header,
+// given its number, hash and the validator set elected for the following epoch.
+func NewFromTrusted(config *params.ChainConfig, number uint64, hash common.Hash, validators []istanbul.ValidatorData) (*Verifier, error) {
+	if config.Istanbul == nil || config.Istanbul.Epoch == 0 {
+		return nil, errors.New("chain config has no istanbul epoch size")
+	}
+	if number%config.Istanbul.Epoch != 0 {
+		return nil, fmt.Errorf("block %d is not the last block of an epoch", number)
+	}
+	if len(validators) == 0 {
+		return nil, errors.New("empty validator set")
+	}
+	return &Verifier{
+		config:    config,
+		epochSize: config.Istanbul.Epoch,
+		number:    number,
+		hash:      hash,
+		valSet:    validator.NewSet(validators),
+	}, nil
+}
+
+// Number returns the number of the last verified epoch header.
+func (v *Verifier) Number() uint64 { return v.number }
+
+// Hash returns the hash of the last verified epoch header.
+func (v *Verifier) Hash() common.Hash { return v.hash }
+
+// Validators returns the validator set elected by the last verified epoch header.
+func (v *Verifier) Validators() []istanbul.ValidatorData {
+	return validator.MapValidatorsToData(v.valSet.List())
+}
+
+// Verify checks the last header of the next epoch and, if it is valid, moves
+// the verifier to the validator set it elects. The header must carry an
+// aggregated seal from a quorum of the current validators. The epoch validator
+// set seal is checked as well when seal is not empty.
+func (v *Verifier) Verify(header *types.Header, seal *types.EpochSnarkData) error {
+	if !header.Number.IsUint64() || header.Number.Uint64() != v.number+v.epochSize {
+		return ErrUnexpectedEpochHeader
+	}
+	extra, err := header.IstanbulExtra()
+	if err != nil {
+		return err
+	}
+	hash := header.Hash()
+	if err := VerifyAggregatedSeal(hash, v.valSet, extra.AggregatedSeal); err != nil {
+		return err
+	}
+
+	added, err := istanbul.CombineIstanbulExtraToValidatorData(extra.AddedValidators, extra.AddedValidatorsPublicKeys)
+	if err != nil {
+		return ErrInvalidValidatorSetDiff
+	}
+	newValSet := v.valSet.Copy()
+	if !newValSet.RemoveValidators(extra.RemovedValidators) || !newValSet.AddValidators(added) {
+		return ErrInvalidValidatorSetDiff
+	}
+
+	if seal != nil && !seal.IsEmpty() {
+		if err := v.verifyEpochSeal(header, hash, extra.AggregatedSeal.Round, newValSet, seal); err != nil {
+			return err
+		}
+	}
+
+	v.number = header.Number.Uint64()
+	v.hash = hash
+	v.valSet = newValSet
+	return nil
+}
+
+// verifyEpochSeal checks that seal was signed by a quorum of the current
+// validators over the encoding of the new validator set.
+func (v *Verifier) verifyEpochSeal(header *types.Header, hash common.Hash, round *big.Int, newValSet istanbul.ValidatorSet, seal *types.EpochSnarkData) error {
+	publicKeys := signerPublicKeys(v.valSet, seal.Bitmap)
+	if len(publicKeys) < v.valSet.MinQuorumSize() {
+		return ErrInsufficientSeals
+	}
+	cip22 := v.config.IsDonut(header.Number)
+	message, extraData, err := istanbul.EncodeEpochValidatorSetData(header.Number.Uint64(), v.epochSize, uint8(round.Uint64()), hash, v.hash, newValSet, cip22)
+	if err != nil {
+		return err
+	}
+	if err := blscrypto.VerifyAggregatedSignature(publicKeys, message, extraData, seal.Signature, true, cip22); err != nil {
+		return ErrInvalidEpochSeal
+	}
+	return nil
+}
+
+// VerifyStream verifies the RLP encoded epoch proofs read from r in order,
+// returning the number of proofs that were verified.
+func (v *Verifier) VerifyStream(r io.Reader) (int, error) {
+	stream := rlp.NewStream(r, 0)
+	for n := 0; ; n++ {
+		var proof EpochProof
+		if err := stream.Decode(&proof); err == io.EOF {
+			return n, nil
+		} else if err != nil {
+			return n, fmt.Errorf("proof %d: %v", n, err)
+		}
+		if err := v.Verify(proof.Header, proof.Seal); err != nil {
+			return n, fmt.Errorf("epoch header %d: %v", proof.Header.Number, err)
+		}
+	}
+}
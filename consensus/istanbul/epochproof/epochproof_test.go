@@ -0,0 +1,294 @@
+package epochproof
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/consensus/istanbul"
+	istanbulCore "github.com/celo-org/celo-blockchain/consensus/istanbul/core"
+	"github.com/celo-org/celo-blockchain/consensus/istanbul/validator"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/crypto"
+	blscrypto "github.com/celo-org/celo-blockchain/crypto/bls"
+	"github.com/celo-org/celo-blockchain/params"
+	"github.com/celo-org/celo-blockchain/rlp"
+	"github.com/celo-org/celo-bls-go/bls"
+)
+
+const testEpochSize = 10
+
+type testValidator struct {
+	key  *ecdsa.PrivateKey
+	data istanbul.ValidatorData
+}
+
+func newTestValidators(t *testing.T, n int) []testValidator {
+	vals := make([]testValidator, n)
+	for i := range vals {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		blsKey, err := blscrypto.ECDSAToBLS(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey, err := blscrypto.PrivateToPublic(blsKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vals[i] = testValidator{key: key, data: istanbul.ValidatorData{Address: crypto.PubkeyToAddress(key.PublicKey), BLSPublicKey: pubKey}}
+	}
+	return vals
+}
+
+func signBLS(t *testing.T, key *ecdsa.PrivateKey, data, extraData []byte, useComposite, cip22 bool) []byte {
+	blsKey, err := blscrypto.ECDSAToBLS(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey, err := bls.DeserializePrivateKey(blsKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer privateKey.Destroy()
+	signature, err := privateKey.SignMessage(data, extraData, useComposite, cip22)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer signature.Destroy()
+	sig, err := signature.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func aggregate(t *testing.T, sigs [][]byte) []byte {
+	asig, err := blscrypto.AggregateSignatures(sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return asig
+}
+
+func makeHeader(t *testing.T, number uint64, extra *types.IstanbulExtra) *types.Header {
+	payload, err := rlp.EncodeToBytes(extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &types.Header{
+		Number: new(big.Int).SetUint64(number),
+		Extra:  append(make([]byte, types.IstanbulExtraVanity), payload...),
+	}
+}
+
+func makeGenesis(t *testing.T, vals []testValidator) *types.Header {
+	extra := &types.IstanbulExtra{RemovedValidators: new(big.Int)}
+	for _, v := range vals {
+		extra.AddedValidators = append(extra.AddedValidators, v.data.Address)
+		extra.AddedValidatorsPublicKeys = append(extra.AddedValidatorsPublicKeys, v.data.BLSPublicKey)
+	}
+	return makeHeader(t, 0, extra)
+}
+
+// makeEpochProof builds the last header of an epoch signed by the given signers
+// (indices into the current validator set), replacing validator 0 with added.
+func makeEpochProof(t *testing.T, config *params.ChainConfig, number uint64, parentEpochHash common.Hash, current []testValidator, signers []int, added testValidator) (*types.Header, *types.EpochSnarkData) {
+	extra := &types.IstanbulExtra{
+		AddedValidators:           []common.Address{added.data.Address},
+		AddedValidatorsPublicKeys: []blscrypto.SerializedPublicKey{added.data.BLSPublicKey},
+		RemovedValidators:         big.NewInt(1),
+	}
+	header := makeHeader(t, number, extra)
+	hash := header.Hash()
+
+	round := big.NewInt(0)
+	newValSet := validator.NewSet(dataOf(current))
+	newValSet.RemoveValidators(big.NewInt(1))
+	newValSet.AddValidators([]istanbul.ValidatorData{added.data})
+	cip22 := config.IsDonut(header.Number)
+	message, extraData, err := istanbul.EncodeEpochValidatorSetData(number, testEpochSize, 0, hash, parentEpochHash, newValSet, cip22)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var seals, epochSeals [][]byte
+	bitmap := new(big.Int)
+	for _, i := range signers {
+		seals = append(seals, signBLS(t, current[i].key, istanbulCore.PrepareCommittedSeal(hash, round), []byte{}, false, false))
+		epochSeals = append(epochSeals, signBLS(t, current[i].key, message, extraData, true, cip22))
+		bitmap.SetBit(bitmap, i, 1)
+	}
+	extra.AggregatedSeal = types.IstanbulAggregatedSeal{Bitmap: bitmap, Signature: aggregate(t, seals), Round: round}
+	sealed := makeHeader(t, number, extra)
+	if sealed.Hash() != hash {
+		t.Fatal("aggregated seal changed the header hash")
+	}
+	return sealed, &types.EpochSnarkData{Bitmap: bitmap, Signature: aggregate(t, epochSeals)}
+}
+
+func dataOf(vals []testValidator) []istanbul.ValidatorData {
+	data := make([]istanbul.ValidatorData, len(vals))
+	for i, v := range vals {
+		data[i] = v.data
+	}
+	return data
+}
+
+func testConfig(donut bool) *params.ChainConfig {
+	config := *params.IstanbulTestChainConfig
+	config.Istanbul = &params.IstanbulConfig{Epoch: testEpochSize}
+	if !donut {
+		config.DonutBlock = nil
+	} else {
+		config.DonutBlock = big.NewInt(0)
+	}
+	return &config
+}
+
+func TestVerifyEpochChain(t *testing.T) {
+	for _, donut := range []bool{false, true} {
+		config := testConfig(donut)
+		vals := newTestValidators(t, 5)
+		genesis := makeGenesis(t, vals[:4])
+
+		verifier, err := New(config, genesis)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Epoch 1: validators 1-3 sign and validator 0 is replaced by validator 4.
+		header, seal := makeEpochProof(t, config, testEpochSize, genesis.Hash(), vals[:4], []int{1, 2, 3}, vals[4])
+		if err := verifier.Verify(header, seal); err != nil {
+			t.Fatalf("donut %v: epoch 1: %v", donut, err)
+		}
+		if verifier.Number() != testEpochSize || verifier.Hash() != header.Hash() {
+			t.Fatalf("donut %v: verifier did not advance", donut)
+		}
+		next := vals[1:]
+		got := verifier.Validators()
+		for i, v := range next {
+			if got[i].Address != v.data.Address {
+				t.Fatalf("donut %v: validator %d: have %x, want %x", donut, i, got[i].Address, v.data.Address)
+			}
+		}
+
+		// Epoch 2 must be signed by the new set: the removed validator cannot help.
+		header2, seal2 := makeEpochProof(t, config, 2*testEpochSize, header.Hash(), vals[:4], []int{0, 1, 2}, vals[0])
+		if err := verifier.Verify(header2, seal2); err != ErrInvalidSignature {
+			t.Fatalf("donut %v: signed by the old set: have %v, want %v", donut, err, ErrInvalidSignature)
+		}
+		header2, seal2 = makeEpochProof(t, config, 2*testEpochSize, header.Hash(), next, []int{0, 1, 2}, vals[0])
+		if err := verifier.Verify(header2, seal2); err != nil {
+			t.Fatalf("donut %v: epoch 2: %v", donut, err)
+		}
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	config := testConfig(true)
+	vals := newTestValidators(t, 5)
+	genesis := makeGenesis(t, vals[:4])
+
+	newVerifier := func() *Verifier {
+		verifier, err := New(config, genesis)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return verifier
+	}
+
+	header, seal := makeEpochProof(t, config, testEpochSize, genesis.Hash(), vals[:4], []int{1, 2}, vals[4])
+	if err := newVerifier().Verify(header, seal); err != ErrInsufficientSeals {
+		t.Errorf("below quorum: have %v, want %v", err, ErrInsufficientSeals)
+	}
+
+	header, seal = makeEpochProof(t, config, 2*testEpochSize, genesis.Hash(), vals[:4], []int{1, 2, 3}, vals[4])
+	if err := newVerifier().Verify(header, seal); err != ErrUnexpectedEpochHeader {
+		t.Errorf("skipped epoch: have %v, want %v", err, ErrUnexpectedEpochHeader)
+	}
+
+	// The epoch seal commits to the hash of the previous epoch block.
+	header, seal = makeEpochProof(t, config, testEpochSize, common.HexToHash("0x01"), vals[:4], []int{1, 2, 3}, vals[4])
+	if err := newVerifier().Verify(header, seal); err != ErrInvalidEpochSeal {
+		t.Errorf("wrong parent epoch hash: have %v, want %v", err, ErrInvalidEpochSeal)
+	}
+	// Without an epoch seal only the aggregated seal is checked.
+	if err := newVerifier().Verify(header, &types.EmptyEpochSnarkData); err != nil {
+		t.Errorf("empty epoch seal: %v", err)
+	}
+}
+
+func TestVerifyStream(t *testing.T) {
+	config := testConfig(true)
+	vals := newTestValidators(t, 5)
+	genesis := makeGenesis(t, vals[:4])
+
+	header, seal := makeEpochProof(t, config, testEpochSize, genesis.Hash(), vals[:4], []int{0, 1, 2, 3}, vals[4])
+	next := vals[1:]
+	header2, seal2 := makeEpochProof(t, config, 2*testEpochSize, header.Hash(), next, []int{0, 1, 3}, vals[0])
+
+	var buf bytes.Buffer
+	for _, proof := range []EpochProof{{header, seal}, {header2, seal2}} {
+		if err := rlp.Encode(&buf, &proof); err != nil {
+			t.Fatal(err)
+		}
+	}
+	verifier, err := New(config, genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := verifier.VerifyStream(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || verifier.Number() != 2*testEpochSize {
+		t.Fatalf("verified %d proofs up to block %d", n, verifier.Number())
+	}
+}
+
+func TestSealBatch(t *testing.T) {
+	config := testConfig(true)
+	vals := newTestValidators(t, 5)
+	valSet := validator.NewSet(dataOf(vals[:4]))
+
+	var batch SealBatch
+	var headers []*types.Header
+	for i := 0; i < 4; i++ {
+		header, _ := makeEpochProof(t, config, uint64(i+1)*testEpochSize, common.Hash{}, vals[:4], []int{0, 1, 2}, vals[4])
+		extra, err := header.IstanbulExtra()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := batch.Add(header.Hash(), valSet, extra.AggregatedSeal); err != nil {
+			t.Fatalf("seal %d: %v", i, err)
+		}
+		headers = append(headers, header)
+	}
+	if batch.Len() != 4 {
+		t.Fatalf("batch length: have %d, want 4", batch.Len())
+	}
+	if _, err := batch.Verify(); err != nil {
+		t.Fatalf("valid batch: %v", err)
+	}
+
+	// A seal below quorum is rejected right away.
+	extra, _ := headers[0].IstanbulExtra()
+	seal := extra.AggregatedSeal
+	seal.Bitmap = big.NewInt(1)
+	if err := batch.Add(headers[0].Hash(), valSet, seal); err != ErrInsufficientSeals {
+		t.Fatalf("below quorum: have %v, want %v", err, ErrInsufficientSeals)
+	}
+
+	// A seal over the wrong header is located by the fallback.
+	if err := batch.Add(headers[1].Hash(), valSet, extra.AggregatedSeal); err != nil {
+		t.Fatal(err)
+	}
+	if bad, err := batch.Verify(); err != ErrInvalidSignature || bad != 4 {
+		t.Fatalf("invalid seal: have %d %v, want 4 %v", bad, err, ErrInvalidSignature)
+	}
+}
@@ -137,6 +137,12 @@ func GetEpochLastBlockNumber(epochNumber uint64, epochSize uint64) uint64 {
 	return epochNumber * epochSize
 }
 
+// GetLatestEpochLastBlockNumber retrieves the last block of the latest epoch completed
+// at the given block number. That is the block itself if it is the last of its epoch.
+func GetLatestEpochLastBlockNumber(number uint64, epochSize uint64) uint64 {
+	return (number / epochSize) * epochSize
+}
+
 func ValidatorSetDiff(oldValSet []ValidatorData, newValSet []ValidatorData) ([]ValidatorData, *big.Int) {
 	valSetMap := make(map[common.Address]bool)
 	oldValSetIndices := make(map[common.Address]int)
@@ -225,3 +231,34 @@ func GetTimestamp() uint {
 	// Unix() returns a int64, but we need a uint for the golang rlp encoding implmentation. Warning: This timestamp value will be truncated in 2106.
 	return uint(time.Now().Unix())
 }
+
+// maxEpochSnarkValidators represents the maximum number of validators the SNARK circuit supports
+// The prover code will then pad any proofs to this maximum to ensure consistent proof structure
+// TODO: Eventually make this governable
+const maxEpochSnarkValidators = uint32(150)
+
+// EncodeEpochValidatorSetData serializes the data signed by the epoch validator set seal
+// of the last block of an epoch, as used in the Plumo SNARK circuit.
+// Before the Donut fork (cip22 false) the round and entropy arguments are ignored.
+func EncodeEpochValidatorSetData(blockNumber, epochSize uint64, round uint8, blockHash, parentEpochBlockHash common.Hash, newValSet ValidatorSet, cip22 bool) ([]byte, []byte, error) {
+	// Serialize the public keys for the validators in the validator set.
+	blsPubKeys := []blscrypto.SerializedPublicKey{}
+	for _, v := range newValSet.List() {
+		blsPubKeys = append(blsPubKeys, v.BLSPublicKey())
+	}
+	epochNumber := uint16(GetEpochNumber(blockNumber, epochSize))
+
+	if !cip22 {
+		maxNonSigners := uint32(newValSet.Size() - newValSet.MinQuorumSize())
+		return blscrypto.EncodeEpochSnarkData(blsPubKeys, maxNonSigners, epochNumber)
+	}
+
+	maxNonSigners := maxEpochSnarkValidators - uint32(newValSet.MinQuorumSize())
+	return blscrypto.EncodeEpochSnarkDataCIP22(
+		blsPubKeys, maxNonSigners, maxEpochSnarkValidators,
+		epochNumber,
+		round,
+		blscrypto.EpochEntropyFromHash(blockHash),
+		blscrypto.EpochEntropyFromHash(parentEpochBlockHash),
+	)
+}
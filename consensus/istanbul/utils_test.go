@@ -187,6 +187,31 @@ func TestGetEpochLastBlockNumber(t *testing.T) {
 	}
 }
 
+func TestGetLatestEpochLastBlockNumber(t *testing.T) {
+	type args struct {
+		number    uint64
+		epochSize uint64
+	}
+	tests := []struct {
+		name string
+		args args
+		want uint64
+	}{
+		{"genesis", args{0, 10}, 0},
+		{"first epoch", args{5, 10}, 0},
+		{"last block epoch 1", args{10, 10}, 10},
+		{"first block epoch 2", args{11, 10}, 10},
+		{"last block epoch 2", args{20, 10}, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetLatestEpochLastBlockNumber(tt.args.number, tt.args.epochSize); got != tt.want {
+				t.Errorf("GetLatestEpochLastBlockNumber() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetNumberWithinEpoch(t *testing.T) {
 	type args struct {
 		number    uint64
@@ -116,6 +116,34 @@ const ERC20Str = `[
 		"payable": false,
 		"stateMutability": "view",
 		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [],
+		"name": "symbol",
+		"outputs": [
+			{
+				"name": "",
+				"type": "string"
+			}
+		],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [],
+		"name": "decimals",
+		"outputs": [
+			{
+				"name": "",
+				"type": "uint8"
+			}
+		],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
 }]`
 
 // This is taken from celo-monorepo/packages/protocol/build/<env>/contracts/FeeCurrency.json
@@ -605,6 +633,29 @@ const RandomStr = `[
 	}
 ]`
 
+// This is taken from celo-monorepo/packages/protocol/build/<env>/contracts/Accounts.json
+const AccountsStr = `[
+	{
+		"constant": true,
+		"inputs": [
+			{
+				"name": "signer",
+				"type": "address"
+			}
+		],
+		"name": "signerToAccount",
+		"outputs": [
+			{
+				"name": "",
+				"type": "address"
+			}
+		],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
 // This is taken from celo-monorepo/packages/protocol/build/<env>/contracts/Validators.json
 const ValidatorsStr = `[
 	{
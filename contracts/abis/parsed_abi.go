@@ -11,6 +11,7 @@ import (
 
 var (
 	Registry             *abi.ABI = mustParseAbi("Registry", RegistryStr)
+	Accounts             *abi.ABI = mustParseAbi("Accounts", AccountsStr)
 	BlockchainParameters *abi.ABI = mustParseAbi("BlockchainParameters", BlockchainParametersStr)
 	SortedOracles        *abi.ABI = mustParseAbi("SortedOracles", SortedOraclesStr)
 	ERC20                *abi.ABI = mustParseAbi("ERC20", ERC20Str)
@@ -34,6 +35,7 @@ func mustParseAbi(name, abiStr string) *abi.ABI {
 }
 
 var byRegistryId = map[common.Hash]*abi.ABI{
+	config.AccountsRegistryId:             Accounts,
 	config.BlockchainParametersRegistryId: BlockchainParameters,
 	config.SortedOraclesRegistryId:        SortedOracles,
 	config.FeeCurrencyWhitelistRegistryId: FeeCurrencyWhitelist,
@@ -10,6 +10,7 @@ var (
 
 	// Celo registered contract IDs.
 	// The names are taken from celo-monorepo/packages/protocol/lib/registry-utils.ts
+	AccountsRegistryId             = makeRegistryId("Accounts")
 	AttestationsRegistryId         = makeRegistryId("Attestations")
 	BlockchainParametersRegistryId = makeRegistryId("BlockchainParameters")
 	ElectionRegistryId             = makeRegistryId("Election")
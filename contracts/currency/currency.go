@@ -39,6 +39,8 @@ var (
 	medianRateMethod   = contracts.NewRegisteredContractMethod(config.SortedOraclesRegistryId, abis.SortedOracles, "medianRate", maxGasForMedianRate)
 	getWhitelistMethod = contracts.NewRegisteredContractMethod(config.FeeCurrencyWhitelistRegistryId, abis.FeeCurrencyWhitelist, "getWhitelist", maxGasForGetWhiteList)
 	getBalanceMethod   = contracts.NewMethod(abis.ERC20, "balanceOf", maxGasToReadErc20Balance)
+	getSymbolMethod    = contracts.NewMethod(abis.ERC20, "symbol", maxGasToReadErc20Balance)
+	getDecimalsMethod  = contracts.NewMethod(abis.ERC20, "decimals", maxGasToReadErc20Balance)
 )
 
 // NoopExchangeRate represents an exchange rate of 1 to 1
@@ -132,6 +134,16 @@ func (er *ExchangeRate) FromBase(goldAmount *big.Int) *big.Int {
 	return new(big.Int).Div(new(big.Int).Mul(goldAmount, er.numerator), er.denominator)
 }
 
+// Numerator returns the token side of the exchange rate
+func (er *ExchangeRate) Numerator() *big.Int {
+	return new(big.Int).Set(er.numerator)
+}
+
+// Denominator returns the base side of the exchange rate
+func (er *ExchangeRate) Denominator() *big.Int {
+	return new(big.Int).Set(er.denominator)
+}
+
 // CurrencyManager provides an interface to access different fee currencies on a given point in time (header,state)
 // and doing comparison or fetching exchange rates
 //
@@ -252,6 +264,35 @@ func GetBalanceOf(vmRunner vm.EVMRunner, accountOwner common.Address, contractAd
 	return result, err
 }
 
+// GetTokenBalanceOf returns an account's balance on a given ERC20 token. Unlike
+// GetBalanceOf it is meant for arbitrary tokens, so failures are only logged at
+// debug level.
+func GetTokenBalanceOf(vmRunner vm.EVMRunner, accountOwner common.Address, contractAddress common.Address) (result *big.Int, err error) {
+	err = getBalanceMethod.Bind(contractAddress).Query(vmRunner, &result, accountOwner)
+	if err != nil {
+		log.Debug("GetTokenBalanceOf evm invocation error", "contractAddress", contractAddress, "err", err)
+	}
+	return result, err
+}
+
+// GetSymbol returns the symbol of a given ERC20 currency
+func GetSymbol(vmRunner vm.EVMRunner, contractAddress common.Address) (result string, err error) {
+	err = getSymbolMethod.Bind(contractAddress).Query(vmRunner, &result)
+	if err != nil {
+		log.Debug("GetSymbol evm invocation error", "contractAddress", contractAddress, "err", err)
+	}
+	return result, err
+}
+
+// GetDecimals returns the number of decimals of a given ERC20 currency
+func GetDecimals(vmRunner vm.EVMRunner, contractAddress common.Address) (result uint8, err error) {
+	err = getDecimalsMethod.Bind(contractAddress).Query(vmRunner, &result)
+	if err != nil {
+		log.Debug("GetDecimals evm invocation error", "contractAddress", contractAddress, "err", err)
+	}
+	return result, err
+}
+
 // CurrencyWhitelist retrieves the list of currencies that can be used to pay transaction fees
 func CurrencyWhitelist(vmRunner vm.EVMRunner) ([]common.Address, error) {
 	returnList := []common.Address{}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/contracts/testutil"
 	"github.com/celo-org/celo-blockchain/core/vm"
 	. "github.com/onsi/gomega"
 )
@@ -224,6 +225,17 @@ func TestExchangeRate(t *testing.T) {
 		g.Expect(twoToOne.ToBase(common.Big2)).Should(EqualBigInt(1))
 	})
 
+	t.Run("should expose copies of its terms", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		twoToOne := MustNewExchangeRate(common.Big2, common.Big1)
+
+		g.Expect(twoToOne.Numerator()).Should(EqualBigInt(2))
+		g.Expect(twoToOne.Denominator()).Should(EqualBigInt(1))
+
+		twoToOne.Numerator().SetInt64(5)
+		g.Expect(twoToOne.Numerator()).Should(EqualBigInt(2))
+	})
+
 }
 
 func TestCurrency(t *testing.T) {
@@ -248,3 +260,51 @@ func TestCurrency(t *testing.T) {
 		g.Expect(expensiveCurrency.CmpToCurrency(big.NewInt(10), big.NewInt(10), &cheapCurrency)).Should(Equal(1))
 	})
 }
+
+func TestGetTokenBalanceOf(t *testing.T) {
+	var (
+		token   = common.HexToAddress("0x0e")
+		account = common.HexToAddress("0x1234")
+	)
+	testutil.TestFailOnFailingRunner(t, GetTokenBalanceOf, account, token)
+
+	g := NewGomegaWithT(t)
+	mock := testutil.NewERC20MetadataMock("cUSD", 18)
+	mock.Balances[account] = big.NewInt(42)
+	vmRunner := testutil.NewMockEVMRunner()
+	vmRunner.RegisterContract(token, mock)
+
+	balance, err := GetTokenBalanceOf(vmRunner, account, token)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(balance).To(Equal(big.NewInt(42)))
+}
+
+func TestGetSymbol(t *testing.T) {
+	token := common.HexToAddress("0x0e")
+	testutil.TestFailOnFailingRunner(t, GetSymbol, token)
+
+	g := NewGomegaWithT(t)
+	vmRunner := testutil.NewMockEVMRunner()
+	vmRunner.RegisterContract(token, testutil.NewERC20MetadataMock("cUSD", 18))
+
+	symbol, err := GetSymbol(vmRunner, token)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(symbol).To(Equal("cUSD"))
+}
+
+func TestGetDecimals(t *testing.T) {
+	token := common.HexToAddress("0x0e")
+	testutil.TestFailOnFailingRunner(t, GetDecimals, token)
+
+	g := NewGomegaWithT(t)
+	vmRunner := testutil.NewMockEVMRunner()
+	vmRunner.RegisterContract(token, testutil.NewERC20MetadataMock("USDC", 6))
+
+	decimals, err := GetDecimals(vmRunner, token)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(decimals).To(Equal(uint8(6)))
+
+	// Tokens without a decimals method
+	_, err = GetDecimals(vmRunner, common.HexToAddress("0x0f"))
+	g.Expect(err).To(HaveOccurred())
+}
@@ -99,6 +99,20 @@ func getTotalVotesForEligibleValidatorGroups(vmRunner vm.EVMRunner) ([]voteTotal
 	return voteTotals, err
 }
 
+// GetGroupVoteTotals returns the total votes of every validator group eligible
+// for election, keyed by group address.
+func GetGroupVoteTotals(vmRunner vm.EVMRunner) (map[common.Address]*big.Int, error) {
+	voteTotals, err := getTotalVotesForEligibleValidatorGroups(vmRunner)
+	if err != nil {
+		return nil, err
+	}
+	totals := make(map[common.Address]*big.Int, len(voteTotals))
+	for _, voteTotal := range voteTotals {
+		totals[voteTotal.Group] = voteTotal.Value
+	}
+	return totals, nil
+}
+
 func getGroupEpochRewards(vmRunner vm.EVMRunner, group common.Address, maxRewards *big.Int, uptimes []*big.Int) (*big.Int, error) {
 	var groupEpochRewards *big.Int
 	err := getGroupEpochRewardsMethod.Query(vmRunner, &groupEpochRewards, group, maxRewards, uptimes)
@@ -108,18 +122,21 @@ func getGroupEpochRewards(vmRunner vm.EVMRunner, group common.Address, maxReward
 	return groupEpochRewards, nil
 }
 
-func DistributeEpochRewards(vmRunner vm.EVMRunner, groups []common.Address, maxTotalRewards *big.Int, uptimes map[common.Address][]*big.Int) (*big.Int, error) {
+// DistributeEpochRewards adds the voter rewards of each of the given groups to their
+// vote totals. It returns the total amount distributed along with the reward of each
+// group, in the same order as groups.
+func DistributeEpochRewards(vmRunner vm.EVMRunner, groups []common.Address, maxTotalRewards *big.Int, uptimes map[common.Address][]*big.Int) (*big.Int, []*big.Int, error) {
 	totalRewards := big.NewInt(0)
 	voteTotals, err := getTotalVotesForEligibleValidatorGroups(vmRunner)
 	if err != nil {
-		return totalRewards, err
+		return totalRewards, nil, err
 	}
 
 	rewards := make([]*big.Int, len(groups))
 	for i, group := range groups {
 		reward, err := getGroupEpochRewards(vmRunner, group, maxTotalRewards, uptimes[group])
 		if err != nil {
-			return totalRewards, err
+			return totalRewards, nil, err
 		}
 		rewards[i] = reward
 		log.Debug("Reward for group voters", "reward", reward, "group", group.String())
@@ -155,9 +172,9 @@ func DistributeEpochRewards(vmRunner vm.EVMRunner, groups []common.Address, maxT
 		}
 		err := distributeEpochRewardsMethod.Execute(vmRunner, nil, common.Big0, group, reward, lesser, greater)
 		if err != nil {
-			return totalRewards, err
+			return totalRewards, nil, err
 		}
 		totalRewards.Add(totalRewards, reward)
 	}
-	return totalRewards, nil
+	return totalRewards, rewards, nil
 }
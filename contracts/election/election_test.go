@@ -24,6 +24,11 @@ func TestGetTotalVotesForEligibleValidatorGroups(t *testing.T) {
 	testutil.TestFailsWhenContractNotDeployed(t, contracts.ErrSmartContractNotDeployed, getTotalVotesForEligibleValidatorGroups)
 }
 
+func TestGetGroupVoteTotals(t *testing.T) {
+	testutil.TestFailOnFailingRunner(t, GetGroupVoteTotals)
+	testutil.TestFailsWhenContractNotDeployed(t, contracts.ErrSmartContractNotDeployed, GetGroupVoteTotals)
+}
+
 func TestGetGroupEpochRewards(t *testing.T) {
 	testutil.TestFailOnFailingRunner(t, getGroupEpochRewards, common.HexToAddress("0x05"), big.NewInt(10), []*big.Int{})
 	testutil.TestFailsWhenContractNotDeployed(t, contracts.ErrSmartContractNotDeployed, getGroupEpochRewards, common.HexToAddress("0x05"), big.NewInt(10), []*big.Int{})
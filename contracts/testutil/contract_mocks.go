@@ -6,6 +6,7 @@ import (
 	"github.com/celo-org/celo-blockchain/common"
 	"github.com/celo-org/celo-blockchain/contracts/abis"
 	"github.com/celo-org/celo-blockchain/contracts/config"
+	blscrypto "github.com/celo-org/celo-blockchain/crypto/bls"
 )
 
 type BlockchainParametersMock struct {
@@ -42,10 +43,14 @@ func (bp *BlockchainParametersMock) IntrinsicGasForAlternativeFeeCurrency() *big
 
 type FeeCurrencyWhitelistMock struct {
 	ContractMock
+	// Whitelist is the list of whitelisted fee currencies.
+	Whitelist []common.Address
 }
 
 func NewWhitelistMock() *FeeCurrencyWhitelistMock {
-	mock := &FeeCurrencyWhitelistMock{}
+	mock := &FeeCurrencyWhitelistMock{
+		Whitelist: []common.Address{common.HexToAddress("02"), common.HexToAddress("05")},
+	}
 
 	contract := NewContractMock(abis.FeeCurrencyWhitelist, mock)
 	mock.ContractMock = contract
@@ -53,7 +58,7 @@ func NewWhitelistMock() *FeeCurrencyWhitelistMock {
 }
 
 func (bp *FeeCurrencyWhitelistMock) GetWhitelist() []common.Address {
-	return []common.Address{common.HexToAddress("02"), common.HexToAddress("05")}
+	return bp.Whitelist
 }
 
 type ERC20TokenMock struct {
@@ -75,3 +80,128 @@ func (bp *ERC20TokenMock) BalanceOf(addr common.Address) *big.Int {
 func (bp *ERC20TokenMock) DebitGasFees(from common.Address, value *big.Int) {
 	// Does not return anything
 }
+
+type AccountsMock struct {
+	ContractMock
+	// Accounts maps signers to the account which authorized them. Unknown
+	// signers are their own account.
+	Accounts map[common.Address]common.Address
+}
+
+func NewAccountsMock() *AccountsMock {
+	mock := &AccountsMock{Accounts: make(map[common.Address]common.Address)}
+
+	contract := NewContractMock(abis.Accounts, mock)
+	mock.ContractMock = contract
+	return mock
+}
+
+func (am *AccountsMock) SignerToAccount(signer common.Address) common.Address {
+	if account, ok := am.Accounts[signer]; ok {
+		return account
+	}
+	return signer
+}
+
+type ValidatorsMock struct {
+	ContractMock
+	// Affiliations maps validator accounts to their group.
+	Affiliations map[common.Address]common.Address
+}
+
+func NewValidatorsMock() *ValidatorsMock {
+	mock := &ValidatorsMock{Affiliations: make(map[common.Address]common.Address)}
+
+	contract := NewContractMock(abis.Validators, mock)
+	mock.ContractMock = contract
+	return mock
+}
+
+func (vm *ValidatorsMock) GetValidator(account common.Address) ([]byte, []byte, common.Address, *big.Int, common.Address) {
+	return make([]byte, 64), make([]byte, blscrypto.PUBLICKEYBYTES), vm.Affiliations[account], big.NewInt(0), account
+}
+
+type ElectionMock struct {
+	ContractMock
+	Elected    []common.Address
+	Groups     []common.Address
+	GroupVotes []*big.Int
+}
+
+func NewElectionMock() *ElectionMock {
+	mock := &ElectionMock{}
+
+	contract := NewContractMock(abis.Elections, mock)
+	mock.ContractMock = contract
+	return mock
+}
+
+func (em *ElectionMock) GetElectableValidators() (*big.Int, *big.Int) {
+	return big.NewInt(1), big.NewInt(int64(len(em.Elected)))
+}
+
+func (em *ElectionMock) ElectNValidatorSigners(min, max *big.Int) []common.Address {
+	return em.Elected
+}
+
+func (em *ElectionMock) GetTotalVotesForEligibleValidatorGroups() ([]common.Address, []*big.Int) {
+	return em.Groups, em.GroupVotes
+}
+
+// ERC20MetadataMock is an ERC20 token exposing its symbol and decimals.
+type ERC20MetadataMock struct {
+	ContractMock
+	SymbolValue   string
+	DecimalsValue uint8
+	// Balances maps holders to their balance, unknown holders have none.
+	Balances map[common.Address]*big.Int
+}
+
+func NewERC20MetadataMock(symbol string, decimals uint8) *ERC20MetadataMock {
+	mock := &ERC20MetadataMock{
+		SymbolValue:   symbol,
+		DecimalsValue: decimals,
+		Balances:      make(map[common.Address]*big.Int),
+	}
+
+	contract := NewContractMock(abis.ERC20, mock)
+	mock.ContractMock = contract
+	return mock
+}
+
+func (em *ERC20MetadataMock) Symbol() string {
+	return em.SymbolValue
+}
+
+func (em *ERC20MetadataMock) Decimals() uint8 {
+	return em.DecimalsValue
+}
+
+func (em *ERC20MetadataMock) BalanceOf(addr common.Address) *big.Int {
+	if balance, ok := em.Balances[addr]; ok {
+		return balance
+	}
+	return big.NewInt(0)
+}
+
+type SortedOraclesMock struct {
+	ContractMock
+	// Rates maps tokens to the numerator and denominator of their median
+	// rate. Unknown tokens are worth one CELO.
+	Rates map[common.Address][2]*big.Int
+}
+
+func NewSortedOraclesMock() *SortedOraclesMock {
+	mock := &SortedOraclesMock{Rates: make(map[common.Address][2]*big.Int)}
+
+	contract := NewContractMock(abis.SortedOracles, mock)
+	mock.ContractMock = contract
+	return mock
+}
+
+func (so *SortedOraclesMock) MedianRate(token common.Address) (*big.Int, *big.Int) {
+	if rate, ok := so.Rates[token]; ok {
+		return rate[0], rate[1]
+	}
+	return big.NewInt(1), big.NewInt(1)
+}
@@ -42,6 +42,7 @@ const (
 	maxGasForGetMembershipInLastEpoch uint64 = 1 * n.Million
 	maxGasForGetRegisteredValidators  uint64 = 2 * n.Million
 	maxGasForGetValidator             uint64 = 100 * n.Thousand
+	maxGasForSignerToAccount          uint64 = 100 * n.Thousand
 	maxGasForUpdateValidatorScore     uint64 = 1 * n.Million
 )
 
@@ -53,6 +54,7 @@ var (
 	getValidatorMethod                       = contracts.NewRegisteredContractMethod(config.ValidatorsRegistryId, abis.Validators, "getValidator", maxGasForGetValidator)
 	updateValidatorScoreFromSignerMethod     = contracts.NewRegisteredContractMethod(config.ValidatorsRegistryId, abis.Validators, "updateValidatorScoreFromSigner", maxGasForUpdateValidatorScore)
 	distributeEpochPaymentsFromSignerMethod  = contracts.NewRegisteredContractMethod(config.ValidatorsRegistryId, abis.Validators, "distributeEpochPaymentsFromSigner", maxGasForDistributeEpochPayment)
+	signerToAccountMethod                    = contracts.NewRegisteredContractMethod(config.AccountsRegistryId, abis.Accounts, "signerToAccount", maxGasForSignerToAccount)
 )
 
 func RetrieveRegisteredValidatorSigners(vmRunner vm.EVMRunner) ([]common.Address, error) {
@@ -87,6 +89,20 @@ func GetValidator(vmRunner vm.EVMRunner, validatorAddress common.Address) (Valid
 	return validator, nil
 }
 
+// GetAffiliationFromSigner returns the validator group the validator authorized
+// with the given signer is currently affiliated with.
+func GetAffiliationFromSigner(vmRunner vm.EVMRunner, signer common.Address) (common.Address, error) {
+	var account common.Address
+	if err := signerToAccountMethod.Query(vmRunner, &account, signer); err != nil {
+		return common.ZeroAddress, err
+	}
+	validator, err := GetValidator(vmRunner, account)
+	if err != nil {
+		return common.ZeroAddress, err
+	}
+	return validator.Affiliation, nil
+}
+
 func GetValidatorData(vmRunner vm.EVMRunner, validatorAddresses []common.Address) ([]istanbul.ValidatorData, error) {
 	var validatorData []istanbul.ValidatorData
 	for _, addr := range validatorAddresses {
@@ -0,0 +1,43 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/contracts"
+	"github.com/celo-org/celo-blockchain/contracts/config"
+	"github.com/celo-org/celo-blockchain/contracts/testutil"
+)
+
+func TestGetAffiliationFromSigner(t *testing.T) {
+	signer := common.HexToAddress("0x51")
+	testutil.TestFailOnFailingRunner(t, GetAffiliationFromSigner, signer)
+	testutil.TestFailsWhenContractNotDeployed(t, contracts.ErrSmartContractNotDeployed, GetAffiliationFromSigner, signer)
+
+	var (
+		account = common.HexToAddress("0xa1")
+		group   = common.HexToAddress("0x61")
+	)
+	runner := testutil.NewMockEVMRunner()
+	registry := testutil.NewRegistryMock()
+	runner.RegisterContract(config.RegistrySmartContractAddress, registry)
+
+	accounts := testutil.NewAccountsMock()
+	accounts.Accounts[signer] = account
+	registry.AddContract(config.AccountsRegistryId, common.HexToAddress("0x10"))
+	runner.RegisterContract(common.HexToAddress("0x10"), accounts)
+
+	validators := testutil.NewValidatorsMock()
+	validators.Affiliations[account] = group
+	registry.AddContract(config.ValidatorsRegistryId, common.HexToAddress("0x11"))
+	runner.RegisterContract(common.HexToAddress("0x11"), validators)
+
+	// The affiliation is looked up by the account authorizing the signer
+	affiliation, err := GetAffiliationFromSigner(runner, signer)
+	if err != nil {
+		t.Fatalf("failed to get affiliation: %v", err)
+	}
+	if affiliation != group {
+		t.Fatalf("affiliation mismatch: have %v, want %v", affiliation, group)
+	}
+}
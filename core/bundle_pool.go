@@ -0,0 +1,275 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/contracts/currency"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/crypto"
+	"github.com/celo-org/celo-blockchain/metrics"
+	"github.com/celo-org/celo-blockchain/params"
+)
+
+const (
+	// bundleHorizon is the number of blocks past the head a bundle may target.
+	bundleHorizon = 16
+
+	// bundleSenderLimit is the maximum number of pooled bundles carrying
+	// transactions from the same sender.
+	bundleSenderLimit = 16
+)
+
+var (
+	// ErrEmptyBundle is returned if a bundle without transactions is submitted.
+	ErrEmptyBundle = errors.New("empty bundle")
+
+	// ErrBundleKnown is returned if the bundle is already contained in the pool.
+	ErrBundleKnown = errors.New("bundle already known")
+
+	// ErrBundleUnderpriced is returned if the bundle pool is full and the bundle
+	// doesn't pay more than the least profitable pooled one.
+	ErrBundleUnderpriced = errors.New("bundle underpriced")
+
+	// ErrBundleExpired is returned if the bundle targets a block older than the
+	// current head.
+	ErrBundleExpired = errors.New("bundle expired")
+
+	// ErrBundleTooFar is returned if the bundle targets a block too far past the
+	// current head.
+	ErrBundleTooFar = errors.New("bundle block number too far in the future")
+
+	// ErrBundleSenderLimit is returned if a sender of the bundle already has too
+	// many bundles in the pool.
+	ErrBundleSenderLimit = errors.New("too many bundles from sender")
+)
+
+var bundleGauge = metrics.NewRegisteredGauge("bundlepool/bundles", nil)
+
+// Bundle is an ordered list of transactions that must be included together at
+// the top of a block, or not at all.
+type Bundle struct {
+	Txs          types.Transactions
+	BlockNumber  *big.Int // Number of the only block the bundle may be included in
+	MinTimestamp uint64   // Earliest block timestamp the bundle may be included at, unchecked if zero
+	MaxTimestamp uint64   // Latest block timestamp the bundle may be included at, unchecked if zero
+
+	hash    common.Hash
+	price   *big.Int         // Tip per unit of gas offered in CELO, set when pooled
+	senders []common.Address // Distinct senders of the transactions, set when pooled
+}
+
+// Hash returns the hash of the bundle, derived from the hashes of its
+// transactions.
+func (b *Bundle) Hash() common.Hash {
+	if b.hash == (common.Hash{}) {
+		hashes := make([]byte, 0, len(b.Txs)*common.HashLength)
+		for _, tx := range b.Txs {
+			hashes = append(hashes, tx.Hash().Bytes()...)
+		}
+		b.hash = crypto.Keccak256Hash(hashes)
+	}
+	return b.hash
+}
+
+// offeredPrice returns the tip per unit of gas the bundle offers in CELO, the
+// tip caps of its transactions weighted by their gas limits.
+func (b *Bundle) offeredPrice(currencies currency.Provider) (*big.Int, error) {
+	var (
+		tips = new(big.Int)
+		gas  = new(big.Int)
+	)
+	for _, tx := range b.Txs {
+		feeCurrency, err := currencies.GetCurrency(tx.DenominatedFeeCurrency())
+		if err != nil {
+			return nil, err
+		}
+		txGas := new(big.Int).SetUint64(tx.Gas())
+		tips.Add(tips, feeCurrency.ToCELO(new(big.Int).Mul(tx.GasTipCap(), txGas)))
+		gas.Add(gas, txGas)
+	}
+	if gas.Sign() == 0 {
+		return tips, nil
+	}
+	return tips.Div(tips, gas), nil
+}
+
+// includable returns whether the bundle may be included in a block with the
+// given header.
+func (b *Bundle) includable(header *types.Header) bool {
+	if b.BlockNumber.Cmp(header.Number) != 0 {
+		return false
+	}
+	if b.MinTimestamp != 0 && header.Time < b.MinTimestamp {
+		return false
+	}
+	return b.MaxTimestamp == 0 || header.Time <= b.MaxTimestamp
+}
+
+// expired returns whether the bundle can no longer be included in a block with
+// the given header or any later one.
+func (b *Bundle) expired(header *types.Header) bool {
+	if b.BlockNumber.Cmp(header.Number) < 0 {
+		return true
+	}
+	return b.MaxTimestamp != 0 && header.Time > b.MaxTimestamp
+}
+
+// BundlePool holds the bundles submitted to this node for inclusion by its
+// miner, in arrival order. Bundles are never propagated to other nodes. Once
+// the pool is full, the least profitable bundles make room for better paying
+// ones.
+type BundlePool struct {
+	signer types.Signer
+
+	mu      sync.Mutex
+	bundles []*Bundle
+	known   map[common.Hash]struct{}
+	senders map[common.Address]int // Number of pooled bundles per transaction sender
+
+	limit       int    // Maximum number of pooled bundles
+	senderLimit int    // Maximum number of pooled bundles per transaction sender
+	horizon     uint64 // Number of blocks past the head bundles may target
+}
+
+// NewBundlePool creates a bundle pool holding at most limit bundles.
+func NewBundlePool(chainconfig *params.ChainConfig, limit int) *BundlePool {
+	return &BundlePool{
+		signer:      types.LatestSigner(chainconfig),
+		known:       make(map[common.Hash]struct{}),
+		senders:     make(map[common.Address]int),
+		limit:       limit,
+		senderLimit: bundleSenderLimit,
+		horizon:     bundleHorizon,
+	}
+}
+
+// Add inserts a bundle into the pool, unless it already expired or targets a
+// block too far past the given head. If the pool is full, the least profitable
+// bundle is evicted in favour of a better paying one, with the tips converted
+// to CELO using the given currencies.
+func (pool *BundlePool) Add(bundle *Bundle, head *types.Header, currencies currency.Provider) error {
+	if len(bundle.Txs) == 0 {
+		return ErrEmptyBundle
+	}
+	if bundle.BlockNumber.Cmp(head.Number) <= 0 {
+		return ErrBundleExpired
+	}
+	if bundle.BlockNumber.Cmp(new(big.Int).Add(head.Number, new(big.Int).SetUint64(pool.horizon))) > 0 {
+		return ErrBundleTooFar
+	}
+	price, err := bundle.offeredPrice(currencies)
+	if err != nil {
+		return err
+	}
+	var (
+		senders []common.Address
+		seen    = make(map[common.Address]struct{})
+	)
+	for _, tx := range bundle.Txs {
+		from, err := types.Sender(pool.signer, tx)
+		if err != nil {
+			return err
+		}
+		if _, ok := seen[from]; !ok {
+			seen[from] = struct{}{}
+			senders = append(senders, from)
+		}
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	hash := bundle.Hash()
+	if _, ok := pool.known[hash]; ok {
+		return ErrBundleKnown
+	}
+	for _, from := range senders {
+		if pool.senders[from] >= pool.senderLimit {
+			return ErrBundleSenderLimit
+		}
+	}
+	if len(pool.bundles) >= pool.limit {
+		cheapest := 0
+		for i, pooled := range pool.bundles {
+			if pooled.price.Cmp(pool.bundles[cheapest].price) < 0 {
+				cheapest = i
+			}
+		}
+		if price.Cmp(pool.bundles[cheapest].price) <= 0 {
+			return ErrBundleUnderpriced
+		}
+		pool.remove(cheapest)
+	}
+	bundle.price, bundle.senders = price, senders
+	pool.bundles = append(pool.bundles, bundle)
+	pool.known[hash] = struct{}{}
+	for _, from := range senders {
+		pool.senders[from]++
+	}
+	bundleGauge.Update(int64(len(pool.bundles)))
+	return nil
+}
+
+// remove drops the bundle at the given index, keeping the arrival order of the
+// others. The pool lock must be held.
+func (pool *BundlePool) remove(i int) {
+	pool.forget(pool.bundles[i])
+	copy(pool.bundles[i:], pool.bundles[i+1:])
+	pool.bundles[len(pool.bundles)-1] = nil
+	pool.bundles = pool.bundles[:len(pool.bundles)-1]
+}
+
+// forget releases the hash and sender accounting of a dropped bundle. The pool
+// lock must be held.
+func (pool *BundlePool) forget(bundle *Bundle) {
+	delete(pool.known, bundle.Hash())
+	for _, from := range bundle.senders {
+		if pool.senders[from]--; pool.senders[from] == 0 {
+			delete(pool.senders, from)
+		}
+	}
+}
+
+// Bundles returns the bundles that may be included in a block with the given
+// header, the most profitable first and in arrival order among equally paying
+// ones, and drops the ones that expired.
+func (pool *BundlePool) Bundles(header *types.Header) []*Bundle {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	var (
+		includable []*Bundle
+		kept       = pool.bundles[:0]
+	)
+	for _, bundle := range pool.bundles {
+		if bundle.expired(header) {
+			pool.forget(bundle)
+			continue
+		}
+		kept = append(kept, bundle)
+		if bundle.includable(header) {
+			includable = append(includable, bundle)
+		}
+	}
+	for i := len(kept); i < len(pool.bundles); i++ {
+		pool.bundles[i] = nil
+	}
+	pool.bundles = kept
+	bundleGauge.Update(int64(len(pool.bundles)))
+
+	sort.SliceStable(includable, func(i, j int) bool {
+		return includable[i].price.Cmp(includable[j].price) > 0
+	})
+	return includable
+}
+
+// Len returns the number of bundles in the pool.
+func (pool *BundlePool) Len() int {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return len(pool.bundles)
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/contracts/currency"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/crypto"
+	"github.com/celo-org/celo-blockchain/params"
+)
+
+func TestBundlePool(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	newBundle := func(nonce uint64, number int64, minTime, maxTime uint64) *Bundle {
+		return &Bundle{
+			Txs:          types.Transactions{transaction(nonce, 100000, key)},
+			BlockNumber:  big.NewInt(number),
+			MinTimestamp: minTime,
+			MaxTimestamp: maxTime,
+		}
+	}
+	head := &types.Header{Number: big.NewInt(10), Time: 100}
+	currencies := currency.NewCacheOnlyManager(nil)
+	pool := NewBundlePool(params.TestChainConfig, 3)
+
+	if err := pool.Add(&Bundle{BlockNumber: big.NewInt(11)}, head, currencies); !errors.Is(err, ErrEmptyBundle) {
+		t.Fatalf("empty bundle error mismatch: have %v, want %v", err, ErrEmptyBundle)
+	}
+	if err := pool.Add(newBundle(0, 10, 0, 0), head, currencies); !errors.Is(err, ErrBundleExpired) {
+		t.Fatalf("stale bundle error mismatch: have %v, want %v", err, ErrBundleExpired)
+	}
+	if err := pool.Add(newBundle(0, 11+bundleHorizon, 0, 0), head, currencies); !errors.Is(err, ErrBundleTooFar) {
+		t.Fatalf("far bundle error mismatch: have %v, want %v", err, ErrBundleTooFar)
+	}
+	var (
+		next    = newBundle(0, 11, 0, 0)
+		late    = newBundle(1, 11, 105, 0)
+		further = newBundle(2, 10+bundleHorizon, 0, 110)
+	)
+	for i, bundle := range []*Bundle{next, late, further} {
+		if err := pool.Add(bundle, head, currencies); err != nil {
+			t.Fatalf("bundle %d: failed to add: %v", i, err)
+		}
+	}
+	if err := pool.Add(newBundle(0, 11, 0, 0), head, currencies); !errors.Is(err, ErrBundleKnown) {
+		t.Fatalf("known bundle error mismatch: have %v, want %v", err, ErrBundleKnown)
+	}
+	if err := pool.Add(newBundle(3, 11, 0, 0), head, currencies); !errors.Is(err, ErrBundleUnderpriced) {
+		t.Fatalf("overflow bundle error mismatch: have %v, want %v", err, ErrBundleUnderpriced)
+	}
+	// Only the bundles whose bounds match the block are returned
+	bundles := pool.Bundles(&types.Header{Number: big.NewInt(11), Time: 101})
+	if len(bundles) != 1 || bundles[0] != next {
+		t.Fatalf("includable bundles mismatch: have %v, want [%x]", bundles, next.Hash())
+	}
+	bundles = pool.Bundles(&types.Header{Number: big.NewInt(11), Time: 105})
+	if len(bundles) != 2 || bundles[0] != next || bundles[1] != late {
+		t.Fatalf("includable bundles mismatch: have %d, want 2", len(bundles))
+	}
+	if pool.Len() != 3 {
+		t.Fatalf("pool size mismatch: have %d, want 3", pool.Len())
+	}
+	// Bundles past their block or timestamp are dropped
+	if bundles = pool.Bundles(&types.Header{Number: big.NewInt(12), Time: 111}); len(bundles) != 0 {
+		t.Fatalf("expired bundles returned: %d", len(bundles))
+	}
+	if pool.Len() != 0 {
+		t.Fatalf("pool size mismatch: have %d, want 0", pool.Len())
+	}
+	if err := pool.Add(newBundle(0, 13, 0, 0), &types.Header{Number: big.NewInt(12)}, currencies); err != nil {
+		t.Fatalf("failed to re-add dropped bundle: %v", err)
+	}
+}
+
+// Tests that a full bundle pool evicts its least profitable bundle for a better
+// paying one, comparing tips in CELO, and returns bundles by profitability.
+func TestBundlePoolEviction(t *testing.T) {
+	var (
+		key, _      = crypto.GenerateKey()
+		feeCurrency = common.HexToAddress("0xcafe")
+		head        = &types.Header{Number: big.NewInt(10)}
+		block       = &types.Header{Number: big.NewInt(11)}
+	)
+	// One CELO is worth two units of the fee currency
+	rate, _ := currency.NewExchangeRate(big.NewInt(2), big.NewInt(1))
+	currencies := currency.NewCacheOnlyManager(map[common.Address]*currency.Currency{
+		feeCurrency: currency.NewCurrency(feeCurrency, *rate),
+	})
+	newBundle := func(nonce uint64, tip int64, feeCurrency *common.Address) *Bundle {
+		tx, _ := types.SignTx(types.NewCeloTransaction(nonce, common.Address{}, big.NewInt(100), 100000, big.NewInt(tip), feeCurrency, nil, nil, nil), types.HomesteadSigner{}, key)
+		return &Bundle{Txs: types.Transactions{tx}, BlockNumber: block.Number}
+	}
+	pool := NewBundlePool(params.TestChainConfig, 2)
+
+	cheap, pricey := newBundle(0, 10, &feeCurrency), newBundle(1, 20, nil)
+	for i, bundle := range []*Bundle{cheap, pricey} {
+		if err := pool.Add(bundle, head, currencies); err != nil {
+			t.Fatalf("bundle %d: failed to add: %v", i, err)
+		}
+	}
+	// A tip of 10 in the fee currency is worth 5 CELO, so paying 5 CELO isn't enough
+	if err := pool.Add(newBundle(2, 5, nil), head, currencies); !errors.Is(err, ErrBundleUnderpriced) {
+		t.Fatalf("underpriced bundle error mismatch: have %v, want %v", err, ErrBundleUnderpriced)
+	}
+	better := newBundle(2, 6, nil)
+	if err := pool.Add(better, head, currencies); err != nil {
+		t.Fatalf("failed to add better paying bundle: %v", err)
+	}
+	bundles := pool.Bundles(block)
+	if len(bundles) != 2 || bundles[0] != pricey || bundles[1] != better {
+		t.Fatalf("includable bundles mismatch: have %d, want [pricey, better]", len(bundles))
+	}
+}
+
+// Tests that the number of pooled bundles per transaction sender is limited,
+// and that dropped bundles release their share.
+func TestBundlePoolSenderLimit(t *testing.T) {
+	var (
+		key, _     = crypto.GenerateKey()
+		other, _   = crypto.GenerateKey()
+		head       = &types.Header{Number: big.NewInt(10)}
+		currencies = currency.NewCacheOnlyManager(nil)
+	)
+	pool := NewBundlePool(params.TestChainConfig, 16)
+	pool.senderLimit = 2
+
+	for i := uint64(0); i < 2; i++ {
+		if err := pool.Add(&Bundle{Txs: types.Transactions{transaction(i, 100000, key)}, BlockNumber: big.NewInt(11)}, head, currencies); err != nil {
+			t.Fatalf("bundle %d: failed to add: %v", i, err)
+		}
+	}
+	// Bundles carrying a transaction of the sender are rejected, whatever the others
+	bundle := &Bundle{Txs: types.Transactions{transaction(0, 100000, other), transaction(2, 100000, key)}, BlockNumber: big.NewInt(12)}
+	if err := pool.Add(bundle, head, currencies); !errors.Is(err, ErrBundleSenderLimit) {
+		t.Fatalf("sender limit error mismatch: have %v, want %v", err, ErrBundleSenderLimit)
+	}
+	if err := pool.Add(&Bundle{Txs: types.Transactions{transaction(0, 100000, other)}, BlockNumber: big.NewInt(12)}, head, currencies); err != nil {
+		t.Fatalf("failed to add bundle of another sender: %v", err)
+	}
+	// Once the first bundles expire, the sender may submit again
+	pool.Bundles(&types.Header{Number: big.NewInt(12)})
+	if err := pool.Add(bundle, &types.Header{Number: big.NewInt(11)}, currencies); err != nil {
+		t.Fatalf("failed to add bundle after expiry: %v", err)
+	}
+}
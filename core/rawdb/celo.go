@@ -2,6 +2,7 @@ package rawdb
 
 import (
 	"bytes"
+	"encoding/binary"
 	"math/big"
 
 	"github.com/celo-org/celo-blockchain/common"
@@ -11,7 +12,12 @@ import (
 	"github.com/celo-org/celo-blockchain/rlp"
 )
 
-var genesisSupplyKey = []byte("genesis-supply-genesis")
+var (
+	genesisSupplyKey   = []byte("genesis-supply-genesis")
+	epochRewardsPrefix = []byte("celo-epoch-rewards-")
+	callTracesPrefix   = []byte("celo-call-traces-")
+	traceIndexTailKey  = []byte("celo-trace-index-tail")
+)
 
 // ReadGenesisCeloSupply retrieves a CELO token supply at genesis
 func ReadGenesisCeloSupply(db ethdb.KeyValueReader) *big.Int {
@@ -56,6 +62,80 @@ func randomnessCommitmentKey(commitment common.Hash) []byte {
 	return append(dbRandomnessPrefix, commitment.Bytes()...)
 }
 
+// WriteEpochRewards stores the rewards breakdown computed while finalizing the last
+// block of an epoch. Entries are keyed by the resulting state root so that blocks
+// which never made it into the chain cannot shadow the canonical one.
+func WriteEpochRewards(db ethdb.KeyValueWriter, epoch uint64, root common.Hash, rewards *types.EpochRewards) {
+	data, err := rlp.EncodeToBytes(rewards)
+	if err != nil {
+		log.Crit("Failed to RLP encode epoch rewards", "err", err)
+	}
+	if err := db.Put(epochRewardsKey(epoch, root), data); err != nil {
+		log.Crit("Failed to store epoch rewards", "err", err)
+	}
+}
+
+// ReadEpochRewards retrieves the rewards breakdown of the given epoch, or nil if it
+// was not recorded.
+func ReadEpochRewards(db ethdb.KeyValueReader, epoch uint64, root common.Hash) *types.EpochRewards {
+	data, _ := db.Get(epochRewardsKey(epoch, root))
+	if len(data) == 0 {
+		return nil
+	}
+	rewards := new(types.EpochRewards)
+	if err := rlp.DecodeBytes(data, rewards); err != nil {
+		log.Error("Invalid epoch rewards RLP", "epoch", epoch, "err", err)
+		return nil
+	}
+	return rewards
+}
+
+// epochRewardsKey = epochRewardsPrefix + epoch (uint64 big endian) + state root
+func epochRewardsKey(epoch uint64, root common.Hash) []byte {
+	return append(append(epochRewardsPrefix, encodeBlockNumber(epoch)...), root.Bytes()...)
+}
+
+// WriteCallTraces stores the encoded flattened call traces of a block.
+func WriteCallTraces(db ethdb.KeyValueWriter, number uint64, hash common.Hash, traces []byte) {
+	if err := db.Put(callTracesKey(number, hash), traces); err != nil {
+		log.Crit("Failed to store call traces", "err", err)
+	}
+}
+
+// ReadCallTraces retrieves the encoded flattened call traces of a block, or nil
+// if the block was not indexed.
+func ReadCallTraces(db ethdb.KeyValueReader, number uint64, hash common.Hash) []byte {
+	data, _ := db.Get(callTracesKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	return data
+}
+
+// ReadTraceIndexTail retrieves the number of the oldest block whose call traces
+// are indexed, or nil if the trace index was never started.
+func ReadTraceIndexTail(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(traceIndexTailKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteTraceIndexTail stores the number of the oldest block whose call traces
+// are indexed.
+func WriteTraceIndexTail(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(traceIndexTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the trace index tail", "err", err)
+	}
+}
+
+// callTracesKey = callTracesPrefix + num (uint64 big endian) + hash
+func callTracesKey(number uint64, hash common.Hash) []byte {
+	return append(append(callTracesPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
 // Extra hash comparison is necessary since ancient database only maintains
 // the canonical data.
 func headerHash(data []byte) common.Hash {
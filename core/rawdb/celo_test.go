@@ -3,6 +3,9 @@ package rawdb
 import (
 	"math/big"
 	"testing"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/core/types"
 )
 
 // Tests Genesis CELO supply storage and retrieval operations.
@@ -25,3 +28,68 @@ func TestGenesisCeloSupply(t *testing.T) {
 		t.Fatalf("Retrieved CELO token supply mismatch: have %v, want %v", supply, initialSupply)
 	}
 }
+
+// Tests epoch rewards storage and retrieval operations.
+func TestEpochRewardsStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+	root := common.HexToHash("0x01")
+
+	if rewards := ReadEpochRewards(db, 3, root); rewards != nil {
+		t.Fatalf("Non existent epoch rewards returned: %v", rewards)
+	}
+
+	rewards := &types.EpochRewards{
+		Epoch:                 3,
+		TargetValidatorReward: big.NewInt(100),
+		Validators: []types.ValidatorEpochReward{
+			{Validator: common.HexToAddress("0x10"), Group: common.HexToAddress("0x20"), Reward: big.NewInt(90)},
+		},
+		CommunityReward: big.NewInt(7),
+		ReserveFallback: true,
+	}
+	WriteEpochRewards(db, 3, root, rewards)
+
+	if stored := ReadEpochRewards(db, 3, common.HexToHash("0x02")); stored != nil {
+		t.Fatalf("Epoch rewards returned for a different state root")
+	}
+	stored := ReadEpochRewards(db, 3, root)
+	if stored == nil {
+		t.Fatalf("Stored epoch rewards not found")
+	}
+	if stored.Epoch != 3 || !stored.ReserveFallback || stored.CommunityReward.Cmp(big.NewInt(7)) != 0 {
+		t.Fatalf("Retrieved epoch rewards mismatch: have %+v, want %+v", stored, rewards)
+	}
+	if len(stored.Validators) != 1 || stored.Validators[0].Reward.Cmp(big.NewInt(90)) != 0 {
+		t.Fatalf("Retrieved validator rewards mismatch: have %+v", stored.Validators)
+	}
+}
+
+// Tests call traces storage and retrieval operations.
+func TestCallTracesStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+	hash := common.HexToHash("0x01")
+
+	if traces := ReadCallTraces(db, 5, hash); traces != nil {
+		t.Fatalf("Non existent call traces returned: %s", traces)
+	}
+	WriteCallTraces(db, 5, hash, []byte("[]"))
+	if traces := ReadCallTraces(db, 5, common.HexToHash("0x02")); traces != nil {
+		t.Fatalf("Call traces returned for a different block hash")
+	}
+	if traces := ReadCallTraces(db, 5, hash); string(traces) != "[]" {
+		t.Fatalf("Retrieved call traces mismatch: have %s, want []", traces)
+	}
+}
+
+// Tests trace index tail storage and retrieval operations.
+func TestTraceIndexTailStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if tail := ReadTraceIndexTail(db); tail != nil {
+		t.Fatalf("Non existent trace index tail returned: %d", *tail)
+	}
+	WriteTraceIndexTail(db, 64)
+	if tail := ReadTraceIndexTail(db); tail == nil || *tail != 64 {
+		t.Fatalf("Retrieved trace index tail mismatch: have %v, want 64", tail)
+	}
+}
@@ -1046,3 +1046,34 @@ func (s *StateDB) AddressInAccessList(addr common.Address) bool {
 func (s *StateDB) SlotInAccessList(addr common.Address, slot common.Hash) (addressPresent bool, slotPresent bool) {
 	return s.accessList.Contains(addr, slot)
 }
+
+// AccessList returns the addresses and storage slots in the access list.
+func (s *StateDB) AccessList() types.AccessList {
+	list := make(types.AccessList, 0, len(s.accessList.addresses))
+	for addr, idx := range s.accessList.addresses {
+		tuple := types.AccessTuple{Address: addr, StorageKeys: []common.Hash{}}
+		if idx >= 0 {
+			for slot := range s.accessList.slots[idx] {
+				tuple.StorageKeys = append(tuple.StorageKeys, slot)
+			}
+		}
+		list = append(list, tuple)
+	}
+	return list
+}
+
+// Dirties returns the accounts modified since the state was last finalised,
+// along with their modified storage slots.
+func (s *StateDB) Dirties() map[common.Address][]common.Hash {
+	dirties := make(map[common.Address][]common.Hash, len(s.journal.dirties))
+	for addr := range s.journal.dirties {
+		var slots []common.Hash
+		if obj, exist := s.stateObjects[addr]; exist {
+			for slot := range obj.dirtyStorage {
+				slots = append(slots, slot)
+			}
+		}
+		dirties[addr] = slots
+	}
+	return dirties
+}
@@ -77,6 +77,27 @@ type StateTransition struct {
 	erc20FeeDebited *big.Int
 }
 
+// TxFees holds the fees charged for a transaction. Amounts are denominated in
+// its fee currency.
+type TxFees struct {
+	FeeCurrency         *common.Address        // Currency the fees were paid in, nil for CELO
+	ExchangeRate        *currency.ExchangeRate // Rate from CELO to the fee currency, nil for CELO
+	Debited             *big.Int               // Amount debited from the sender before execution
+	Refund              *big.Int               // Amount credited back to the sender
+	Tip                 *big.Int               // Amount credited to the coinbase
+	Coinbase            common.Address         // Recipient of the tip
+	BaseFee             *big.Int               // Amount credited to the fee handler
+	FeeHandler          common.Address         // Recipient of the base fee, zero if not deployed
+	GatewayFee          *big.Int               // Amount credited to the gateway fee recipient, nil if none
+	GatewayFeeRecipient common.Address         // Recipient of the gateway fee
+}
+
+// FeeTracer is an EVMLogger also notified of the fees charged for each
+// transaction. Fees are debited and credited outside of the traced calls.
+type FeeTracer interface {
+	CaptureFees(fees *TxFees)
+}
+
 // Message represents a message sent to a contract.
 type Message interface {
 	From() common.Address
@@ -263,6 +284,15 @@ func (st *StateTransition) to() common.Address {
 	return *st.msg.To()
 }
 
+// getExchangeRate retrieves the exchange rate of the fee currency, honouring
+// the overrides of the system contract context if there is one.
+func (st *StateTransition) getExchangeRate() (*currency.ExchangeRate, error) {
+	if st.sysCtx != nil {
+		return st.sysCtx.GetExchangeRate(st.vmRunner, st.msg.FeeCurrency())
+	}
+	return currency.GetExchangeRate(st.vmRunner, st.msg.FeeCurrency())
+}
+
 // payFees deducts gas and gateway fees from sender balance and adds the purchased amount of gas to the state.
 func (st *StateTransition) payFees(espresso bool, feeCurrencyRate *currency.ExchangeRate) error {
 	var isWhiteListed bool
@@ -482,7 +512,7 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	if st.msg.FeeCurrency() == nil && st.msg.MaxFeeInFeeCurrency() != nil {
 		return nil, ErrDenominatedNoCurrency
 	}
-	feeCurrencyRate, err := currency.GetExchangeRate(st.vmRunner, st.msg.FeeCurrency())
+	feeCurrencyRate, err := st.getExchangeRate()
 	if err != nil {
 		return nil, err
 	}
@@ -571,7 +601,8 @@ func (st *StateTransition) creditTxFees(feeCurrencyRate *currency.ExchangeRate)
 	}
 
 	// Run only primary evm.Call() with tracer
-	if st.evm.GetDebug() {
+	debug := st.evm.GetDebug()
+	if debug {
 		st.evm.SetDebug(false)
 		defer func() { st.evm.SetDebug(true) }()
 	}
@@ -605,6 +636,7 @@ func (st *StateTransition) creditTxFees(feeCurrencyRate *currency.ExchangeRate)
 	}
 
 	feeCurrency := st.msg.FeeCurrency()
+	debited := new(big.Int).Add(refund, totalTxFee)
 
 	gatewayFeeRecipient := st.msg.GatewayFeeRecipient()
 	if gatewayFeeRecipient == nil {
@@ -649,6 +681,26 @@ func (st *StateTransition) creditTxFees(feeCurrencyRate *currency.ExchangeRate)
 		}
 
 	}
+	if tracer, ok := st.evm.Config.Tracer.(FeeTracer); ok && debug {
+		fees := &TxFees{
+			FeeCurrency: feeCurrency,
+			Debited:     debited,
+			Refund:      refund,
+			Tip:         tipTxFee,
+			Coinbase:    st.evm.Context.Coinbase,
+			BaseFee:     baseTxFee,
+			FeeHandler:  feeHandlerAddress,
+		}
+		if st.msg.GatewayFeeRecipient() != nil {
+			fees.GatewayFee = st.msg.GatewayFee()
+			fees.GatewayFeeRecipient = *st.msg.GatewayFeeRecipient()
+			debited.Add(debited, fees.GatewayFee)
+		}
+		if feeCurrency != nil {
+			fees.ExchangeRate = feeCurrencyRate
+		}
+		tracer.CaptureFees(fees)
+	}
 	return nil
 }
 
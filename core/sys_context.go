@@ -23,6 +23,9 @@ type SysContractCallCtx struct {
 	// gasPriceMinimums stores values for whitelisted currencies keyed by their contract address
 	// Note that native token(CELO) is keyed by common.ZeroAddress
 	gasPriceMinimums GasPriceMinimums
+	// exchangeRates stores overridden exchange rates keyed by currency contract
+	// address. Currencies without an entry are read from the oracle.
+	exchangeRates map[common.Address]*currency.ExchangeRate
 }
 
 // runnerFactory exists to allow multiple different implementations to be
@@ -99,6 +102,50 @@ func (sc *SysContractCallCtx) GetGasPriceMinimum(feeCurrency *common.Address) *b
 	return sc.gasPriceMinimums.GetGasPriceMinimum(feeCurrency)
 }
 
+// GetExchangeRate retrieves the exchange rate of the given fee currency,
+// reading it from the oracle unless it has been overridden.
+func (sc *SysContractCallCtx) GetExchangeRate(vmRunner vm.EVMRunner, feeCurrency *common.Address) (*currency.ExchangeRate, error) {
+	if feeCurrency != nil {
+		if rate, ok := sc.exchangeRates[*feeCurrency]; ok {
+			return rate, nil
+		}
+	}
+	return currency.GetExchangeRate(vmRunner, feeCurrency)
+}
+
+// OverrideWhitelist replaces the whitelisted currencies. It is meant for
+// simulating calls under hypothetical conditions, never for block processing.
+func (sc *SysContractCallCtx) OverrideWhitelist(whitelist []common.Address) {
+	sc.whitelistedCurrencies = make(map[common.Address]struct{}, len(whitelist))
+	for _, feeCurrency := range whitelist {
+		sc.whitelistedCurrencies[feeCurrency] = struct{}{}
+	}
+}
+
+// OverrideGasPriceMinimum replaces the gas price minimum of the given fee
+// currency, nil being CELO. It is meant for simulating calls under
+// hypothetical conditions, never for block processing.
+func (sc *SysContractCallCtx) OverrideGasPriceMinimum(feeCurrency *common.Address, gasPriceMinimum *big.Int) {
+	key := common.ZeroAddress
+	if feeCurrency != nil {
+		key = *feeCurrency
+	}
+	if sc.gasPriceMinimums == nil {
+		sc.gasPriceMinimums = make(GasPriceMinimums)
+	}
+	sc.gasPriceMinimums[key] = gasPriceMinimum
+}
+
+// OverrideExchangeRate replaces the exchange rate of the given fee currency.
+// It is meant for simulating calls under hypothetical conditions, never for
+// block processing.
+func (sc *SysContractCallCtx) OverrideExchangeRate(feeCurrency common.Address, rate *currency.ExchangeRate) {
+	if sc.exchangeRates == nil {
+		sc.exchangeRates = make(map[common.Address]*currency.ExchangeRate)
+	}
+	sc.exchangeRates[feeCurrency] = rate
+}
+
 // GetCurrentGasPriceMinimumMap returns the gas price minimum map for all whitelisted currencies.
 // Note that the CELO currency is keyed by the Zero address.
 func (sc *SysContractCallCtx) GetCurrentGasPriceMinimumMap() GasPriceMinimums {
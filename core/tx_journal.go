@@ -20,6 +20,7 @@ import (
 	"errors"
 	"io"
 	"os"
+	"time"
 
 	"github.com/celo-org/celo-blockchain/common"
 	"github.com/celo-org/celo-blockchain/core/types"
@@ -145,12 +146,16 @@ func (journal *txJournal) rotate(all map[common.Address]types.Transactions) erro
 	journaled := 0
 	for _, txs := range all {
 		for _, tx := range txs {
+			// Conditions are not journaled, so neither are conditional transactions
+			if tx.Conditional() != nil {
+				continue
+			}
 			if err = rlp.Encode(replacement, tx); err != nil {
 				replacement.Close()
 				return err
 			}
+			journaled++
 		}
-		journaled += len(txs)
 	}
 	replacement.Close()
 
@@ -178,3 +183,137 @@ func (journal *txJournal) close() error {
 	}
 	return err
 }
+
+// remoteJournalEntry is a journaled remote transaction along with the unix
+// time it was first seen, so that its age survives node restarts.
+type remoteJournalEntry struct {
+	Tx   *types.Transaction
+	Time uint64
+}
+
+// remoteTxJournal is a snapshot of the remote transactions of the pool, bounded
+// in size and age, which is regenerated periodically rather than appended to on
+// every insertion. Remote transactions are thus only persisted every Rejournal
+// interval and on shutdown, the ones received since the last rotation are lost
+// if the node crashes.
+type remoteTxJournal struct {
+	path     string                    // Filesystem path to store the transactions at
+	maxSize  uint64                    // Maximum number of transactions to store
+	lifetime time.Duration             // Maximum age of the stored transactions
+	seen     map[common.Hash]time.Time // First seen times of the journaled transactions, which predate their reload
+}
+
+// newRemoteTxJournal creates a new remote transaction journal.
+func newRemoteTxJournal(path string, maxSize uint64, lifetime time.Duration) *remoteTxJournal {
+	return &remoteTxJournal{
+		path:     path,
+		maxSize:  maxSize,
+		lifetime: lifetime,
+		seen:     make(map[common.Hash]time.Time),
+	}
+}
+
+// firstSeen returns the time the transaction was first seen, which is the time
+// recorded in the journal for reloaded transactions.
+func (journal *remoteTxJournal) firstSeen(tx *types.Transaction) time.Time {
+	if seen, ok := journal.seen[tx.Hash()]; ok {
+		return seen
+	}
+	return tx.Time()
+}
+
+// load parses a remote transaction journal dump from disk, remembering the time
+// each transaction was first seen and passing the ones that have not expired to
+// the specified pool.
+func (journal *remoteTxJournal) load(add func([]*types.Transaction) []error) error {
+	// Skip the parsing if the journal file doesn't exist at all
+	if _, err := os.Stat(journal.path); os.IsNotExist(err) {
+		return nil
+	}
+	input, err := os.Open(journal.path)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	stream := rlp.NewStream(input, 0)
+	total, expired, dropped := 0, 0, 0
+
+	loadBatch := func(txs types.Transactions) {
+		for _, err := range add(txs) {
+			if err != nil {
+				log.Debug("Failed to add journaled remote transaction", "err", err)
+				dropped++
+			}
+		}
+	}
+	var (
+		failure error
+		batch   types.Transactions
+	)
+	for {
+		entry := new(remoteJournalEntry)
+		if err = stream.Decode(entry); err != nil {
+			if err != io.EOF {
+				failure = err
+			}
+			if batch.Len() > 0 {
+				loadBatch(batch)
+			}
+			break
+		}
+		total++
+
+		seen := time.Unix(int64(entry.Time), 0)
+		if time.Since(seen) > journal.lifetime {
+			expired++
+			continue
+		}
+		journal.seen[entry.Tx.Hash()] = seen
+		if batch = append(batch, entry.Tx); batch.Len() > 1024 {
+			loadBatch(batch)
+			batch = batch[:0]
+		}
+	}
+	log.Info("Loaded remote transaction journal", "transactions", total, "expired", expired, "dropped", dropped)
+
+	return failure
+}
+
+// rotate regenerates the remote transaction journal from the given transactions,
+// skipping the expired ones and keeping at most maxSize of them in order.
+func (journal *remoteTxJournal) rotate(txs types.Transactions) error {
+	replacement, err := os.OpenFile(journal.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	var (
+		journaled = uint64(0)
+		seen      = make(map[common.Hash]time.Time)
+	)
+	for _, tx := range txs {
+		if journaled >= journal.maxSize {
+			break
+		}
+		first := journal.firstSeen(tx)
+		if time.Since(first) > journal.lifetime || tx.Conditional() != nil {
+			continue
+		}
+		if err = rlp.Encode(replacement, &remoteJournalEntry{Tx: tx, Time: uint64(first.Unix())}); err != nil {
+			replacement.Close()
+			return err
+		}
+		seen[tx.Hash()] = first
+		journaled++
+	}
+	replacement.Close()
+	journal.seen = seen
+
+	// Replace the live journal with the newly generated one
+	if err = os.Rename(journal.path+".new", journal.path); err != nil {
+		return err
+	}
+	log.Info("Regenerated remote transaction journal", "transactions", journaled, "available", len(txs))
+
+	return nil
+}
@@ -299,15 +299,17 @@ func toCurrency(celoAmount *big.Int, feeCurrency *common.Address, txCtx *txPoolC
 	return currency.FromCELO(celoAmount), nil
 }
 
-func toCELO(amount *big.Int, feeCurrency *common.Address, txCtx *txPoolContext) (*big.Int, error) {
-	if feeCurrency == nil {
-		return amount, nil
-	}
-	currency, err := txCtx.GetCurrency(feeCurrency)
-	if err != nil {
-		return nil, err
-	}
-	return currency.ToCELO(amount), nil
+// bumpedPrices returns the fee cap and tip a replacement transaction has to
+// match to replace one paying feeCap and tip, given the price bump percentage.
+func bumpedPrices(feeCap, tip *big.Int, priceBump uint64) (*big.Int, *big.Int) {
+	// thresholdFeeCap = oldFC  * (100 + priceBump) / 100
+	a := big.NewInt(100 + int64(priceBump))
+	aFeeCap := new(big.Int).Mul(a, feeCap)
+	aTip := a.Mul(a, tip)
+
+	// thresholdTip    = oldTip * (100 + priceBump) / 100
+	b := big.NewInt(100)
+	return aFeeCap.Div(aFeeCap, b), aTip.Div(aTip, b)
 }
 
 // Add tries to insert a new transaction into the list, returning whether the
@@ -319,51 +321,35 @@ func (l *txList) Add(tx *types.Transaction, priceBump uint64) (bool, *types.Tran
 	// If there's an older better transaction, abort
 	old := l.txs.Get(tx.Nonce())
 	if old != nil {
-		var oldGasFeeCap, oldGasTipCap *big.Int
-		var newGasFeeCap, newGasTipCap *big.Int
-
 		// Short circuit conversion if both are the same currency
 		if common.AreEqualAddresses(old.DenominatedFeeCurrency(), tx.DenominatedFeeCurrency()) {
 			if old.GasFeeCapCmp(tx) >= 0 || old.GasTipCapCmp(tx) >= 0 {
 				return false, nil
 			}
-			oldGasFeeCap = old.GasFeeCap()
-			oldGasTipCap = old.GasTipCap()
-			newGasFeeCap = tx.GasFeeCap()
-			newGasTipCap = tx.GasTipCap()
-		} else {
-			txCtx := l.ctx.Load().(txPoolContext)
-			// Convert old values into tx fee currency
-			var err error
-			if oldGasFeeCap, err = toCELO(old.GasFeeCap(), old.DenominatedFeeCurrency(), &txCtx); err != nil {
-				return false, nil
-			}
-			if oldGasTipCap, err = toCELO(old.GasTipCap(), old.DenominatedFeeCurrency(), &txCtx); err != nil {
-				return false, nil
-			}
-			if newGasFeeCap, err = toCELO(tx.GasFeeCap(), tx.DenominatedFeeCurrency(), &txCtx); err != nil {
+			thresholdFeeCap, thresholdTip := bumpedPrices(old.GasFeeCap(), old.GasTipCap(), priceBump)
+
+			// Have to ensure that either the new fee cap or tip is higher than the
+			// old ones as well as checking the percentage threshold to ensure that
+			// this is accurate for low (Wei-level) gas price replacements
+			if tx.GasFeeCapIntCmp(thresholdFeeCap) < 0 || tx.GasTipCapIntCmp(thresholdTip) < 0 {
 				return false, nil
 			}
-			if newGasTipCap, err = toCELO(tx.GasTipCap(), tx.DenominatedFeeCurrency(), &txCtx); err != nil {
+		} else {
+			// A replacement in another currency is priced by the tip it would
+			// actually pay at the current gas price minimums, so that a sender
+			// stuck with a transaction in a depegged or illiquid currency can
+			// still rescue the nonce. Both thresholds stay in the old currency
+			// and are compared through the exchange rates.
+			txCtx := l.ctx.Load().(txPoolContext)
+			oldCurrency, newCurrency := old.DenominatedFeeCurrency(), tx.DenominatedFeeCurrency()
+			oldTip := old.EffectiveGasTipValue(txCtx.GetGasPriceMinimum(oldCurrency))
+			newTip := tx.EffectiveGasTipValue(txCtx.GetGasPriceMinimum(newCurrency))
+			thresholdFeeCap, thresholdTip := bumpedPrices(old.GasFeeCap(), oldTip, priceBump)
+
+			if txCtx.CmpValues(tx.GasFeeCap(), newCurrency, thresholdFeeCap, oldCurrency) < 0 ||
+				txCtx.CmpValues(newTip, newCurrency, thresholdTip, oldCurrency) < 0 {
 				return false, nil
 			}
-
-		}
-		// thresholdFeeCap = oldFC  * (100 + priceBump) / 100
-		a := big.NewInt(100 + int64(priceBump))
-		aFeeCap := new(big.Int).Mul(a, oldGasFeeCap)
-		aTip := a.Mul(a, oldGasTipCap)
-
-		// thresholdTip    = oldTip * (100 + priceBump) / 100
-		b := big.NewInt(100)
-		thresholdFeeCap := aFeeCap.Div(aFeeCap, b)
-		thresholdTip := aTip.Div(aTip, b)
-
-		// Have to ensure that either the new fee cap or tip is higher than the
-		// old ones as well as checking the percentage threshold to ensure that
-		// this is accurate for low (Wei-level) gas price replacements
-		if newGasFeeCap.Cmp(thresholdFeeCap) < 0 || newGasTipCap.Cmp(thresholdTip) < 0 {
-			return false, nil
 		}
 	}
 	// Otherwise overwrite the old transaction with the current one
@@ -91,6 +91,18 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	// ErrSenderBanned is returned if the sender of a transaction has been banned
+	// from the pool by the node operator.
+	ErrSenderBanned = errors.New("sender banned")
+
+	// ErrConditionalExpired is returned if the block number or timestamp bounds
+	// of a conditional transaction have already passed.
+	ErrConditionalExpired = errors.New("conditional expired")
+
+	// ErrConditionalLimit is returned if the pool already holds as many
+	// conditional transactions, or conditional storage slots, as allowed.
+	ErrConditionalLimit = errors.New("conditional transaction limit reached")
 )
 
 var (
@@ -131,6 +143,7 @@ var (
 	queuedGauge  = metrics.NewRegisteredGauge("txpool/queued", nil)
 	localGauge   = metrics.NewRegisteredGauge("txpool/local", nil)
 	slotsGauge   = metrics.NewRegisteredGauge("txpool/slots", nil)
+	bannedGauge  = metrics.NewRegisteredGauge("txpool/banned", nil)
 
 	reheapTimer = metrics.NewRegisteredTimer("txpool/reheap", nil)
 )
@@ -185,7 +198,11 @@ type TxPoolConfig struct {
 	Locals    []common.Address // Addresses that should be treated by default as local
 	NoLocals  bool             // Whether local transaction handling should be disabled
 	Journal   string           // Journal of local transactions to survive node restarts
-	Rejournal time.Duration    // Time interval to regenerate the local transaction journal
+	Rejournal time.Duration    // Time interval to regenerate the local and remote transaction journals
+
+	RemoteJournal         string        // Journal of remote transactions to survive node restarts, written every Rejournal interval (disabled if empty)
+	RemoteJournalSize     uint64        // Maximum number of remote transactions to journal
+	RemoteJournalLifetime time.Duration // Maximum age of journaled remote transactions
 
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
@@ -196,6 +213,9 @@ type TxPoolConfig struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	ConditionalTxs   uint64 // Maximum number of conditional transactions for all accounts
+	ConditionalSlots uint64 // Maximum number of storage slots checked by all conditional transactions
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -204,6 +224,9 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	Journal:   "transactions.rlp",
 	Rejournal: time.Hour,
 
+	RemoteJournalSize:     4096,
+	RemoteJournalLifetime: 3 * time.Hour,
+
 	PriceLimit: 0,
 	PriceBump:  10,
 
@@ -213,6 +236,9 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	GlobalQueue:  1024,
 
 	Lifetime: 3 * time.Hour,
+
+	ConditionalTxs:   1024,
+	ConditionalSlots: 16384,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -223,6 +249,14 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool journal time", "provided", conf.Rejournal, "updated", time.Second)
 		conf.Rejournal = time.Second
 	}
+	if conf.RemoteJournalSize < 1 {
+		log.Warn("Sanitizing invalid txpool remote journal size", "provided", conf.RemoteJournalSize, "updated", DefaultTxPoolConfig.RemoteJournalSize)
+		conf.RemoteJournalSize = DefaultTxPoolConfig.RemoteJournalSize
+	}
+	if conf.RemoteJournalLifetime < 1 {
+		log.Warn("Sanitizing invalid txpool remote journal lifetime", "provided", conf.RemoteJournalLifetime, "updated", DefaultTxPoolConfig.RemoteJournalLifetime)
+		conf.RemoteJournalLifetime = DefaultTxPoolConfig.RemoteJournalLifetime
+	}
 	if conf.PriceBump < 1 {
 		log.Warn("Sanitizing invalid txpool price bump", "provided", conf.PriceBump, "updated", DefaultTxPoolConfig.PriceBump)
 		conf.PriceBump = DefaultTxPoolConfig.PriceBump
@@ -247,6 +281,14 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultTxPoolConfig.Lifetime)
 		conf.Lifetime = DefaultTxPoolConfig.Lifetime
 	}
+	if conf.ConditionalTxs < 1 {
+		log.Warn("Sanitizing invalid txpool conditional transactions", "provided", conf.ConditionalTxs, "updated", DefaultTxPoolConfig.ConditionalTxs)
+		conf.ConditionalTxs = DefaultTxPoolConfig.ConditionalTxs
+	}
+	if conf.ConditionalSlots < 1 {
+		log.Warn("Sanitizing invalid txpool conditional slots", "provided", conf.ConditionalSlots, "updated", DefaultTxPoolConfig.ConditionalSlots)
+		conf.ConditionalSlots = DefaultTxPoolConfig.ConditionalSlots
+	}
 	return conf
 }
 
@@ -281,6 +323,7 @@ type TxPool struct {
 	gingerbreadP2 bool // Fork indicator for the Gingerbread P2 fork.
 	hfork         bool // Fork indicator for the HFork.
 
+	currentHead     *types.Header  // Current head of the blockchain
 	currentState    *state.StateDB // Current state in the blockchain head
 	currentVMRunner vm.EVMRunner   // Current EVMRunner
 	pendingNonces   *txNoncer      // Pending state tracking virtual nonces
@@ -290,6 +333,9 @@ type TxPool struct {
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
 
+	remoteJournal *remoteTxJournal             // Journal of remote transactions to back up to disk
+	banned        map[common.Address]time.Time // Accounts banned from the pool until the given time
+
 	pending map[common.Address]*txList   // All currently processable transactions
 	queue   map[common.Address]*txList   // Queued but non-processable transactions
 	beats   map[common.Address]time.Time // Last heartbeat from each known account
@@ -328,6 +374,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		pending:         make(map[common.Address]*txList),
 		queue:           make(map[common.Address]*txList),
 		beats:           make(map[common.Address]time.Time),
+		banned:          make(map[common.Address]time.Time),
 		all:             newTxLookup(),
 		chainHeadCh:     make(chan ChainHeadEvent, chainHeadChanSize),
 		reqResetCh:      make(chan *txpoolResetRequest),
@@ -365,6 +412,15 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 			log.Warn("Failed to rotate transaction journal", "err", err)
 		}
 	}
+	// If remote journaling is enabled, reload the remote transactions still
+	// valid against the current state
+	if config.RemoteJournal != "" {
+		pool.remoteJournal = newRemoteTxJournal(config.RemoteJournal, config.RemoteJournalSize, config.RemoteJournalLifetime)
+
+		if err := pool.remoteJournal.load(pool.addJournaledRemotes); err != nil {
+			log.Warn("Failed to load remote transaction journal", "err", err)
+		}
+	}
 
 	// Subscribe events from blockchain and start the main event loop.
 	pool.chainHeadSub = pool.chain.SubscribeChainHeadEvent(pool.chainHeadCh)
@@ -438,9 +494,16 @@ func (pool *TxPool) loop() {
 					queuedEvictionMeter.Mark(int64(len(list)))
 				}
 			}
+			// Lift any expired bans
+			for addr, until := range pool.banned {
+				if time.Now().After(until) {
+					delete(pool.banned, addr)
+				}
+			}
+			bannedGauge.Update(int64(len(pool.banned)))
 			pool.mu.Unlock()
 
-		// Handle local transaction journal rotation
+		// Handle local and remote transaction journal rotation
 		case <-journal.C:
 			if pool.journal != nil {
 				pool.mu.Lock()
@@ -449,6 +512,14 @@ func (pool *TxPool) loop() {
 				}
 				pool.mu.Unlock()
 			}
+			if pool.remoteJournal != nil {
+				pool.mu.RLock()
+				remotes := pool.remote()
+				pool.mu.RUnlock()
+				if err := pool.remoteJournal.rotate(remotes); err != nil {
+					log.Warn("Failed to rotate remote tx journal", "err", err)
+				}
+			}
 		}
 	}
 }
@@ -465,6 +536,14 @@ func (pool *TxPool) Stop() {
 	if pool.journal != nil {
 		pool.journal.close()
 	}
+	if pool.remoteJournal != nil {
+		pool.mu.RLock()
+		remotes := pool.remote()
+		pool.mu.RUnlock()
+		if err := pool.remoteJournal.rotate(remotes); err != nil {
+			log.Warn("Failed to rotate remote tx journal", "err", err)
+		}
+	}
 	log.Info("Transaction pool stopped")
 }
 
@@ -571,6 +650,82 @@ func (pool *TxPool) Content() (map[common.Address]types.Transactions, map[common
 	return pending, queued
 }
 
+// FeeCurrencyStats summarizes the transactions of the pool paying fees in a
+// single currency. The tips are the effective tips of the pending transactions
+// at the current gas price minimums, converted to CELO, and are nil when there
+// are no pending transactions.
+type FeeCurrencyStats struct {
+	Pending         int
+	Queued          int
+	CheapestTip     *big.Int
+	MedianTip       *big.Int
+	GasPriceMinimum *big.Int
+}
+
+// FeeCurrencyStats retrieves the stats of the pool content per fee currency,
+// keyed by the currency address with CELO under the zero address. Currencies
+// with a gas price minimum at the current head are reported even if they have
+// no transactions.
+func (pool *TxPool) FeeCurrencyStats() map[common.Address]*FeeCurrencyStats {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	ctx := pool.currentCtx.Load().(txPoolContext)
+	return feeCurrencyStats(pool.pending, pool.queue, ctx.GetCurrentGasPriceMinimumMap(), ctx.CurrencyManager)
+}
+
+// feeCurrencyStats groups the given pending and queued transactions by fee
+// currency, see FeeCurrencyStats.
+func feeCurrencyStats(pending, queue map[common.Address]*txList, gasPriceMinimums GasPriceMinimums, currencies currency.Provider) map[common.Address]*FeeCurrencyStats {
+	stats := make(map[common.Address]*FeeCurrencyStats)
+	statsOf := func(feeCurrency *common.Address) *FeeCurrencyStats {
+		key := common.ZeroAddress
+		if feeCurrency != nil {
+			key = *feeCurrency
+		}
+		if stats[key] == nil {
+			stats[key] = &FeeCurrencyStats{GasPriceMinimum: gasPriceMinimums.GetGasPriceMinimum(feeCurrency)}
+		}
+		return stats[key]
+	}
+	for address := range gasPriceMinimums {
+		address := address
+		if address == common.ZeroAddress {
+			statsOf(nil)
+		} else {
+			statsOf(&address)
+		}
+	}
+
+	tips := make(map[*FeeCurrencyStats][]*big.Int)
+	for _, list := range pending {
+		for _, tx := range list.txs.items {
+			currencyStats := statsOf(tx.FeeCurrency())
+			currencyStats.Pending++
+
+			// Celo denominated transactions pay CELO prices in another currency
+			denominated := tx.DenominatedFeeCurrency()
+			curr, err := currencies.GetCurrency(denominated)
+			if err != nil {
+				continue
+			}
+			tip := curr.ToCELO(tx.EffectiveGasTipValue(gasPriceMinimums.GetGasPriceMinimum(denominated)))
+			tips[currencyStats] = append(tips[currencyStats], tip)
+		}
+	}
+	for _, list := range queue {
+		for _, tx := range list.txs.items {
+			statsOf(tx.FeeCurrency()).Queued++
+		}
+	}
+	for currencyStats, values := range tips {
+		sort.Slice(values, func(i, j int) bool { return values[i].Cmp(values[j]) < 0 })
+		currencyStats.CheapestTip = values[0]
+		currencyStats.MedianTip = values[len(values)/2]
+	}
+	return stats
+}
+
 // ContentFrom retrieves the data content of the transaction pool, returning the
 // pending as well as queued transactions of this address, grouped by nonce.
 func (pool *TxPool) ContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
@@ -645,6 +800,49 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 	return txs
 }
 
+// remote retrieves all currently known remote transactions, the executable ones
+// first, each account's transactions sorted by nonce. The returned transaction
+// set is a copy and can be freely modified by calling code.
+func (pool *TxPool) remote() types.Transactions {
+	var pending, queued types.Transactions
+	for addr, list := range pool.pending {
+		if !pool.locals.contains(addr) {
+			pending = append(pending, list.Flatten()...)
+		}
+	}
+	for addr, list := range pool.queue {
+		if !pool.locals.contains(addr) {
+			queued = append(queued, list.Flatten()...)
+		}
+	}
+	return append(pending, queued...)
+}
+
+// addJournaledRemotes adds remote transactions loaded from the journal, dropping
+// the ones whose fee cap no longer covers the gas price minimum of their currency.
+// The remaining validation against the current state and whitelist is done by
+// the regular remote insertion path.
+func (pool *TxPool) addJournaledRemotes(txs []*types.Transaction) []error {
+	var (
+		errs  = make([]error, len(txs))
+		valid = make([]*types.Transaction, 0, len(txs))
+		index = make([]int, 0, len(txs))
+		ctx   = pool.ctx()
+	)
+	for i, tx := range txs {
+		if tx.GasFeeCapIntCmp(ctx.GetGasPriceMinimum(tx.DenominatedFeeCurrency())) < 0 {
+			errs[i] = ErrUnderpriced
+			continue
+		}
+		valid = append(valid, tx)
+		index = append(index, i)
+	}
+	for i, err := range pool.addTxs(valid, false, true) {
+		errs[index[i]] = err
+	}
+	return errs
+}
+
 func (pool *TxPool) ctx() *txPoolContext {
 	ctx := pool.currentCtx.Load().(txPoolContext)
 	return &ctx
@@ -715,6 +913,25 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		return ErrInvalidSender
 	}
 
+	// Reject transactions from senders banned by the operator
+	if until, ok := pool.banned[from]; ok && time.Now().Before(until) {
+		return ErrSenderBanned
+	}
+
+	// Reject conditional transactions that can no longer be included
+	if cond := tx.Conditional(); cond != nil {
+		if cond.Expired(pool.currentHead) {
+			return ErrConditionalExpired
+		}
+		// Conditions are checked again on every reset, so bound their total
+		if count, slots := pool.all.ConditionalStats(); uint64(count) >= pool.config.ConditionalTxs || uint64(slots+cond.Slots()) > pool.config.ConditionalSlots {
+			return ErrConditionalLimit
+		}
+		if err := cond.CheckStorage(pool.currentState); err != nil {
+			return err
+		}
+	}
+
 	isWhitelisted := pool.ctx().IsWhitelisted(tx.FeeCurrency())
 	if !isWhitelisted {
 		return ErrNonWhitelistedFeeCurrency
@@ -923,8 +1140,9 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 // journalTx adds the specified transaction to the local disk journal if it is
 // deemed to have been sent from a local account.
 func (pool *TxPool) journalTx(from common.Address, tx *types.Transaction) {
-	// Only journal if it's enabled and the transaction is local
-	if pool.journal == nil || !pool.locals.contains(from) {
+	// Only journal if it's enabled and the transaction is local. Conditional
+	// transactions are left out, as their conditions would be lost on reload.
+	if pool.journal == nil || !pool.locals.contains(from) || tx.Conditional() != nil {
 		return
 	}
 	if err := pool.journal.insert(tx); err != nil {
@@ -1108,6 +1326,45 @@ func (pool *TxPool) Get(hash common.Hash) *types.Transaction {
 	return pool.all.Get(hash)
 }
 
+// RemoveTransaction drops a single transaction from the pool, demoting any
+// pending transactions of the same sender it invalidates. It returns whether
+// the transaction was found.
+func (pool *TxPool) RemoveTransaction(hash common.Hash) bool {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if pool.all.Get(hash) == nil {
+		return false
+	}
+	pool.removeTx(hash, true)
+	return true
+}
+
+// EvictAccount drops all pending and queued transactions of an account and, if
+// ban is positive, rejects any new transaction from it for that long. It returns
+// the number of transactions dropped.
+func (pool *TxPool) EvictAccount(addr common.Address, ban time.Duration) int {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	var txs types.Transactions
+	if list := pool.pending[addr]; list != nil {
+		txs = append(txs, list.Flatten()...)
+	}
+	if list := pool.queue[addr]; list != nil {
+		txs = append(txs, list.Flatten()...)
+	}
+	// Drop the highest nonces first to avoid demoting the rest along the way
+	for i := len(txs) - 1; i >= 0; i-- {
+		pool.removeTx(txs[i].Hash(), true)
+	}
+	if ban > 0 {
+		pool.banned[addr] = time.Now().Add(ban)
+		bannedGauge.Update(int64(len(pool.banned)))
+	}
+	return len(txs)
+}
+
 // Has returns an indicator whether txpool has a transaction cached with the
 // given hash.
 func (pool *TxPool) Has(hash common.Hash) bool {
@@ -1306,6 +1563,7 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 	// because of another transaction (e.g. higher gas price).
 	if reset != nil {
 		pool.demoteUnexecutables()
+		pool.dropUnmetConditionals()
 		if reset.newHead != nil && pool.chainconfig.IsLondon(new(big.Int).Add(reset.newHead.Number, big.NewInt(1))) {
 			pool.priced.SetBaseFee(pool.ctx())
 		} else {
@@ -1413,6 +1671,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	if newHead == nil {
 		newHead = pool.chain.CurrentBlock().Header() // Special case during testing
 	}
+	pool.currentHead = newHead
 	statedb, err := pool.chain.StateAt(newHead.Root)
 	if err != nil {
 		log.Error("Failed to reset txpool state", "err", err)
@@ -1735,6 +1994,17 @@ func (pool *TxPool) demoteUnexecutables() {
 	}
 }
 
+// dropUnmetConditionals removes all conditional transactions whose conditions
+// can no longer be met on top of the current head.
+func (pool *TxPool) dropUnmetConditionals() {
+	for _, tx := range pool.all.Conditionals() {
+		if cond := tx.Conditional(); cond.Expired(pool.currentHead) || cond.CheckStorage(pool.currentState) != nil {
+			log.Trace("Removed conditional transaction with unmet conditions", "hash", tx.Hash())
+			pool.removeTx(tx.Hash(), true)
+		}
+	}
+}
+
 // ValidateTransactorBalanceCoversTx validates transactor has enough funds to cover transaction cost, the rules are consistent with state_transition.
 //
 // For native token(CELO) as feeCurrency:
@@ -1865,13 +2135,17 @@ type txLookup struct {
 	lock    sync.RWMutex
 	locals  map[common.Hash]*types.Transaction
 	remotes map[common.Hash]*types.Transaction
+
+	conditionals     map[common.Hash]*types.Transaction // Subset of the transactions submitted with inclusion conditions
+	conditionalSlots int                                // Number of storage slots checked by the conditional transactions
 }
 
 // newTxLookup returns a new txLookup structure.
 func newTxLookup() *txLookup {
 	return &txLookup{
-		locals:  make(map[common.Hash]*types.Transaction),
-		remotes: make(map[common.Hash]*types.Transaction),
+		locals:       make(map[common.Hash]*types.Transaction),
+		remotes:      make(map[common.Hash]*types.Transaction),
+		conditionals: make(map[common.Hash]*types.Transaction),
 	}
 }
 
@@ -1957,6 +2231,28 @@ func (t *txLookup) Slots() int {
 	return t.slots
 }
 
+// Conditionals returns the transactions of the lookup submitted with inclusion
+// conditions.
+func (t *txLookup) Conditionals() types.Transactions {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	txs := make(types.Transactions, 0, len(t.conditionals))
+	for _, tx := range t.conditionals {
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+// ConditionalStats returns the current number of conditional transactions in
+// the lookup and the number of storage slots they check.
+func (t *txLookup) ConditionalStats() (int, int) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return len(t.conditionals), t.conditionalSlots
+}
+
 // Add adds a transaction to the lookup.
 func (t *txLookup) Add(tx *types.Transaction, local bool) {
 	t.lock.Lock()
@@ -1970,6 +2266,10 @@ func (t *txLookup) Add(tx *types.Transaction, local bool) {
 	} else {
 		t.remotes[tx.Hash()] = tx
 	}
+	if cond := tx.Conditional(); cond != nil {
+		t.conditionals[tx.Hash()] = tx
+		t.conditionalSlots += cond.Slots()
+	}
 }
 
 // Remove removes a transaction from the lookup.
@@ -1991,6 +2291,11 @@ func (t *txLookup) Remove(hash common.Hash) {
 
 	delete(t.locals, hash)
 	delete(t.remotes, hash)
+
+	if cond := tx.Conditional(); cond != nil {
+		delete(t.conditionals, hash)
+		t.conditionalSlots -= cond.Slots()
+	}
 }
 
 // RemoteToLocals migrates the transactions belongs to the given locals to locals
@@ -30,6 +30,7 @@ import (
 	"github.com/celo-org/celo-blockchain/common"
 	"github.com/celo-org/celo-blockchain/consensus"
 	mockEngine "github.com/celo-org/celo-blockchain/consensus/consensustest"
+	"github.com/celo-org/celo-blockchain/contracts/config"
 	"github.com/celo-org/celo-blockchain/contracts/currency"
 	"github.com/celo-org/celo-blockchain/contracts/testutil"
 	"github.com/celo-org/celo-blockchain/core/rawdb"
@@ -201,6 +202,18 @@ func validateTxPoolInternals(pool *TxPool) error {
 	if priced != remote {
 		return fmt.Errorf("total priced transaction count %d != %d", priced, remote)
 	}
+	// Ensure the conditional transactions are tracked consistently
+	var conditionals, conditionalSlots int
+	pool.all.Range(func(hash common.Hash, tx *types.Transaction, local bool) bool {
+		if cond := tx.Conditional(); cond != nil {
+			conditionals++
+			conditionalSlots += cond.Slots()
+		}
+		return true
+	}, true, true)
+	if count, slots := pool.all.ConditionalStats(); count != conditionals || slots != conditionalSlots {
+		return fmt.Errorf("conditional stats mismatch: have %d/%d, want %d/%d", count, slots, conditionals, conditionalSlots)
+	}
 	// Ensure the next nonce to assign is the correct one
 	for addr, txs := range pool.pending {
 		// Find the last transaction
@@ -2621,6 +2634,74 @@ func TestTransactionReplacementCeloDynamicFeeDifferentCurrencies(t *testing.T) {
 	}
 }
 
+// Tests that a transaction stuck in a depegged currency can be replaced by one
+// in another currency, based on the effective tips converted to a common unit.
+func TestTransactionReplacementCeloDynamicFeeDepeggedCurrency(t *testing.T) {
+	t.Parallel()
+
+	secondFeeCurrency := common.HexToAddress("05")
+
+	// defaultFeeCurrency is worth a tenth of secondFeeCurrency and has a
+	// higher gas price minimum
+	blockchain := newTestBlockchain()
+	gpmAddress := common.HexToAddress("0x090")
+	blockchain.celoMock.Runner.RegisterContract(gpmAddress, testutil.NewSingleMethodContract(config.GasPriceMinimumRegistryId, "getGasPriceMinimum",
+		func(currency common.Address) *big.Int {
+			if currency == defaultFeeCurrency {
+				return big.NewInt(150)
+			}
+			return big.NewInt(10)
+		},
+	))
+	blockchain.celoMock.Registry.AddContract(config.GasPriceMinimumRegistryId, gpmAddress)
+	sortedOraclesAddress := common.HexToAddress("0x091")
+	blockchain.celoMock.Runner.RegisterContract(sortedOraclesAddress, testutil.NewSingleMethodContract(config.SortedOraclesRegistryId, "medianRate",
+		func(currency common.Address) (*big.Int, *big.Int) {
+			if currency == defaultFeeCurrency {
+				return big.NewInt(10), common.Big1
+			}
+			return common.Big1, common.Big1
+		},
+	))
+	blockchain.celoMock.Registry.AddContract(config.SortedOraclesRegistryId, sortedOraclesAddress)
+
+	pool := NewTxPool(testTxPoolConfig, eip1559Config, blockchain)
+	defer pool.Stop()
+	<-pool.initDoneCh
+
+	key, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	// The original transaction pays an effective tip of 200 - 150 = 50 units,
+	// which is worth 5 units of secondFeeCurrency
+	tx := celoDynamicFeeTxV2(0, 100000, big.NewInt(200), big.NewInt(100), key, defaultFeeCurrency)
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add original transaction: %v", err)
+	}
+	// A fee cap below the converted threshold of 22 is underpriced
+	tx = celoDynamicFeeTxV2(0, 100000, big.NewInt(21), big.NewInt(6), key, secondFeeCurrency)
+	if err := pool.AddRemote(tx); err != ErrReplaceUnderpriced {
+		t.Fatalf("replacement error mismatch: have %v, want %v", err, ErrReplaceUnderpriced)
+	}
+	// An effective tip below the converted threshold of 5.5 is underpriced
+	tx = celoDynamicFeeTxV2(0, 100000, big.NewInt(22), big.NewInt(5), key, secondFeeCurrency)
+	if err := pool.AddRemote(tx); err != ErrReplaceUnderpriced {
+		t.Fatalf("replacement error mismatch: have %v, want %v", err, ErrReplaceUnderpriced)
+	}
+	// Tip caps are far apart, but the effective tips clear the price bump
+	tx = celoDynamicFeeTxV2(0, 100000, big.NewInt(22), big.NewInt(6), key, secondFeeCurrency)
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to replace depegged transaction: %v", err)
+	}
+	if pending, _ := pool.Content(); len(pending[crypto.PubkeyToAddress(key.PublicKey)]) != 1 ||
+		pending[crypto.PubkeyToAddress(key.PublicKey)][0].Hash() != tx.Hash() {
+		t.Fatalf("replacement transaction not pending")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Tests that local transactions are journaled to disk, but remote transactions
 // get discarded between restarts.
 func TestTransactionJournaling(t *testing.T)         { testTransactionJournaling(t, false) }
@@ -2737,6 +2818,309 @@ func testTransactionJournaling(t *testing.T, nolocals bool) {
 	pool.Stop()
 }
 
+// Tests that operators can remove single transactions and evict and ban whole
+// accounts from the pool.
+func TestTransactionRemoveAndEvict(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	other, _ := crypto.GenerateKey()
+	addr, otherAddr := crypto.PubkeyToAddress(key.PublicKey), crypto.PubkeyToAddress(other.PublicKey)
+	testAddBalance(pool, addr, big.NewInt(1000000000))
+	testAddBalance(pool, otherAddr, big.NewInt(1000000000))
+
+	txs := []*types.Transaction{
+		transaction(0, 100000, key),
+		transaction(1, 100000, key),
+		transaction(2, 100000, key),
+		transaction(5, 100000, key),
+		transaction(0, 100000, other),
+	}
+	pool.AddRemotesSync(txs)
+	if pending, queued := pool.Stats(); pending != 4 || queued != 1 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want %d/%d", pending, queued, 4, 1)
+	}
+	// Removing a pending transaction demotes the ones following it
+	if !pool.RemoveTransaction(txs[1].Hash()) {
+		t.Fatalf("pending transaction not found")
+	}
+	if pool.RemoveTransaction(txs[1].Hash()) {
+		t.Fatalf("removed transaction still found")
+	}
+	if pending, queued := pool.Stats(); pending != 2 || queued != 2 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want %d/%d", pending, queued, 2, 2)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	// Evicting the account drops all its transactions and bans it
+	if evicted := pool.EvictAccount(addr, time.Hour); evicted != 3 {
+		t.Fatalf("evicted transactions mismatch: have %d, want %d", evicted, 3)
+	}
+	if pending, queued := pool.Stats(); pending != 1 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want %d/%d", pending, queued, 1, 0)
+	}
+	if err := pool.AddRemote(transaction(0, 100000, key)); err != ErrSenderBanned {
+		t.Fatalf("banned sender error mismatch: have %v, want %v", err, ErrSenderBanned)
+	}
+	if err := pool.AddLocal(transaction(0, 100000, key)); err != ErrSenderBanned {
+		t.Fatalf("banned local sender error mismatch: have %v, want %v", err, ErrSenderBanned)
+	}
+	// Evicting without a ban lets the account back in
+	if evicted := pool.EvictAccount(otherAddr, 0); evicted != 1 {
+		t.Fatalf("evicted transactions mismatch: have %d, want %d", evicted, 1)
+	}
+	if err := pool.addRemoteSync(transaction(0, 100000, other)); err != nil {
+		t.Fatalf("failed to add transaction of evicted account: %v", err)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that conditional transactions are validated on admission and dropped
+// once their conditions no longer hold.
+func TestTransactionConditional(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	contract, slot := common.HexToAddress("0xc0ffee"), common.HexToHash("0x01")
+	pool.currentState.SetState(contract, slot, common.HexToHash("0x02"))
+
+	conditional := func(nonce uint64, cond *types.TransactionConditional) *types.Transaction {
+		tx := transaction(nonce, 100000, key)
+		tx.SetConditional(cond)
+		return tx
+	}
+	// Conditions already past or not matching the state are rejected
+	tx := conditional(0, &types.TransactionConditional{BlockNumberMax: big.NewInt(0)})
+	if err := pool.AddLocal(tx); err != ErrConditionalExpired {
+		t.Fatalf("expired conditional error mismatch: have %v, want %v", err, ErrConditionalExpired)
+	}
+	tx = conditional(0, &types.TransactionConditional{
+		KnownAccounts: map[common.Address]map[common.Hash]common.Hash{contract: {slot: common.HexToHash("0x03")}},
+	})
+	if err := pool.AddLocal(tx); !errors.Is(err, types.ErrConditionalStorage) {
+		t.Fatalf("storage conditional error mismatch: have %v, want %v", err, types.ErrConditionalStorage)
+	}
+	// Matching conditions are accepted
+	tx = conditional(0, &types.TransactionConditional{
+		KnownAccounts:  map[common.Address]map[common.Hash]common.Hash{contract: {slot: common.HexToHash("0x02")}},
+		BlockNumberMax: big.NewInt(10),
+	})
+	if err := pool.AddLocal(tx); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+	}
+	// Once the slot changes, the transaction is dropped on the next reset
+	pool.mu.Lock()
+	pool.currentState.SetState(contract, slot, common.HexToHash("0x03"))
+	pool.dropUnmetConditionals()
+	pool.mu.Unlock()
+
+	if pending, queued := pool.Stats(); pending != 0 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want %d/%d", pending, queued, 0, 0)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the pool bounds the number of conditional transactions and of the
+// storage slots they check, releasing them as the transactions leave the pool.
+func TestTransactionConditionalLimits(t *testing.T) {
+	t.Parallel()
+
+	config := testTxPoolConfig
+	config.ConditionalTxs = 2
+	config.ConditionalSlots = 3
+
+	pool := NewTxPool(config, params.TestChainConfig, newTestBlockchain())
+	<-pool.initDoneCh
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	contract := common.HexToAddress("0xc0ffee")
+	conditional := func(nonce uint64, slots int) *types.Transaction {
+		storage := make(map[common.Hash]common.Hash)
+		for i := 0; i < slots; i++ {
+			storage[common.BigToHash(big.NewInt(int64(i)))] = common.Hash{}
+		}
+		tx := transaction(nonce, 100000, key)
+		tx.SetConditional(&types.TransactionConditional{
+			KnownAccounts: map[common.Address]map[common.Hash]common.Hash{contract: storage},
+		})
+		return tx
+	}
+	first := conditional(0, 2)
+	if err := pool.AddLocal(first); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+	// Exceeding the slot limit is rejected
+	if err := pool.AddLocal(conditional(1, 2)); err != ErrConditionalLimit {
+		t.Fatalf("slot limit error mismatch: have %v, want %v", err, ErrConditionalLimit)
+	}
+	if err := pool.AddLocal(conditional(1, 1)); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+	// Exceeding the transaction limit is rejected, unconditional ones are not
+	if err := pool.AddLocal(conditional(2, 0)); err != ErrConditionalLimit {
+		t.Fatalf("transaction limit error mismatch: have %v, want %v", err, ErrConditionalLimit)
+	}
+	if err := pool.AddLocal(transaction(2, 100000, key)); err != nil {
+		t.Fatalf("failed to add unconditional transaction: %v", err)
+	}
+	// Dropping a conditional transaction releases its share of the limits
+	if !pool.RemoveTransaction(first.Hash()) {
+		t.Fatalf("conditional transaction not removed")
+	}
+	if count, slots := pool.all.ConditionalStats(); count != 1 || slots != 1 {
+		t.Fatalf("conditional stats mismatch: have %d/%d, want %d/%d", count, slots, 1, 1)
+	}
+	if err := pool.AddLocal(conditional(3, 2)); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that remote transactions are journaled to disk if enabled, and that on
+// restart only the ones still valid against the new state and gas price
+// minimums are reloaded.
+func TestTransactionRemoteJournaling(t *testing.T) {
+	t.Parallel()
+
+	// Create a temporary file for the journal
+	file, err := ioutil.TempFile("", "")
+	if err != nil {
+		t.Fatalf("failed to create temporary journal: %v", err)
+	}
+	journal := file.Name()
+	defer os.Remove(journal)
+
+	// Clean up the temporary file, we only need the path for now
+	file.Close()
+	os.Remove(journal)
+
+	// Mock a gas price minimum that can be raised between restarts
+	gasPriceMinimum := big.NewInt(10)
+	newBlockchain := func(statedb *state.StateDB) *testBlockChain {
+		blockchain := newTestBlockchain()
+		if statedb != nil {
+			blockchain.statedb = statedb
+		}
+		gpmAddress := common.HexToAddress("0x090")
+		blockchain.celoMock.Runner.RegisterContract(gpmAddress, testutil.NewSingleMethodContract(config.GasPriceMinimumRegistryId, "getGasPriceMinimum",
+			func(currency common.Address) *big.Int { return gasPriceMinimum },
+		))
+		blockchain.celoMock.Registry.AddContract(config.GasPriceMinimumRegistryId, gpmAddress)
+		return blockchain
+	}
+	config := testTxPoolConfig
+	config.NoLocals = true
+	config.RemoteJournal = journal
+
+	blockchain := newBlockchain(nil)
+	pool := NewTxPool(config, eip1559Config, blockchain)
+	<-pool.initDoneCh
+
+	cheap, _ := crypto.GenerateKey()
+	stale, _ := crypto.GenerateKey()
+	valid, _ := crypto.GenerateKey()
+	for _, key := range []*ecdsa.PrivateKey{cheap, stale, valid} {
+		testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+	}
+	txs := []*types.Transaction{
+		celoDynamicFeeTxV2(0, 100000, big.NewInt(20), big.NewInt(1), cheap, defaultFeeCurrency),
+		celoDynamicFeeTxV2(0, 100000, big.NewInt(100), big.NewInt(1), stale, defaultFeeCurrency),
+		celoDynamicFeeTxV2(0, 100000, big.NewInt(100), big.NewInt(1), valid, defaultFeeCurrency),
+		celoDynamicFeeTxV2(1, 100000, big.NewInt(100), big.NewInt(1), valid, defaultFeeCurrency),
+		celoDynamicFeeTxV2(3, 100000, big.NewInt(100), big.NewInt(1), valid, defaultFeeCurrency),
+	}
+	for i, err := range pool.AddRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("failed to add remote transaction %d: %v", i, err)
+		}
+	}
+	if pending, queued := pool.Stats(); pending != 4 || queued != 1 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want %d/%d", pending, queued, 4, 1)
+	}
+	// Terminate the old pool, invalidate a transaction through the state and
+	// another one through the gas price minimum, and restart
+	pool.Stop()
+	blockchain.statedb.SetNonce(crypto.PubkeyToAddress(stale.PublicKey), 1)
+	gasPriceMinimum = big.NewInt(50)
+
+	pool = NewTxPool(config, eip1559Config, newBlockchain(blockchain.statedb))
+	defer pool.Stop()
+
+	if pending, queued := pool.Stats(); pending != 2 || queued != 1 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want %d/%d", pending, queued, 2, 1)
+	}
+	for _, tx := range txs[2:] {
+		if pool.Get(tx.Hash()) == nil {
+			t.Fatalf("journaled transaction %x not reloaded", tx.Hash())
+		}
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the remote journal drops expired transactions, keeps the time
+// they were first seen and respects its size limit.
+func TestRemoteTxJournalLimits(t *testing.T) {
+	t.Parallel()
+
+	file, err := ioutil.TempFile("", "")
+	if err != nil {
+		t.Fatalf("failed to create temporary journal: %v", err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	key, _ := crypto.GenerateKey()
+	var txs types.Transactions
+	for i := uint64(0); i < 4; i++ {
+		txs = append(txs, transaction(i, 100000, key))
+	}
+	seen := time.Now().Add(-time.Minute).Truncate(time.Second)
+	journal := newRemoteTxJournal(file.Name(), 2, time.Hour)
+	journal.seen[txs[0].Hash()] = seen
+	journal.seen[txs[1].Hash()] = time.Now().Add(-2 * time.Hour)
+
+	if err := journal.rotate(txs); err != nil {
+		t.Fatalf("failed to rotate journal: %v", err)
+	}
+	// Reload the journal as a restarted node would
+	journal = newRemoteTxJournal(file.Name(), 2, time.Hour)
+	var loaded []*types.Transaction
+	err = journal.load(func(txs []*types.Transaction) []error {
+		loaded = append(loaded, txs...)
+		return make([]error, len(txs))
+	})
+	if err != nil {
+		t.Fatalf("failed to load journal: %v", err)
+	}
+	// The expired transaction is skipped and the last one exceeds the size
+	if len(loaded) != 2 || loaded[0].Hash() != txs[0].Hash() || loaded[1].Hash() != txs[2].Hash() {
+		t.Fatalf("loaded transactions mismatch: have %d, want %d", len(loaded), 2)
+	}
+	if first := journal.firstSeen(loaded[0]); !first.Equal(seen) {
+		t.Fatalf("first seen time mismatch: have %v, want %v", first, seen)
+	}
+}
+
 // TestTransactionStatusCheck tests that the pool can correctly retrieve the
 // pending status of individual transactions.
 func TestTransactionStatusCheck(t *testing.T) {
@@ -2929,3 +3313,64 @@ func BenchmarkInsertRemoteWithAllLocals(b *testing.B) {
 		pool.Stop()
 	}
 }
+
+func TestFeeCurrencyStats(t *testing.T) {
+	cusd := common.HexToAddress("0xCAFE")
+	ceur := common.HexToAddress("0xBEEF")
+	exchangeRate, _ := currency.NewExchangeRate(big.NewInt(2), big.NewInt(1))
+	currencies := currency.NewCacheOnlyManager(map[common.Address]*currency.Currency{
+		cusd: currency.NewCurrency(cusd, *exchangeRate),
+	})
+	gasPriceMinimums := GasPriceMinimums{
+		common.ZeroAddress: big.NewInt(100),
+		cusd:               big.NewInt(200),
+		ceur:               big.NewInt(300),
+	}
+	list := func(txs ...*types.Transaction) *txList {
+		list := newTxList(false, nil)
+		for _, tx := range txs {
+			list.txs.Put(tx)
+		}
+		return list
+	}
+	tx := func(nonce uint64, feeCap, tip int64, feeCurrency *common.Address) *types.Transaction {
+		return types.NewTx(&types.CeloDynamicFeeTxV2{
+			Nonce:       nonce,
+			GasFeeCap:   big.NewInt(feeCap),
+			GasTipCap:   big.NewInt(tip),
+			FeeCurrency: feeCurrency,
+		})
+	}
+	pending := map[common.Address]*txList{
+		common.HexToAddress("0x01"): list(tx(0, 1000, 10, nil), tx(1, 1000, 30, nil), tx(2, 120, 50, nil)),
+		common.HexToAddress("0x02"): list(tx(0, 1000, 40, &cusd), tx(1, 220, 60, &cusd)),
+	}
+	queue := map[common.Address]*txList{
+		common.HexToAddress("0x02"): list(tx(3, 1000, 10, &cusd)),
+	}
+	stats := feeCurrencyStats(pending, queue, gasPriceMinimums, currencies)
+
+	if len(stats) != 3 {
+		t.Fatalf("stats reported for %d currencies, want 3", len(stats))
+	}
+	check := func(name string, got *FeeCurrencyStats, pending, queued int, cheapest, median *big.Int, gasPriceMinimum int64) {
+		if got.Pending != pending || got.Queued != queued {
+			t.Errorf("%s: pending/queued mismatch: have %d/%d, want %d/%d", name, got.Pending, got.Queued, pending, queued)
+		}
+		if (got.CheapestTip == nil) != (cheapest == nil) || (cheapest != nil && got.CheapestTip.Cmp(cheapest) != 0) {
+			t.Errorf("%s: cheapest tip mismatch: have %v, want %v", name, got.CheapestTip, cheapest)
+		}
+		if (got.MedianTip == nil) != (median == nil) || (median != nil && got.MedianTip.Cmp(median) != 0) {
+			t.Errorf("%s: median tip mismatch: have %v, want %v", name, got.MedianTip, median)
+		}
+		if got.GasPriceMinimum.Int64() != gasPriceMinimum {
+			t.Errorf("%s: gas price minimum mismatch: have %v, want %d", name, got.GasPriceMinimum, gasPriceMinimum)
+		}
+	}
+	// The last CELO transaction only pays an effective tip of 20
+	check("celo", stats[common.ZeroAddress], 3, 0, big.NewInt(10), big.NewInt(20), 100)
+	// Effective tips of 40 and 20 cUSD are worth 20 and 10 CELO
+	check("cusd", stats[cusd], 2, 1, big.NewInt(10), big.NewInt(20), 200)
+	// Whitelisted currencies without transactions are still reported
+	check("ceur", stats[ceur], 0, 0, nil, nil, 300)
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/celo-org/celo-blockchain/common"
+)
+
+// ValidatorEpochReward is the epoch payment made to a single elected validator.
+// The reward is denominated in cUSD and is split on-chain between the validator
+// and its group.
+type ValidatorEpochReward struct {
+	Validator common.Address
+	Group     common.Address
+	Reward    *big.Int
+}
+
+// GroupEpochReward aggregates the rewards earned through a validator group during
+// an epoch: the payments made to its elected members and the rewards added to
+// the votes cast for it.
+type GroupEpochReward struct {
+	Group            common.Address
+	ValidatorRewards *big.Int
+	VoterRewards     *big.Int
+}
+
+// EpochRewards records what was paid out while finalizing the last block of an
+// epoch, so it can be queried later without replaying the block.
+type EpochRewards struct {
+	Epoch uint64
+
+	// Targets as computed by the EpochRewards contract.
+	TargetValidatorReward        *big.Int
+	TargetVoterRewards           *big.Int
+	TargetCommunityReward        *big.Int
+	TargetCarbonOffsettingReward *big.Int
+
+	Validators                  []ValidatorEpochReward
+	Groups                      []GroupEpochReward
+	TotalValidatorRewards       *big.Int // in cUSD
+	TotalValidatorRewardsInCelo *big.Int // minted to the reserve
+	TotalVoterRewards           *big.Int
+
+	CommunityRecipient      common.Address
+	CommunityReward         *big.Int
+	CarbonOffsettingPartner common.Address
+	CarbonOffsettingReward  *big.Int
+	// ReserveFallback is set when the community reward was sent to the reserve
+	// because it was below its critical threshold.
+	ReserveFallback bool
+}
@@ -63,6 +63,8 @@ type Transaction struct {
 	inner TxData    // Consensus contents of a transaction
 	time  time.Time // Time first seen locally (spam avoidance)
 
+	conditional *TransactionConditional // Inclusion conditions of a locally submitted transaction
+
 	// caches
 	hash atomic.Value
 	size atomic.Value
@@ -434,6 +436,23 @@ func (tx *Transaction) Hash() common.Hash {
 	return h
 }
 
+// Time returns the time when the transaction was first seen on the network.
+func (tx *Transaction) Time() time.Time {
+	return tx.time
+}
+
+// Conditional returns the conditions the transaction was submitted with, or nil
+// if it can be included unconditionally.
+func (tx *Transaction) Conditional() *TransactionConditional {
+	return tx.conditional
+}
+
+// SetConditional attaches inclusion conditions to the transaction. It must be
+// called before the transaction is handed to the transaction pool.
+func (tx *Transaction) SetConditional(conditional *TransactionConditional) {
+	tx.conditional = conditional
+}
+
 // Size returns the true RLP encoded storage size of the transaction, either by
 // encoding and returning it, or returning a previously cached value.
 func (tx *Transaction) Size() common.StorageSize {
@@ -0,0 +1,89 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/celo-org/celo-blockchain/common"
+)
+
+var (
+	// ErrConditionalBlockNumber is returned if a conditional transaction is
+	// included outside of its block number bounds.
+	ErrConditionalBlockNumber = errors.New("block number out of conditional bounds")
+
+	// ErrConditionalTimestamp is returned if a conditional transaction is
+	// included outside of its timestamp bounds.
+	ErrConditionalTimestamp = errors.New("timestamp out of conditional bounds")
+
+	// ErrConditionalStorage is returned if a storage slot of a conditional
+	// transaction does not hold the expected value.
+	ErrConditionalStorage = errors.New("storage slot does not match conditional value")
+)
+
+// StorageReader is the state access needed to check the known accounts of a
+// conditional transaction.
+type StorageReader interface {
+	GetState(addr common.Address, key common.Hash) common.Hash
+}
+
+// TransactionConditional is a set of conditions a transaction submitted to
+// this node must satisfy to be included in a block. It is not part of the
+// transaction itself and is never propagated to other nodes. Unset bounds are
+// not checked.
+type TransactionConditional struct {
+	KnownAccounts  map[common.Address]map[common.Hash]common.Hash // Expected storage slot values per account
+	BlockNumberMin *big.Int
+	BlockNumberMax *big.Int
+	TimestampMin   *uint64
+	TimestampMax   *uint64
+}
+
+// Slots returns the number of storage slots the conditional checks.
+func (c *TransactionConditional) Slots() int {
+	slots := 0
+	for _, storage := range c.KnownAccounts {
+		slots += len(storage)
+	}
+	return slots
+}
+
+// Expired returns whether the conditional can no longer hold for any block
+// after the given one.
+func (c *TransactionConditional) Expired(header *Header) bool {
+	if c.BlockNumberMax != nil && c.BlockNumberMax.Cmp(header.Number) <= 0 {
+		return true
+	}
+	return c.TimestampMax != nil && *c.TimestampMax <= header.Time
+}
+
+// CheckBlock verifies that a block with the given header satisfies the block
+// number and timestamp bounds.
+func (c *TransactionConditional) CheckBlock(header *Header) error {
+	if c.BlockNumberMin != nil && header.Number.Cmp(c.BlockNumberMin) < 0 {
+		return fmt.Errorf("%w: have %v, min %v", ErrConditionalBlockNumber, header.Number, c.BlockNumberMin)
+	}
+	if c.BlockNumberMax != nil && header.Number.Cmp(c.BlockNumberMax) > 0 {
+		return fmt.Errorf("%w: have %v, max %v", ErrConditionalBlockNumber, header.Number, c.BlockNumberMax)
+	}
+	if c.TimestampMin != nil && header.Time < *c.TimestampMin {
+		return fmt.Errorf("%w: have %d, min %d", ErrConditionalTimestamp, header.Time, *c.TimestampMin)
+	}
+	if c.TimestampMax != nil && header.Time > *c.TimestampMax {
+		return fmt.Errorf("%w: have %d, max %d", ErrConditionalTimestamp, header.Time, *c.TimestampMax)
+	}
+	return nil
+}
+
+// CheckStorage verifies that every known account slot holds its expected value.
+func (c *TransactionConditional) CheckStorage(state StorageReader) error {
+	for addr, storage := range c.KnownAccounts {
+		for key, want := range storage {
+			if have := state.GetState(addr, key); have != want {
+				return fmt.Errorf("%w: account %s slot %s has %s, want %s", ErrConditionalStorage, addr.Hex(), key.Hex(), have.Hex(), want.Hex())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/celo-org/celo-blockchain/common"
+)
+
+type testStorage map[common.Address]map[common.Hash]common.Hash
+
+func (s testStorage) GetState(addr common.Address, key common.Hash) common.Hash {
+	return s[addr][key]
+}
+
+func TestTransactionConditional(t *testing.T) {
+	min, max := uint64(100), uint64(200)
+	cond := &TransactionConditional{
+		BlockNumberMin: big.NewInt(10),
+		BlockNumberMax: big.NewInt(20),
+		TimestampMin:   &min,
+		TimestampMax:   &max,
+	}
+	tests := []struct {
+		number  int64
+		time    uint64
+		err     error
+		expired bool
+	}{
+		{number: 9, time: 150, err: ErrConditionalBlockNumber},
+		{number: 10, time: 99, err: ErrConditionalTimestamp},
+		{number: 10, time: 100},
+		{number: 19, time: 150},
+		{number: 20, time: 150, expired: true},
+		{number: 21, time: 150, err: ErrConditionalBlockNumber, expired: true},
+		{number: 15, time: 200, expired: true},
+		{number: 15, time: 201, err: ErrConditionalTimestamp, expired: true},
+	}
+	for i, test := range tests {
+		header := &Header{Number: big.NewInt(test.number), Time: test.time}
+		if err := cond.CheckBlock(header); !errors.Is(err, test.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+		}
+		if expired := cond.Expired(header); expired != test.expired {
+			t.Errorf("test %d: expiry mismatch: have %v, want %v", i, expired, test.expired)
+		}
+	}
+	// Unset bounds are never checked
+	empty := new(TransactionConditional)
+	if err := empty.CheckBlock(&Header{Number: big.NewInt(0)}); err != nil {
+		t.Errorf("empty conditional failed: %v", err)
+	}
+	if empty.Expired(&Header{Number: big.NewInt(1 << 40), Time: 1 << 40}) {
+		t.Errorf("empty conditional expired")
+	}
+}
+
+func TestTransactionConditionalStorage(t *testing.T) {
+	addr, slot := common.HexToAddress("0x01"), common.HexToHash("0x02")
+	state := testStorage{addr: {slot: common.HexToHash("0x03")}}
+
+	cond := &TransactionConditional{
+		KnownAccounts: map[common.Address]map[common.Hash]common.Hash{
+			addr: {slot: common.HexToHash("0x03"), common.HexToHash("0x04"): {}},
+		},
+	}
+	if slots := cond.Slots(); slots != 2 {
+		t.Fatalf("slot count mismatch: have %d, want %d", slots, 2)
+	}
+	if err := cond.CheckStorage(state); err != nil {
+		t.Fatalf("matching storage failed: %v", err)
+	}
+	state[addr][slot] = common.HexToHash("0x05")
+	if err := cond.CheckStorage(state); !errors.Is(err, ErrConditionalStorage) {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrConditionalStorage)
+	}
+}
@@ -171,6 +171,62 @@ func VerifyAggregatedSignature(publicKeys []SerializedPublicKey, message []byte,
 	return err
 }
 
+// AggregatedSignature is a signature aggregated from the signatures of a set of
+// signers over the same message.
+type AggregatedSignature struct {
+	PublicKeys []SerializedPublicKey
+	Message    []byte
+	ExtraData  []byte
+	Signature  []byte
+}
+
+// BatchVerifyAggregatedSignatures verifies many aggregated signatures at once
+// with a single multi-pairing. An error means at least one signature is
+// invalid, but not which one.
+//
+// Only the sum of the signatures is checked: invalid signatures whose errors
+// cancel each other out pass. They cannot make a message pass that its public
+// keys did not sign, as that requires the signatures of the other messages.
+// The BLS library has no scalar multiplication to randomize the signatures
+// with, and doing it by repeated aggregation is slower than verifying them one
+// by one.
+func BatchVerifyAggregatedSignatures(signatures []AggregatedSignature, shouldUseCompositeHasher, cip22 bool) error {
+	if len(signatures) == 0 {
+		return nil
+	}
+	signedMessages := make([]*bls.SignedBlockHeader, len(signatures))
+	for i, signature := range signatures {
+		publicKeyObjs := []*bls.PublicKey{}
+		for _, publicKey := range signature.PublicKeys {
+			publicKeyObj, err := bls.DeserializePublicKeyCached(publicKey[:])
+			if err != nil {
+				return err
+			}
+			defer publicKeyObj.Destroy()
+			publicKeyObjs = append(publicKeyObjs, publicKeyObj)
+		}
+		apk, err := bls.AggregatePublicKeys(publicKeyObjs)
+		if err != nil {
+			return err
+		}
+		defer apk.Destroy()
+
+		signatureObj, err := bls.DeserializeSignature(signature.Signature)
+		if err != nil {
+			return err
+		}
+		defer signatureObj.Destroy()
+
+		signedMessages[i] = &bls.SignedBlockHeader{
+			Data:   signature.Message,
+			Extra:  signature.ExtraData,
+			Pubkey: apk,
+			Sig:    signatureObj,
+		}
+	}
+	return bls.BatchVerifyEpochs(signedMessages, shouldUseCompositeHasher, cip22)
+}
+
 func AggregateSignatures(signatures [][]byte) ([]byte, error) {
 	signatureObjs := []*bls.Signature{}
 	for _, signature := range signatures {
@@ -56,3 +56,91 @@ func TestEncodeEpochSnarkData(t *testing.T) {
 	t.Logf("Encoded epoch block: %x", encodedEpochBlock)
 	t.Logf("Encoded epoch block extra data: %x", encodedEpochBlockExtraData)
 }
+
+// makeAggregatedSignatures signs n distinct messages with the same signers and
+// aggregates the signatures of each message.
+func makeAggregatedSignatures(t testing.TB, n, signers int) []AggregatedSignature {
+	keys := make([]*bls.PrivateKey, signers)
+	publicKeys := make([]SerializedPublicKey, signers)
+	for i := range keys {
+		key, err := bls.GeneratePrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKey, err := key.ToPublic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeyBytes, err := publicKey.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		copy(publicKeys[i][:], publicKeyBytes)
+		keys[i] = key
+	}
+	signatures := make([]AggregatedSignature, n)
+	for i := range signatures {
+		message := []byte{byte(i), byte(i >> 8), 0x42}
+		var sigs [][]byte
+		for _, key := range keys {
+			sig, err := key.SignMessage(message, []byte{}, false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sigBytes, err := sig.Serialize()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sigs = append(sigs, sigBytes)
+		}
+		asig, err := AggregateSignatures(sigs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signatures[i] = AggregatedSignature{PublicKeys: publicKeys, Message: message, ExtraData: []byte{}, Signature: asig}
+	}
+	return signatures
+}
+
+func TestBatchVerifyAggregatedSignatures(t *testing.T) {
+	signatures := makeAggregatedSignatures(t, 8, 4)
+	if err := BatchVerifyAggregatedSignatures(signatures, false, false); err != nil {
+		t.Fatalf("valid batch failed: %v", err)
+	}
+	// Replace a message with one its public keys did not sign.
+	signatures[2].Message = []byte("unsigned")
+	if err := BatchVerifyAggregatedSignatures(signatures, false, false); err == nil {
+		t.Fatal("batch with an unsigned message verified")
+	}
+}
+
+func benchmarkVerifyAggregatedSignatures(b *testing.B, n int, batch bool) {
+	signatures := makeAggregatedSignatures(b, n, 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if batch {
+			if err := BatchVerifyAggregatedSignatures(signatures, false, false); err != nil {
+				b.Fatal(err)
+			}
+			continue
+		}
+		for _, s := range signatures {
+			if err := VerifyAggregatedSignature(s.PublicKeys, s.Message, s.ExtraData, s.Signature, false, false); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkVerifyAggregatedSignatures_Single_128(b *testing.B) {
+	benchmarkVerifyAggregatedSignatures(b, 128, false)
+}
+func BenchmarkVerifyAggregatedSignatures_Batch_128(b *testing.B) {
+	benchmarkVerifyAggregatedSignatures(b, 128, true)
+}
+func BenchmarkVerifyAggregatedSignatures_Single_512(b *testing.B) {
+	benchmarkVerifyAggregatedSignatures(b, 512, false)
+}
+func BenchmarkVerifyAggregatedSignatures_Batch_512(b *testing.B) {
+	benchmarkVerifyAggregatedSignatures(b, 512, true)
+}
@@ -177,6 +177,167 @@ func TestPrestateTransactionNativeTransfer(t *testing.T) {
 	}
 }
 
+// Use the prestateTracer in diff mode to trace a native CELO transfer paying
+// for gas in cUSD, and check that the fee debit and credit made by system calls
+// show up in the fee currency storage.
+func TestPrestateDiffModeFeeCurrencyTransfer(t *testing.T) {
+	cusdAddress := common.HexToAddress("0xd008")
+	ac := test.AccountConfig(1, 2)
+	gingerbreadBlock := common.Big0
+	gc, ec, err := test.BuildConfig(ac, gingerbreadBlock)
+	require.NoError(t, err)
+	network, shutdown, err := test.NewNetwork(ac, gc, ec)
+	require.NoError(t, err)
+	defer shutdown()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*20)
+	defer cancel()
+
+	accounts := test.Accounts(ac.DeveloperAccounts(), gc.ChainConfig())
+
+	suggestedGasPrice, err := network[0].WsClient.SuggestGasPriceInCurrency(ctx, &cusdAddress)
+	require.NoError(t, err)
+	gasFeeCap := new(big.Int).Mul(suggestedGasPrice, big.NewInt(2))
+	tx, err := accounts[0].SendValueWithDynamicFee(ctx, accounts[1].Address, 1, &cusdAddress, gasFeeCap, suggestedGasPrice, network[0])
+	require.NoError(t, err)
+	err = network.AwaitTransactions(ctx, tx)
+	require.NoError(t, err)
+	c, err := rpc.DialContext(ctx, network[0].WSEndpoint())
+	require.NoError(t, err)
+
+	type account struct {
+		Balance *hexutil.Big                `json:"balance"`
+		Nonce   uint64                      `json:"nonce"`
+		Storage map[common.Hash]common.Hash `json:"storage"`
+	}
+	var result struct {
+		Pre  map[common.Address]*account `json:"pre"`
+		Post map[common.Address]*account `json:"post"`
+	}
+	tracerStr := "prestateTracer"
+	err = c.CallContext(ctx, &result, "debug_traceTransaction", tx.Hash().String(), tracers.TraceConfig{Tracer: &tracerStr, TracerConfig: json.RawMessage(`{"diffMode": true}`)})
+	require.NoError(t, err)
+
+	// The transfer itself only moves CELO and bumps the sender nonce
+	require.Equal(t, big.NewInt(1), new(big.Int).Sub(result.Post[accounts[1].Address].Balance.ToInt(), result.Pre[accounts[1].Address].Balance.ToInt()))
+	require.Equal(t, result.Pre[accounts[0].Address].Nonce+1, result.Post[accounts[0].Address].Nonce)
+	// The fees move cUSD balances, which live in the token storage
+	require.Contains(t, result.Post, cusdAddress)
+	require.NotEmpty(t, result.Post[cusdAddress].Storage)
+}
+
+// Use the feeCurrencyTracer to trace a native CELO transfer paying for gas in
+// cUSD, and check that the fee credits add up to the debit.
+func TestFeeCurrencyTracer(t *testing.T) {
+	cusdAddress := common.HexToAddress("0xd008")
+	ac := test.AccountConfig(1, 2)
+	gingerbreadBlock := common.Big0
+	gc, ec, err := test.BuildConfig(ac, gingerbreadBlock)
+	require.NoError(t, err)
+	network, shutdown, err := test.NewNetwork(ac, gc, ec)
+	require.NoError(t, err)
+	defer shutdown()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*20)
+	defer cancel()
+
+	accounts := test.Accounts(ac.DeveloperAccounts(), gc.ChainConfig())
+
+	suggestedGasPrice, err := network[0].WsClient.SuggestGasPriceInCurrency(ctx, &cusdAddress)
+	require.NoError(t, err)
+	gasFeeCap := new(big.Int).Mul(suggestedGasPrice, big.NewInt(2))
+	tx, err := accounts[0].SendValueWithDynamicFee(ctx, accounts[1].Address, 1, &cusdAddress, gasFeeCap, suggestedGasPrice, network[0])
+	require.NoError(t, err)
+	err = network.AwaitTransactions(ctx, tx)
+	require.NoError(t, err)
+	c, err := rpc.DialContext(ctx, network[0].WSEndpoint())
+	require.NoError(t, err)
+
+	var result struct {
+		FeeCurrency  *common.Address `json:"feeCurrency"`
+		ExchangeRate *struct {
+			Numerator   *hexutil.Big `json:"numerator"`
+			Denominator *hexutil.Big `json:"denominator"`
+		} `json:"exchangeRate"`
+		Debited    *hexutil.Big   `json:"debited"`
+		Refund     *hexutil.Big   `json:"refund"`
+		Tip        *hexutil.Big   `json:"tip"`
+		Coinbase   common.Address `json:"coinbase"`
+		BaseFee    *hexutil.Big   `json:"baseFee"`
+		FeeHandler common.Address `json:"feeHandler"`
+	}
+	tracerStr := "feeCurrencyTracer"
+	err = c.CallContext(ctx, &result, "debug_traceTransaction", tx.Hash().String(), tracers.TraceConfig{Tracer: &tracerStr})
+	require.NoError(t, err)
+
+	require.Equal(t, &cusdAddress, result.FeeCurrency)
+	require.NotNil(t, result.ExchangeRate)
+	require.NotEqual(t, common.ZeroAddress, result.FeeHandler)
+	credited := new(big.Int).Add(result.Refund.ToInt(), result.Tip.ToInt())
+	credited.Add(credited, result.BaseFee.ToInt())
+	require.Equal(t, result.Debited.ToInt(), credited)
+}
+
+// Use trace_filter to find a GoldToken.transfer by its sender, and trace_block
+// to get the traces of its block including the internal calls.
+func TestTraceFilterGoldTokenTransfer(t *testing.T) {
+	ac := test.AccountConfig(1, 2)
+	gingerbreadBlock := common.Big0
+	gc, ec, err := test.BuildConfig(ac, gingerbreadBlock)
+	require.NoError(t, err)
+	ec.TraceIndex = true
+	network, shutdown, err := test.NewNetwork(ac, gc, ec)
+	require.NoError(t, err)
+	defer shutdown()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*40)
+	defer cancel()
+
+	accounts := test.Accounts(ac.DeveloperAccounts(), gc.ChainConfig())
+	tx, err := accounts[0].SendCeloViaGoldToken(ctx, accounts[1].Address, 1, network[0])
+	require.NoError(t, err)
+	err = network.AwaitTransactions(ctx, tx)
+	require.NoError(t, err)
+	receipt, err := network[0].WsClient.TransactionReceipt(ctx, tx.Hash())
+	require.NoError(t, err)
+	c, err := rpc.DialContext(ctx, network[0].WSEndpoint())
+	require.NoError(t, err)
+
+	type trace struct {
+		Action struct {
+			From *common.Address `json:"from"`
+			To   *common.Address `json:"to"`
+		} `json:"action"`
+		TraceAddress    []int       `json:"traceAddress"`
+		TransactionHash common.Hash `json:"transactionHash"`
+	}
+	var traces []trace
+	filter := map[string]interface{}{
+		"fromBlock":   hexutil.EncodeBig(receipt.BlockNumber),
+		"toBlock":     hexutil.EncodeBig(receipt.BlockNumber),
+		"fromAddress": []common.Address{accounts[0].Address},
+	}
+	// trace_filter only serves indexed blocks, which are indexed 32 at a time
+	// once the last of them is imported
+	err = network.AwaitBlock(ctx, receipt.BlockNumber.Uint64()/32*32+31)
+	require.NoError(t, err)
+	for {
+		if err = c.CallContext(ctx, &traces, "trace_filter", filter); err == nil || ctx.Err() != nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.NoError(t, err)
+	// Only the top level call is made by the sender
+	require.Len(t, traces, 1)
+	require.Equal(t, tx.Hash(), traces[0].TransactionHash)
+	require.Equal(t, []int{}, traces[0].TraceAddress)
+
+	var blockTraces []trace
+	err = c.CallContext(ctx, &blockTraces, "trace_block", hexutil.EncodeBig(receipt.BlockNumber))
+	require.NoError(t, err)
+	// GoldToken calls the transfer precompile
+	require.Greater(t, len(blockTraces), 1)
+	require.Equal(t, traces[0], blockTraces[0])
+}
+
 // This test verifies correct behavior in a network of size one, in the case that
 // this fails we know that the problem does not lie with our network code.
 func TestSingleNodeNetworkManyTxs(t *testing.T) {
@@ -21,12 +21,15 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	ethereum "github.com/celo-org/celo-blockchain"
 	"github.com/celo-org/celo-blockchain/accounts"
 	"github.com/celo-org/celo-blockchain/common"
 	"github.com/celo-org/celo-blockchain/consensus"
+	"github.com/celo-org/celo-blockchain/consensus/istanbul"
 	"github.com/celo-org/celo-blockchain/contracts/blockchain_parameters"
+	"github.com/celo-org/celo-blockchain/contracts/currency"
 	"github.com/celo-org/celo-blockchain/core"
 	"github.com/celo-org/celo-blockchain/core/bloombits"
 	"github.com/celo-org/celo-blockchain/core/rawdb"
@@ -47,6 +50,7 @@ type EthAPIBackend struct {
 	extRPCEnabled       bool
 	allowUnprotectedTxs bool
 	eth                 *Ethereum
+	gpo                 *gp.Oracle
 }
 
 // ChainConfig returns the active chain configuration.
@@ -73,6 +77,9 @@ func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 	if number == rpc.LatestBlockNumber {
 		return b.eth.blockchain.CurrentBlock().Header(), nil
 	}
+	if number == rpc.LatestEpochBlockNumber {
+		number = b.latestEpochBlockNumber()
+	}
 	return b.eth.blockchain.GetHeaderByNumber(uint64(number)), nil
 }
 
@@ -107,9 +114,21 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 	if number == rpc.LatestBlockNumber {
 		return b.eth.blockchain.CurrentBlock(), nil
 	}
+	if number == rpc.LatestEpochBlockNumber {
+		number = b.latestEpochBlockNumber()
+	}
 	return b.eth.blockchain.GetBlockByNumber(uint64(number)), nil
 }
 
+// latestEpochBlockNumber resolves rpc.LatestEpochBlockNumber against the current head.
+func (b *EthAPIBackend) latestEpochBlockNumber() rpc.BlockNumber {
+	head := b.eth.blockchain.CurrentBlock().NumberU64()
+	if config := b.ChainConfig().Istanbul; config != nil {
+		return rpc.BlockNumber(istanbul.GetLatestEpochLastBlockNumber(head, config.Epoch))
+	}
+	return rpc.BlockNumber(head)
+}
+
 func (b *EthAPIBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
 	return b.eth.blockchain.GetBlockByHash(hash), nil
 }
@@ -242,6 +261,14 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return b.eth.txPool.AddLocal(signedTx)
 }
 
+func (b *EthAPIBackend) SendBundle(ctx context.Context, bundle *core.Bundle) error {
+	vmRunner, err := b.eth.BlockChain().NewEVMRunnerForCurrentBlock()
+	if err != nil {
+		return err
+	}
+	return b.eth.bundlePool.Add(bundle, b.eth.blockchain.CurrentHeader(), currency.NewManager(vmRunner))
+}
+
 func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 	pending, err := b.eth.txPool.Pending(false)
 	if err != nil {
@@ -275,10 +302,22 @@ func (b *EthAPIBackend) TxPoolContent() (map[common.Address]types.Transactions,
 	return b.eth.TxPool().Content()
 }
 
+func (b *EthAPIBackend) TxPoolFeeCurrencyStats() map[common.Address]*core.FeeCurrencyStats {
+	return b.eth.TxPool().FeeCurrencyStats()
+}
+
 func (b *EthAPIBackend) TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
 	return b.eth.TxPool().ContentFrom(addr)
 }
 
+func (b *EthAPIBackend) TxPoolRemoveTransaction(hash common.Hash) bool {
+	return b.eth.TxPool().RemoveTransaction(hash)
+}
+
+func (b *EthAPIBackend) TxPoolEvictAccount(addr common.Address, ban time.Duration) (int, error) {
+	return b.eth.TxPool().EvictAccount(addr, ban), nil
+}
+
 func (b *EthAPIBackend) TxPool() *core.TxPool {
 	return b.eth.TxPool()
 }
@@ -292,11 +331,11 @@ func (b *EthAPIBackend) SyncProgress() ethereum.SyncProgress {
 }
 
 func (b *EthAPIBackend) SuggestGasTipCap(ctx context.Context, currencyAddress *common.Address) (*big.Int, error) {
-	vmRunner, err := b.eth.BlockChain().NewEVMRunnerForCurrentBlock()
-	if err != nil {
-		return nil, err
-	}
-	return gp.GetGasTipCapSuggestion(vmRunner, currencyAddress)
+	return b.gpo.SuggestTipCap(ctx, currencyAddress)
+}
+
+func (b *EthAPIBackend) FeeHistory(ctx context.Context, blockCount int, lastBlock rpc.BlockNumber, rewardPercentiles []float64, feeCurrency *common.Address) (firstBlock *big.Int, reward [][]*big.Int, baseFee []*big.Int, gasUsedRatio []float64, err error) {
+	return b.gpo.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles, feeCurrency)
 }
 
 func (b *EthAPIBackend) CurrentGasPriceMinimum(ctx context.Context, currencyAddress *common.Address) (*big.Int, error) {
@@ -465,6 +504,10 @@ func (b *EthAPIBackend) GatewayFee() *big.Int {
 	return b.eth.GatewayFee()
 }
 
+func (b *EthAPIBackend) FeeCurrencyLimits() (map[common.Address]float64, float64) {
+	return b.eth.config.Miner.FeeCurrencyLimits, b.eth.config.Miner.FeeCurrencyDefault
+}
+
 func (b *EthAPIBackend) Engine() consensus.Engine {
 	return b.eth.engine
 }
@@ -42,7 +42,9 @@ import (
 	"github.com/celo-org/celo-blockchain/eth/downloader"
 	"github.com/celo-org/celo-blockchain/eth/ethconfig"
 	"github.com/celo-org/celo-blockchain/eth/filters"
+	"github.com/celo-org/celo-blockchain/eth/gasprice"
 	"github.com/celo-org/celo-blockchain/eth/protocols/eth"
+	"github.com/celo-org/celo-blockchain/eth/tracers"
 
 	// "github.com/celo-org/celo-blockchain/eth/protocols/snap"
 	"github.com/celo-org/celo-blockchain/ethdb"
@@ -63,12 +65,16 @@ import (
 // Deprecated: use ethconfig.Config instead.
 type Config = ethconfig.Config
 
+// bundlePoolLimit is the maximum number of bundles kept for the miner.
+const bundlePoolLimit = 1024
+
 // Ethereum implements the Ethereum full node service.
 type Ethereum struct {
 	config *ethconfig.Config
 
 	// Handlers
 	txPool             *core.TxPool
+	bundlePool         *core.BundlePool
 	blockchain         *core.BlockChain
 	handler            *handler
 	ethDialCandidates  enode.Iterator
@@ -84,6 +90,7 @@ type Ethereum struct {
 	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
 	closeBloomHandler chan struct{}
+	traceIndexer      *core.ChainIndexer // Call trace indexer operating during block imports, if enabled
 
 	APIBackend *EthAPIBackend
 
@@ -137,6 +144,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
 	chainConfig.FullHeaderChainAvailable = config.SyncMode.SyncFullHeaderChain()
+	config.TrustedEpoch = params.TrustedEpochFor(genesisHash, config.TrustedEpoch)
 
 	if err := pruner.RecoverPruning(stack.ResolvePath(""), chainDb, stack.ResolvePath(config.TrieCleanCacheJournal)); err != nil {
 		log.Error("Failed to recover state", "error", err)
@@ -210,8 +218,12 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
+	if config.TxPool.RemoteJournal != "" {
+		config.TxPool.RemoteJournal = stack.ResolvePath(config.TxPool.RemoteJournal)
+	}
 
 	eth.txPool = core.NewTxPool(config.TxPool, chainConfig, eth.blockchain)
+	eth.bundlePool = core.NewBundlePool(chainConfig, bundlePoolLimit)
 
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieCleanLimit + cacheConfig.TrieDirtyLimit + cacheConfig.SnapshotLimit
@@ -228,6 +240,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		BloomCache:   uint64(cacheLimit),
 		EventMux:     eth.eventMux,
 		Checkpoint:   checkpoint,
+		TrustedEpoch: config.TrustedEpoch,
 		Whitelist:    config.Whitelist,
 		server:       stack.Server(),
 		proxyServer:  stack.ProxyServer(),
@@ -249,7 +262,13 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	eth.miner = miner.New(eth, &config.Miner, chainConfig, eth.EventMux(), eth.engine, chainDb)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
 
-	eth.APIBackend = &EthAPIBackend{stack.Config().ExtRPCEnabled(), true, eth}
+	eth.APIBackend = &EthAPIBackend{stack.Config().ExtRPCEnabled(), true, eth, nil}
+	eth.APIBackend.gpo = gasprice.NewOracle(eth.APIBackend, config.GPO)
+
+	if config.TraceIndex {
+		eth.traceIndexer = tracers.NewTraceIndexer(eth.APIBackend, config.NoPruning)
+		eth.traceIndexer.Start(eth.blockchain)
+	}
 
 	// Setup DNS discovery iterators.
 	dnsclient := dnsdisc.NewClient(dnsdisc.Config{})
@@ -310,6 +329,16 @@ func (s *Ethereum) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// The trace APIs are served from the trace index
+	if s.traceIndexer != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "trace",
+			Version:   "1.0",
+			Service:   tracers.NewTraceAPI(s.APIBackend, s.traceIndexer),
+			Public:    false,
+		})
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -549,6 +578,7 @@ func (s *Ethereum) AccountManager() *accounts.Manager   { return s.accountManage
 func (s *Ethereum) BlockChain() *core.BlockChain        { return s.blockchain }
 func (s *Ethereum) Config() *Config                     { return s.config }
 func (s *Ethereum) TxPool() *core.TxPool                { return s.txPool }
+func (s *Ethereum) BundlePool() *core.BundlePool        { return s.bundlePool }
 func (s *Ethereum) EventMux() *event.TypeMux            { return s.eventMux }
 func (s *Ethereum) Engine() consensus.Engine            { return s.engine }
 func (s *Ethereum) ChainDb() ethdb.Database             { return s.chainDb }
@@ -610,6 +640,9 @@ func (s *Ethereum) Stop() error {
 	// Then stop everything else.
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
+	if s.traceIndexer != nil {
+		s.traceIndexer.Close()
+	}
 	s.txPool.Stop()
 	s.miner.Stop()
 	s.miner.Close()
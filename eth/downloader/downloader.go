@@ -150,6 +150,7 @@ type Downloader struct {
 	quitCh        chan struct{} // Quit channel to signal termination
 	quitLock      sync.Mutex    // Lock to prevent double closes
 	epoch         uint64        // Epoch value is useful in IBFT consensus
+	trustedEpoch  uint64        // Block number of the trusted epoch header lightest sync starts from (0 = genesis)
 	ibftConsensus bool          // True if we are in IBFT consensus mode
 
 	// Testing hooks
@@ -222,7 +223,7 @@ type BlockChain interface {
 // TODO(tim) previously passing mode here!
 
 // New creates a new downloader to fetch hashes and blocks from remote peers.
-func New(checkpoint uint64, stateDb ethdb.Database, stateBloom *trie.SyncBloom, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
+func New(checkpoint uint64, trustedEpoch *params.TrustedEpoch, stateDb ethdb.Database, stateBloom *trie.SyncBloom, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
 	if lightchain == nil {
 		lightchain = chain
 	}
@@ -239,6 +240,10 @@ func New(checkpoint uint64, stateDb ethdb.Database, stateBloom *trie.SyncBloom,
 	if epoch > math.MaxInt32 {
 		panic(fmt.Sprintf("epoch is too big(%d), the code to fetch epoch headers casts epoch to an int to calculate value for skip variable", epoch))
 	}
+	trustedEpochBlock := uint64(0)
+	if trustedEpoch != nil && ibftConsensus {
+		trustedEpochBlock = trustedEpoch.BlockNumber(epoch)
+	}
 
 	dl := &Downloader{
 		stateDB:        stateDb,
@@ -266,6 +271,7 @@ func New(checkpoint uint64, stateDb ethdb.Database, stateBloom *trie.SyncBloom,
 		trackStateReq: make(chan *stateReq),
 		ibftConsensus: ibftConsensus,
 		epoch:         epoch,
+		trustedEpoch:  trustedEpochBlock,
 	}
 	go dl.stateFetcher()
 	return dl
@@ -511,6 +517,23 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 	if err != nil {
 		return err
 	}
+	// Lightest sync doesn't need the epochs before the trusted one, as the consensus
+	// engine accepts the trusted epoch header as the root of the validator set chain.
+	// Fast sync imports the bodies and receipts of every block into a chain that
+	// can't have gaps, so it still starts from the common ancestor, but only starts
+	// verifying headers from the trusted epoch: the headers before it are
+	// authenticated by the trusted epoch header they lead to.
+	var trustedEpoch uint64
+	if origin < d.trustedEpoch && d.trustedEpoch <= height {
+		switch mode {
+		case LightestSync:
+			log.Info("Starting lightest sync from the trusted epoch", "number", d.trustedEpoch)
+			origin = d.trustedEpoch - 1
+		case FastSync:
+			log.Info("Starting fast sync verification from the trusted epoch", "number", d.trustedEpoch)
+			trustedEpoch = d.trustedEpoch
+		}
+	}
 	d.syncStatsLock.Lock()
 	if d.syncStatsChainHeight <= origin || d.syncStatsChainOrigin > origin {
 		d.syncStatsChainOrigin = origin
@@ -583,7 +606,7 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 		func() error { return d.fetchHeaders(p, origin+1, height) }, // Headers are always retrieved
 		func() error { return d.fetchBodies(origin + 1) },           // Bodies are retrieved during normal and fast sync
 		func() error { return d.fetchReceipts(origin + 1) },         // Receipts are retrieved during fast sync
-		func() error { return d.processHeaders(origin+1, td) },
+		func() error { return d.processHeaders(origin+1, td, trustedEpoch) },
 	}
 	if mode == FastSync {
 		d.pivotLock.Lock()
@@ -1650,8 +1673,9 @@ func (d *Downloader) fetchParts(deliveryCh chan dataPack, deliver func(dataPack)
 
 // processHeaders takes batches of retrieved headers from an input channel and
 // keeps processing and scheduling them into the header chain and downloader's
-// queue until the stream ends or a failure occurs.
-func (d *Downloader) processHeaders(origin uint64, td *big.Int) error {
+// queue until the stream ends or a failure occurs. Headers before trustedEpoch,
+// if set, are left for the trusted epoch header to authenticate.
+func (d *Downloader) processHeaders(origin uint64, td *big.Int, trustedEpoch uint64) error {
 	// Keep a count of uncertain headers to roll back
 	var (
 		rollback    uint64 // Zero means no rollback (fine as you can't unroll the genesis)
@@ -1768,6 +1792,9 @@ func (d *Downloader) processHeaders(origin uint64, td *big.Int) error {
 					if chunk[len(chunk)-1].Number.Uint64()+uint64(fsHeaderForceVerify) > pivot {
 						frequency = 1
 					}
+					if chunk[len(chunk)-1].Number.Uint64() < trustedEpoch {
+						frequency = 0
+					}
 					if n, err := d.lightchain.InsertHeaderChain(chunk, frequency, mode.SyncFullHeaderChain()); err != nil {
 						rollbackErr = err
 
@@ -1781,7 +1808,12 @@ func (d *Downloader) processHeaders(origin uint64, td *big.Int) error {
 					// All verifications passed, track all headers within the alloted limits
 					if mode == FastSync {
 						head := chunk[len(chunk)-1].Number.Uint64()
-						if head-rollback > uint64(fsHeaderSafetyNet) {
+						if frequency == 0 {
+							// Unverified headers stay uncertain until the trusted epoch is reached
+							if rollback == 0 {
+								rollback = chunk[0].Number.Uint64()
+							}
+						} else if head-rollback > uint64(fsHeaderSafetyNet) {
 							rollback = head - uint64(fsHeaderSafetyNet)
 						} else {
 							rollback = 1
@@ -71,16 +71,27 @@ type downloadTester struct {
 	ancientReceipts map[common.Hash]types.Receipts // Ancient receipts belonging to the tester
 	ancientChainTd  map[common.Hash]*big.Int       // Ancient total difficulties of the blocks in the local chain
 
+	config    *params.ChainConfig // Chain config of the tester, nil unless testing istanbul specific syncing
+	checkFreq map[uint64]int      // Seal verification frequency each header number was inserted with
+
 	lock sync.RWMutex
 }
 
 func (dl *downloadTester) Config() *params.ChainConfig {
-	return nil
+	return dl.config
 }
 
 // newTester creates a new downloader test mocker.
 func newTester() *downloadTester {
+	return newTesterWithConfig(nil, nil)
+}
+
+// newTesterWithConfig creates a new downloader test mocker with the given chain
+// config, starting lightest sync from the trusted epoch if any.
+func newTesterWithConfig(config *params.ChainConfig, trustedEpoch *params.TrustedEpoch) *downloadTester {
 	tester := &downloadTester{
+		config:      config,
+		checkFreq:   make(map[uint64]int),
 		genesis:     testGenesis,
 		peerDb:      testDB,
 		peers:       make(map[string]*downloadTesterPeer),
@@ -99,7 +110,7 @@ func newTester() *downloadTester {
 	tester.stateDb = rawdb.NewMemoryDatabase()
 	tester.stateDb.Put(testGenesis.Root().Bytes(), []byte{0x00})
 
-	tester.downloader = New(0, tester.stateDb, trie.NewSyncBloom(1, tester.stateDb), new(event.TypeMux), tester, nil, tester.dropPeer)
+	tester.downloader = New(0, trustedEpoch, tester.stateDb, trie.NewSyncBloom(1, tester.stateDb), new(event.TypeMux), tester, nil, tester.dropPeer)
 	return tester
 }
 
@@ -311,13 +322,13 @@ func (dl *downloadTester) InsertHeaderChain(headers []*types.Header, checkFreq i
 	dl.lock.Lock()
 	defer dl.lock.Unlock()
 	// Do a quick check, as the blockchain.InsertHeaderChain doesn't insert anything in case of errors
-	if dl.getHeaderByHash(headers[0].ParentHash) == nil {
+	if contiguousHeaders && dl.getHeaderByHash(headers[0].ParentHash) == nil {
 		return 0, fmt.Errorf("InsertHeaderChain: unknown parent at first position, parent of number %d", headers[0].Number)
 	}
 	var hashes []common.Hash
 	for i := 1; i < len(headers); i++ {
 		hash := headers[i-1].Hash()
-		if headers[i].ParentHash != headers[i-1].Hash() {
+		if contiguousHeaders && headers[i].ParentHash != headers[i-1].Hash() {
 			return i, fmt.Errorf("non-contiguous import at position %d", i)
 		}
 		hashes = append(hashes, hash)
@@ -329,12 +340,13 @@ func (dl *downloadTester) InsertHeaderChain(headers []*types.Header, checkFreq i
 		if dl.getHeaderByHash(hash) != nil {
 			continue
 		}
-		if dl.getHeaderByHash(header.ParentHash) == nil {
+		if contiguousHeaders && dl.getHeaderByHash(header.ParentHash) == nil {
 			// This _should_ be impossible, due to precheck and induction
 			return i, fmt.Errorf("InsertHeaderChain: unknown parent at position %d", i)
 		}
 		dl.ownHashes = append(dl.ownHashes, hash)
 		dl.ownHeaders[hash] = header
+		dl.checkFreq[header.Number.Uint64()] = checkFreq
 
 		dl.ownChainTd[hash] = new(big.Int).Add(header.Number, big.NewInt(1))
 	}
@@ -595,6 +607,69 @@ func testCanonSync(t *testing.T, protocol uint, mode SyncMode) {
 	assertOwnChain(t, tester, chain.len())
 }
 
+// Tests that lightest sync starts from the trusted epoch, skipping the epoch
+// headers before it.
+func TestLightestSyncFromTrustedEpoch67(t *testing.T) {
+	t.Parallel()
+
+	const epoch = 16
+	config := &params.ChainConfig{Istanbul: &params.IstanbulConfig{Epoch: epoch}}
+	chain := testChainBase.shorten(5*epoch + 5)
+	trusted := &params.TrustedEpoch{Epoch: 2, Hash: chain.headerm[chain.chain[2*epoch]].Hash()}
+
+	tester := newTesterWithConfig(config, trusted)
+	defer tester.terminate()
+	tester.newPeer("peer", istanbul.Celo67, chain)
+
+	if err := tester.sync("peer", nil, LightestSync); err != nil {
+		t.Fatalf("failed to synchronise headers: %v", err)
+	}
+	if header := tester.GetHeaderByNumber(epoch); header != nil {
+		t.Fatalf("epoch header #%d before the trusted epoch was synced", epoch)
+	}
+	for _, number := range []uint64{2 * epoch, 3 * epoch, 4 * epoch, 5 * epoch, 5*epoch + 4} {
+		if header := tester.GetHeaderByNumber(number); header == nil || header.Hash() != chain.headerm[chain.chain[number]].Hash() {
+			t.Fatalf("header #%d not synced", number)
+		}
+	}
+}
+
+// Tests that fast sync downloads the whole chain, but leaves the headers before
+// the trusted epoch for it to authenticate.
+func TestFastSyncFromTrustedEpoch67(t *testing.T) {
+	t.Parallel()
+
+	const epoch = 64
+	config := &params.ChainConfig{Istanbul: &params.IstanbulConfig{Epoch: epoch}}
+	chain := testChainBase.shorten(5*epoch + 5)
+	trusted := &params.TrustedEpoch{Epoch: 4, Hash: chain.headerm[chain.chain[4*epoch]].Hash()}
+
+	tester := newTesterWithConfig(config, trusted)
+	defer tester.terminate()
+	tester.newPeer("peer", istanbul.Celo67, chain)
+
+	if err := tester.sync("peer", nil, FastSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertOwnChain(t, tester, chain.len())
+
+	// The batches of headers before the trusted epoch are inserted without
+	// verifying any seal, the others as usual
+	tester.lock.RLock()
+	defer tester.lock.RUnlock()
+	if tester.checkFreq[1] != 0 {
+		t.Fatalf("header #1 inserted with check frequency %d, want 0", tester.checkFreq[1])
+	}
+	verified := false
+	for number := uint64(1); number < uint64(chain.len()); number++ {
+		if tester.checkFreq[number] != 0 {
+			verified = true
+		} else if verified || number >= 4*epoch {
+			t.Fatalf("header #%d inserted without verification", number)
+		}
+	}
+}
+
 // Tests that if a large batch of blocks are being downloaded, it is throttled
 // until the cached blocks are retrieved.
 func TestThrottling67Full(t *testing.T) { testThrottling(t, istanbul.Celo67, FullSync) }
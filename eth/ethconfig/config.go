@@ -29,6 +29,7 @@ import (
 	istanbulBackend "github.com/celo-org/celo-blockchain/consensus/istanbul/backend"
 	"github.com/celo-org/celo-blockchain/core"
 	"github.com/celo-org/celo-blockchain/eth/downloader"
+	"github.com/celo-org/celo-blockchain/eth/gasprice"
 	"github.com/celo-org/celo-blockchain/ethdb"
 	"github.com/celo-org/celo-blockchain/log"
 	"github.com/celo-org/celo-blockchain/miner"
@@ -36,6 +37,26 @@ import (
 	"github.com/celo-org/celo-blockchain/params"
 )
 
+// FullNodeGPO contains default gasprice oracle settings for full node.
+var FullNodeGPO = gasprice.Config{
+	Blocks:           20,
+	Percentile:       60,
+	MaxHeaderHistory: 1024,
+	MaxBlockHistory:  1024,
+	MaxPrice:         gasprice.DefaultMaxPrice,
+	IgnorePrice:      gasprice.DefaultIgnorePrice,
+}
+
+// LightClientGPO contains default gasprice oracle settings for light client.
+var LightClientGPO = gasprice.Config{
+	Blocks:           2,
+	Percentile:       60,
+	MaxHeaderHistory: 300,
+	MaxBlockHistory:  5,
+	MaxPrice:         gasprice.DefaultMaxPrice,
+	IgnorePrice:      gasprice.DefaultIgnorePrice,
+}
+
 // Defaults contains default settings for use on the Ethereum main net.
 var Defaults = Config{
 	SyncMode:                downloader.FastSync,
@@ -54,6 +75,7 @@ var Defaults = Config{
 	GatewayFee:              big.NewInt(0),
 
 	TxPool:                core.DefaultTxPoolConfig,
+	GPO:                   FullNodeGPO,
 	RPCGasInflationRate:   1.3,
 	RPCGasPriceMultiplier: big.NewInt(200),
 	RPCGasCap:             25000000,
@@ -84,6 +106,8 @@ type Config struct {
 
 	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 
+	TraceIndex bool `toml:",omitempty"` // Whether to index the call traces of each block for trace_filter
+
 	// Whitelist of required block number -> hash values to accept
 	Whitelist map[uint64]common.Hash `toml:"-"`
 
@@ -128,6 +152,9 @@ type Config struct {
 	// Transaction pool options
 	TxPool core.TxPoolConfig
 
+	// Gas Price Oracle options
+	GPO gasprice.Config
+
 	// Enables tracking of SHA3 preimages in the VM
 	EnablePreimageRecording bool
 
@@ -162,6 +189,10 @@ type Config struct {
 	// CheckpointOracle is the configuration for checkpoint oracle.
 	CheckpointOracle *params.CheckpointOracleConfig `toml:",omitempty"`
 
+	// TrustedEpoch is an epoch header lightest and fast sync start verifying from,
+	// which can be nil.
+	TrustedEpoch *params.TrustedEpoch `toml:",omitempty"`
+
 	// HFork block override (TODO: remove after the fork)
 	OverrideHFork *big.Int `toml:",omitempty"`
 
@@ -181,6 +212,7 @@ func CreateConsensusEngine(stack *node.Node, chainConfig *params.ChainConfig, co
 		if err := istanbul.ApplyParamsChainConfigToConfig(chainConfig, &config.Istanbul); err != nil {
 			log.Crit("Invalid Configuration for Istanbul Engine", "err", err)
 		}
+		config.Istanbul.TrustedEpoch = config.TrustedEpoch
 		return istanbulBackend.New(&config.Istanbul, db)
 	}
 	log.Error(fmt.Sprintf("Only Istanbul Consensus is supported: %v", chainConfig))
@@ -10,6 +10,7 @@ import (
 	"github.com/celo-org/celo-blockchain/consensus/istanbul"
 	"github.com/celo-org/celo-blockchain/core"
 	"github.com/celo-org/celo-blockchain/eth/downloader"
+	"github.com/celo-org/celo-blockchain/eth/gasprice"
 	"github.com/celo-org/celo-blockchain/miner"
 	"github.com/celo-org/celo-blockchain/params"
 )
@@ -25,6 +26,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		NoPruning               bool
 		NoPrefetch              bool
 		TxLookupLimit           uint64                 `toml:",omitempty"`
+		TraceIndex              bool                   `toml:",omitempty"`
 		Whitelist               map[uint64]common.Hash `toml:"-"`
 		LightServ               int                    `toml:",omitempty"`
 		LightIngress            int                    `toml:",omitempty"`
@@ -53,6 +55,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		Preimages               bool
 		Miner                   miner.Config
 		TxPool                  core.TxPoolConfig
+		GPO                     gasprice.Config
 		EnablePreimageRecording bool
 		Istanbul                istanbul.Config
 		DocRoot                 string `toml:"-"`
@@ -63,6 +66,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		RPCEthCompatibility     bool
 		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
 		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
+		TrustedEpoch            *params.TrustedEpoch           `toml:",omitempty"`
 		OverrideHFork           *big.Int                       `toml:",omitempty"`
 		MinSyncPeers            int                            `toml:",omitempty"`
 	}
@@ -75,6 +79,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.NoPruning = c.NoPruning
 	enc.NoPrefetch = c.NoPrefetch
 	enc.TxLookupLimit = c.TxLookupLimit
+	enc.TraceIndex = c.TraceIndex
 	enc.Whitelist = c.Whitelist
 	enc.LightServ = c.LightServ
 	enc.LightIngress = c.LightIngress
@@ -103,6 +108,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.Preimages = c.Preimages
 	enc.Miner = c.Miner
 	enc.TxPool = c.TxPool
+	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
 	enc.Istanbul = c.Istanbul
 	enc.DocRoot = c.DocRoot
@@ -113,6 +119,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.RPCEthCompatibility = c.RPCEthCompatibility
 	enc.Checkpoint = c.Checkpoint
 	enc.CheckpointOracle = c.CheckpointOracle
+	enc.TrustedEpoch = c.TrustedEpoch
 	enc.OverrideHFork = c.OverrideHFork
 	enc.MinSyncPeers = c.MinSyncPeers
 	return &enc, nil
@@ -129,6 +136,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		NoPruning               *bool
 		NoPrefetch              *bool
 		TxLookupLimit           *uint64                `toml:",omitempty"`
+		TraceIndex              *bool                  `toml:",omitempty"`
 		Whitelist               map[uint64]common.Hash `toml:"-"`
 		LightServ               *int                   `toml:",omitempty"`
 		LightIngress            *int                   `toml:",omitempty"`
@@ -157,6 +165,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		Preimages               *bool
 		Miner                   *miner.Config
 		TxPool                  *core.TxPoolConfig
+		GPO                     *gasprice.Config
 		EnablePreimageRecording *bool
 		Istanbul                *istanbul.Config
 		DocRoot                 *string `toml:"-"`
@@ -167,6 +176,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		RPCEthCompatibility     *bool
 		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
 		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
+		TrustedEpoch            *params.TrustedEpoch           `toml:",omitempty"`
 		OverrideHFork           *big.Int                       `toml:",omitempty"`
 		MinSyncPeers            *int                           `toml:",omitempty"`
 	}
@@ -198,6 +208,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TxLookupLimit != nil {
 		c.TxLookupLimit = *dec.TxLookupLimit
 	}
+	if dec.TraceIndex != nil {
+		c.TraceIndex = *dec.TraceIndex
+	}
 	if dec.Whitelist != nil {
 		c.Whitelist = dec.Whitelist
 	}
@@ -282,6 +295,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TxPool != nil {
 		c.TxPool = *dec.TxPool
 	}
+	if dec.GPO != nil {
+		c.GPO = *dec.GPO
+	}
 	if dec.EnablePreimageRecording != nil {
 		c.EnablePreimageRecording = *dec.EnablePreimageRecording
 	}
@@ -312,6 +328,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.CheckpointOracle != nil {
 		c.CheckpointOracle = dec.CheckpointOracle
 	}
+	if dec.TrustedEpoch != nil {
+		c.TrustedEpoch = dec.TrustedEpoch
+	}
 	if dec.OverrideHFork != nil {
 		c.OverrideHFork = dec.OverrideHFork
 	}
@@ -140,10 +140,55 @@ func (api *PublicFilterAPI) NewPendingTransactionFilter() rpc.ID {
 	return pendingTxSub.ID
 }
 
+// PendingTransactionsCriteria restricts the transactions sent by a
+// newPendingTransactions subscription. Every set field must match, and a list
+// matches if it contains the transaction's value. Unset fields match all.
+type PendingTransactionsCriteria struct {
+	From          []common.Address `json:"from"`
+	To            []common.Address `json:"to"`
+	FeeCurrencies []common.Address `json:"feeCurrencies"` // the zero address selects CELO
+	MinTip        *hexutil.Big     `json:"minTip"`        // in the fee currency of each transaction
+}
+
+// matches returns whether the transaction satisfies the criteria. The effective
+// tip is computed on top of the gas price minimum returned by gpm.
+func (crit *PendingTransactionsCriteria) matches(tx *types.Transaction, signer types.Signer, gpm func(currency *common.Address) (*big.Int, error)) bool {
+	if len(crit.FeeCurrencies) > 0 {
+		currency := common.ZeroAddress
+		if tx.FeeCurrency() != nil {
+			currency = *tx.FeeCurrency()
+		}
+		if !includes(crit.FeeCurrencies, currency) {
+			return false
+		}
+	}
+	if len(crit.To) > 0 && (tx.To() == nil || !includes(crit.To, *tx.To())) {
+		return false
+	}
+	if len(crit.From) > 0 {
+		from, err := types.Sender(signer, tx)
+		if err != nil || !includes(crit.From, from) {
+			return false
+		}
+	}
+	if crit.MinTip != nil {
+		gasPriceMinimum, err := gpm(tx.DenominatedFeeCurrency())
+		if err != nil {
+			log.Debug("Failed to retrieve gas price minimum for pending transaction", "hash", tx.Hash(), "err", err)
+			return false
+		}
+		if tx.EffectiveGasTipValue(gasPriceMinimum).Cmp(crit.MinTip.ToInt()) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // NewPendingTransactions creates a subscription that is triggered each time a
 // transaction enters the transaction pool. If fullTx is true the full tx is
-// sent to the client, otherwise the hash is sent.
-func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context, fullTx *bool) (*rpc.Subscription, error) {
+// sent to the client, otherwise the hash is sent. If crit is set, only the
+// transactions matching it are sent.
+func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context, fullTx *bool, crit *PendingTransactionsCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
@@ -155,6 +200,7 @@ func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context, fullTx *
 		txs := make(chan []*types.Transaction, 128)
 		pendingTxSub := api.events.SubscribePendingTxs(txs)
 		chainConfig := api.backend.ChainConfig()
+		signer := types.LatestSigner(chainConfig)
 
 		for {
 			select {
@@ -162,7 +208,26 @@ func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context, fullTx *
 				// To keep the original behaviour, send a single tx hash in one notification.
 				// TODO(rjl493456442) Send a batch of tx hashes in one notification
 				latest := api.backend.CurrentHeader()
+				gasPriceMinimums := make(map[common.Address]*big.Int)
+				gpm := func(currency *common.Address) (*big.Int, error) {
+					key := common.ZeroAddress
+					if currency != nil {
+						key = *currency
+					}
+					if gasPriceMinimum, ok := gasPriceMinimums[key]; ok {
+						return gasPriceMinimum, nil
+					}
+					gasPriceMinimum, err := api.backend.RealGasPriceMinimumForHeader(ctx, currency, latest)
+					if err != nil {
+						return nil, err
+					}
+					gasPriceMinimums[key] = gasPriceMinimum
+					return gasPriceMinimum, nil
+				}
 				for _, tx := range txs {
+					if crit != nil && !crit.matches(tx, signer, gpm) {
+						continue
+					}
 					if fullTx != nil && *fullTx {
 						rpcTx := ethapi.NewRPCPendingTransaction(tx, latest, chainConfig)
 						notifier.Notify(rpcSub.ID, rpcTx)
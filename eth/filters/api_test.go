@@ -19,9 +19,14 @@ package filters
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/common/hexutil"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/crypto"
+	"github.com/celo-org/celo-blockchain/params"
 	"github.com/celo-org/celo-blockchain/rpc"
 )
 
@@ -183,3 +188,62 @@ func TestUnmarshalJSONNewFilterArgs(t *testing.T) {
 		t.Fatalf("expected 0 topics, got %d topics", len(test7.Topics[2]))
 	}
 }
+
+func TestPendingTransactionsCriteria(t *testing.T) {
+	var (
+		key, _    = crypto.GenerateKey()
+		sender    = crypto.PubkeyToAddress(key.PublicKey)
+		recipient = common.HexToAddress("0xb794f5ea0ba39494ce83a213fffba74279579268")
+		stable    = common.HexToAddress("0x765DE816845861e75A25fCA122bb6898B8B1282a")
+		signer    = types.LatestSigner(params.TestChainConfig)
+	)
+	newTx := func(to *common.Address, feeCurrency *common.Address, tip int64) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.CeloDynamicFeeTx{
+			ChainID:     params.TestChainConfig.ChainID,
+			GasTipCap:   big.NewInt(tip),
+			GasFeeCap:   big.NewInt(1000),
+			Gas:         params.TxGas,
+			FeeCurrency: feeCurrency,
+			To:          to,
+			Value:       new(big.Int),
+		})
+	}
+	// The gas price minimum is 100 in CELO and 500 in the stable token
+	gpm := func(currency *common.Address) (*big.Int, error) {
+		if currency == nil {
+			return big.NewInt(100), nil
+		}
+		return big.NewInt(500), nil
+	}
+	var (
+		celoTx     = newTx(&recipient, nil, 300)
+		stableTx   = newTx(&recipient, &stable, 800)
+		creationTx = newTx(nil, nil, 300)
+	)
+	tests := []struct {
+		crit PendingTransactionsCriteria
+		tx   *types.Transaction
+		want bool
+	}{
+		{PendingTransactionsCriteria{}, celoTx, true},
+		{PendingTransactionsCriteria{From: []common.Address{sender}}, celoTx, true},
+		{PendingTransactionsCriteria{From: []common.Address{recipient}}, celoTx, false},
+		{PendingTransactionsCriteria{To: []common.Address{recipient}}, celoTx, true},
+		{PendingTransactionsCriteria{To: []common.Address{sender}}, celoTx, false},
+		{PendingTransactionsCriteria{To: []common.Address{recipient}}, creationTx, false},
+		{PendingTransactionsCriteria{FeeCurrencies: []common.Address{{}}}, celoTx, true},
+		{PendingTransactionsCriteria{FeeCurrencies: []common.Address{{}}}, stableTx, false},
+		{PendingTransactionsCriteria{FeeCurrencies: []common.Address{stable}}, stableTx, true},
+		// The effective tip is capped by the fee cap over the gas price minimum
+		{PendingTransactionsCriteria{MinTip: (*hexutil.Big)(big.NewInt(300))}, celoTx, true},
+		{PendingTransactionsCriteria{MinTip: (*hexutil.Big)(big.NewInt(301))}, celoTx, false},
+		{PendingTransactionsCriteria{MinTip: (*hexutil.Big)(big.NewInt(300))}, stableTx, true},
+		{PendingTransactionsCriteria{MinTip: (*hexutil.Big)(big.NewInt(500))}, stableTx, true},
+		{PendingTransactionsCriteria{MinTip: (*hexutil.Big)(big.NewInt(501))}, stableTx, false},
+	}
+	for i, test := range tests {
+		if have := test.crit.matches(test.tx, signer, gpm); have != test.want {
+			t.Errorf("test %d: match mismatch: have %v, want %v", i, have, test.want)
+		}
+	}
+}
@@ -146,6 +146,18 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	if f.end == -1 {
 		end = head
 	}
+	if f.begin == rpc.LatestEpochBlockNumber.Int64() || f.end == rpc.LatestEpochBlockNumber.Int64() {
+		header, _ := f.backend.HeaderByNumber(ctx, rpc.LatestEpochBlockNumber)
+		if header == nil {
+			return nil, nil
+		}
+		if f.begin == rpc.LatestEpochBlockNumber.Int64() {
+			f.begin = header.Number.Int64()
+		}
+		if f.end == rpc.LatestEpochBlockNumber.Int64() {
+			end = header.Number.Uint64()
+		}
+	}
 	// Gather all indexed logs, and finish with non indexed ones
 	var (
 		logs []*types.Log
@@ -0,0 +1,364 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"sync/atomic"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/contracts/blockchain_parameters"
+	gpm "github.com/celo-org/celo-blockchain/contracts/gasprice_minimum"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/log"
+	"github.com/celo-org/celo-blockchain/rpc"
+)
+
+var (
+	errInvalidPercentile = errors.New("invalid reward percentile")
+	errRequestBeyondHead = errors.New("request beyond head block")
+	errMissingBlock      = errors.New("block not found")
+)
+
+const (
+	// maxBlockFetchers is the max number of goroutines to spin up to pull blocks
+	// for the fee history calculation (mostly relevant for LES).
+	maxBlockFetchers = 4
+)
+
+// blockFees represents a single block for processing
+type blockFees struct {
+	// set by the caller
+	blockNumber uint64
+	header      *types.Header
+	block       *types.Block // only set if reward percentiles are requested
+	receipts    types.Receipts
+	// filled by processBlock
+	results processedFees
+	err     error
+}
+
+// processedFees contains the results of a processed block, denominated in the
+// requested fee currency.
+type processedFees struct {
+	reward       []*big.Int
+	baseFee      *big.Int
+	gasUsedRatio float64
+}
+
+// feeCacheKey identifies processed block fees in the history cache.
+type feeCacheKey struct {
+	hash        common.Hash
+	percentiles string
+	feeCurrency common.Address
+}
+
+// txGasAndReward is sorted in ascending order based on reward
+type (
+	txGasAndReward struct {
+		gasUsed uint64
+		reward  *big.Int
+	}
+	sortGasAndReward []txGasAndReward
+)
+
+func (s sortGasAndReward) Len() int { return len(s) }
+func (s sortGasAndReward) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+func (s sortGasAndReward) Less(i, j int) bool {
+	return s[i].reward.Cmp(s[j].reward) < 0
+}
+
+// processBlock takes a blockFees structure with the blockNumber, the header and optionally
+// the block field filled in and fills in the rest of the fields.
+func (oracle *Oracle) processBlock(ctx context.Context, bf *blockFees, percentiles []float64, feeCurrency *common.Address) {
+	parentRunner := oracle.parentRunner(ctx, bf.header)
+	if bf.results.baseFee, bf.err = blockBaseFee(bf.header, feeCurrency, parentRunner); bf.err != nil {
+		return
+	}
+
+	gasLimit := bf.header.GasLimit
+	if gasLimit == 0 {
+		// Before the Gingerbread fork the block gas limit is set by the blockchain parameters contract
+		runner, err := parentRunner()
+		if err != nil {
+			bf.err = err
+			return
+		}
+		gasLimit = blockchain_parameters.GetBlockGasLimitOrDefault(runner)
+	}
+	bf.results.gasUsedRatio = float64(bf.header.GasUsed) / float64(gasLimit)
+	if len(percentiles) == 0 {
+		// rewards were not requested, return null
+		return
+	}
+	if bf.block == nil || (bf.receipts == nil && len(bf.block.Transactions()) != 0) {
+		log.Error("Block or receipts are missing while reward percentiles are requested")
+		return
+	}
+
+	bf.results.reward = make([]*big.Int, len(percentiles))
+	if len(bf.block.Transactions()) == 0 {
+		// return an all zero row if there are no transactions to gather data from
+		for i := range bf.results.reward {
+			bf.results.reward[i] = new(big.Int)
+		}
+		return
+	}
+
+	tips, err := effectiveTips(bf.header, bf.block.Transactions(), bf.results.baseFee, feeCurrency, parentRunner)
+	if err != nil {
+		bf.err = err
+		return
+	}
+	sorter := make(sortGasAndReward, len(tips))
+	for i, tip := range tips {
+		sorter[i] = txGasAndReward{gasUsed: bf.receipts[i].GasUsed, reward: tip}
+	}
+	sort.Sort(sorter)
+
+	var txIndex int
+	sumGasUsed := sorter[0].gasUsed
+
+	for i, p := range percentiles {
+		thresholdGasUsed := uint64(float64(bf.block.GasUsed()) * p / 100)
+		for sumGasUsed < thresholdGasUsed && txIndex < len(bf.block.Transactions())-1 {
+			txIndex++
+			sumGasUsed += sorter[txIndex].gasUsed
+		}
+		bf.results.reward[i] = sorter[txIndex].reward
+	}
+}
+
+// nextBaseFee returns the base fee of the block following the given one in
+// the requested fee currency. It comes from the next header when there is one,
+// or else from the gas price minimum contract in the state the block leaves.
+func (oracle *Oracle) nextBaseFee(ctx context.Context, number uint64, pending bool, feeCurrency *common.Address) (*big.Int, error) {
+	blockNr := rpc.BlockNumber(number)
+	if pending {
+		blockNr = rpc.PendingBlockNumber
+	} else if feeCurrency == nil {
+		if next, _ := oracle.backend.HeaderByNumber(ctx, rpc.BlockNumber(number+1)); next != nil && next.BaseFee != nil {
+			return next.BaseFee, nil
+		}
+	}
+	state, header, err := oracle.backend.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHash{BlockNumber: &blockNr})
+	if err != nil {
+		return nil, err
+	}
+	return gpm.GetGasPriceMinimum(oracle.backend.NewEVMRunner(header, state), feeCurrency)
+}
+
+// resolveBlockRange resolves the specified block range to absolute block numbers while also
+// enforcing backend specific limitations. The pending block and corresponding receipts are
+// also returned if requested and available.
+// Note: an error is only returned if retrieving the head header has failed. If there are no
+// retrievable blocks in the specified range then zero block count is returned with no error.
+func (oracle *Oracle) resolveBlockRange(ctx context.Context, lastBlock rpc.BlockNumber, blocks int) (*types.Block, types.Receipts, uint64, int, error) {
+	var (
+		headBlock       rpc.BlockNumber
+		pendingBlock    *types.Block
+		pendingReceipts types.Receipts
+	)
+	// query either pending block or head header and set headBlock
+	if lastBlock == rpc.PendingBlockNumber {
+		if pendingBlock, pendingReceipts = oracle.backend.PendingBlockAndReceipts(); pendingBlock != nil {
+			lastBlock = rpc.BlockNumber(pendingBlock.NumberU64())
+			headBlock = lastBlock - 1
+		} else {
+			// pending block not supported by backend, process until latest block
+			lastBlock = rpc.LatestBlockNumber
+			blocks--
+			if blocks == 0 {
+				return nil, nil, 0, 0, nil
+			}
+		}
+	}
+	if pendingBlock == nil {
+		// if pending block is not fetched then we retrieve the head header to get the head block number
+		if latestHeader, err := oracle.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber); err == nil {
+			headBlock = rpc.BlockNumber(latestHeader.Number.Uint64())
+		} else {
+			return nil, nil, 0, 0, err
+		}
+	}
+	switch {
+	case lastBlock == rpc.LatestBlockNumber:
+		lastBlock = headBlock
+	case lastBlock < 0:
+		// other tags, such as the latest epoch block, are resolved by the backend
+		header, err := oracle.backend.HeaderByNumber(ctx, lastBlock)
+		if err != nil {
+			return nil, nil, 0, 0, err
+		}
+		if header == nil {
+			return nil, nil, 0, 0, fmt.Errorf("%w: %d", errMissingBlock, lastBlock)
+		}
+		lastBlock = rpc.BlockNumber(header.Number.Uint64())
+	case pendingBlock == nil && lastBlock > headBlock:
+		return nil, nil, 0, 0, fmt.Errorf("%w: requested %d, head %d", errRequestBeyondHead, lastBlock, headBlock)
+	}
+	// ensure not trying to retrieve before genesis
+	if rpc.BlockNumber(blocks) > lastBlock+1 {
+		blocks = int(lastBlock + 1)
+	}
+	return pendingBlock, pendingReceipts, uint64(lastBlock), blocks, nil
+}
+
+// FeeHistory returns data relevant for fee estimation based on the specified range of blocks.
+// The range can be specified either with absolute block numbers or ending with the latest
+// or pending block. Backends may or may not support gathering data from the pending block
+// or blocks older than a certain age (specified in maxHistory). The first block of the
+// actually processed range is returned to avoid ambiguity when parts of the requested range
+// are not available or when the head has changed during processing this request.
+// Three arrays are returned based on the processed blocks:
+//   - reward: the requested percentiles of effective priority fees per gas of transactions in each
+//     block, sorted in ascending order and weighted by gas used.
+//   - baseFee: base fee per gas in the given block
+//   - gasUsedRatio: gasUsed/gasLimit in the given block
+//
+// Note: baseFee includes the next block after the newest of the returned range, because this
+// value can be derived from the newest block.
+// Base fees and rewards are denominated in feeCurrency, or in CELO if it is nil, converted
+// through the gas price minimum and exchange rates in effect at each block.
+func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64, feeCurrency *common.Address) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+	if blocks < 1 {
+		return common.Big0, nil, nil, nil, nil // returning with no data and no error means there are no retrievable blocks
+	}
+	maxFeeHistory := oracle.maxHeaderHistory
+	if len(rewardPercentiles) != 0 {
+		maxFeeHistory = oracle.maxBlockHistory
+	}
+	if blocks > maxFeeHistory {
+		log.Warn("Sanitizing fee history length", "requested", blocks, "truncated", maxFeeHistory)
+		blocks = maxFeeHistory
+	}
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return common.Big0, nil, nil, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+		}
+		if i > 0 && p < rewardPercentiles[i-1] {
+			return common.Big0, nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
+		}
+	}
+	pendingBlock, pendingReceipts, lastBlock, blocks, err := oracle.resolveBlockRange(ctx, unresolvedLastBlock, blocks)
+	if err != nil || blocks == 0 {
+		return common.Big0, nil, nil, nil, err
+	}
+	oldestBlock := lastBlock + 1 - uint64(blocks)
+
+	var (
+		next    = oldestBlock
+		results = make(chan *blockFees, blocks)
+	)
+	percentileKey := make([]byte, 8*len(rewardPercentiles))
+	for i, p := range rewardPercentiles {
+		binary.LittleEndian.PutUint64(percentileKey[i*8:(i+1)*8], math.Float64bits(p))
+	}
+	var currencyKey common.Address
+	if feeCurrency != nil {
+		currencyKey = *feeCurrency
+	}
+	for i := 0; i < maxBlockFetchers && i < blocks; i++ {
+		go func() {
+			for {
+				// Retrieve the next block number to fetch with this goroutine
+				blockNumber := atomic.AddUint64(&next, 1) - 1
+				if blockNumber > lastBlock {
+					return
+				}
+
+				fees := &blockFees{blockNumber: blockNumber}
+				if pendingBlock != nil && blockNumber >= pendingBlock.NumberU64() {
+					fees.block, fees.receipts = pendingBlock, pendingReceipts
+					fees.header = fees.block.Header()
+					oracle.processBlock(ctx, fees, rewardPercentiles, feeCurrency)
+					results <- fees
+					continue
+				}
+				fees.header, fees.err = oracle.backend.HeaderByNumber(ctx, rpc.BlockNumber(blockNumber))
+				if fees.header == nil || fees.err != nil {
+					// send to results even if empty to guarantee that blocks items are sent in total
+					results <- fees
+					continue
+				}
+				cacheKey := feeCacheKey{fees.header.Hash(), string(percentileKey), currencyKey}
+				if p, ok := oracle.historyCache.Get(cacheKey); ok {
+					fees.results = p.(processedFees)
+					results <- fees
+					continue
+				}
+				if len(rewardPercentiles) != 0 {
+					fees.block, fees.err = oracle.backend.BlockByHash(ctx, fees.header.Hash())
+					if fees.block != nil && fees.err == nil {
+						fees.receipts, fees.err = oracle.backend.GetReceipts(ctx, fees.block.Hash())
+					}
+				}
+				if fees.err == nil {
+					oracle.processBlock(ctx, fees, rewardPercentiles, feeCurrency)
+					if fees.err == nil {
+						oracle.historyCache.Add(cacheKey, fees.results)
+					}
+				}
+				results <- fees
+			}
+		}()
+	}
+	var (
+		reward       = make([][]*big.Int, blocks)
+		baseFee      = make([]*big.Int, blocks+1)
+		gasUsedRatio = make([]float64, blocks)
+		firstMissing = blocks
+	)
+	for ; blocks > 0; blocks-- {
+		fees := <-results
+		if fees.err != nil {
+			return common.Big0, nil, nil, nil, fees.err
+		}
+		i := int(fees.blockNumber - oldestBlock)
+		if fees.results.baseFee != nil {
+			reward[i], baseFee[i], gasUsedRatio[i] = fees.results.reward, fees.results.baseFee, fees.results.gasUsedRatio
+		} else {
+			// getting no block and no error means we are requesting into the future (might happen because of a reorg)
+			if i < firstMissing {
+				firstMissing = i
+			}
+		}
+	}
+	if firstMissing == 0 {
+		return common.Big0, nil, nil, nil, nil
+	}
+	newest := oldestBlock + uint64(firstMissing) - 1
+	pending := pendingBlock != nil && newest >= pendingBlock.NumberU64()
+	if baseFee[firstMissing], err = oracle.nextBaseFee(ctx, newest, pending, feeCurrency); err != nil {
+		return common.Big0, nil, nil, nil, err
+	}
+	if len(rewardPercentiles) != 0 {
+		reward = reward[:firstMissing]
+	} else {
+		reward = nil
+	}
+	baseFee, gasUsedRatio = baseFee[:firstMissing+1], gasUsedRatio[:firstMissing]
+	return new(big.Int).SetUint64(oldestBlock), reward, baseFee, gasUsedRatio, nil
+}
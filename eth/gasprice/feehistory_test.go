@@ -0,0 +1,276 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/contracts/config"
+	"github.com/celo-org/celo-blockchain/contracts/testutil"
+	"github.com/celo-org/celo-blockchain/core/state"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/core/vm"
+	"github.com/celo-org/celo-blockchain/crypto"
+	"github.com/celo-org/celo-blockchain/params"
+	"github.com/celo-org/celo-blockchain/rpc"
+	"github.com/celo-org/celo-blockchain/trie"
+)
+
+var (
+	celoAddress    = common.HexToAddress("0x076")
+	altFeeCurrency = common.HexToAddress("0x0AA")
+)
+
+// testFeeBackend serves a short chain of Gingerbread blocks, each holding a
+// CELO and an altFeeCurrency transaction, backed by mocked core contracts.
+type testFeeBackend struct {
+	blocks   []*types.Block
+	receipts []types.Receipts
+	runner   *testutil.MockEVMRunner
+}
+
+func newTestFeeBackend(n int) *testFeeBackend {
+	b := &testFeeBackend{runner: testutil.NewMockEVMRunner()}
+
+	registry := testutil.NewRegistryMock()
+	b.runner.RegisterContract(config.RegistrySmartContractAddress, registry)
+	registry.AddContract(config.GoldTokenRegistryId, celoAddress)
+
+	gpmAddress := common.HexToAddress("0x090")
+	b.runner.RegisterContract(gpmAddress, testutil.NewSingleMethodContract(config.GasPriceMinimumRegistryId, "getGasPriceMinimum",
+		func(currency common.Address) *big.Int {
+			if currency == altFeeCurrency {
+				return big.NewInt(246)
+			}
+			return big.NewInt(123)
+		},
+	))
+	registry.AddContract(config.GasPriceMinimumRegistryId, gpmAddress)
+
+	sortedOracleAddress := common.HexToAddress("0x091")
+	b.runner.RegisterContract(sortedOracleAddress, testutil.NewSingleMethodContract(config.SortedOraclesRegistryId, "medianRate",
+		func(currency common.Address) (*big.Int, *big.Int) {
+			if currency == altFeeCurrency {
+				return common.Big2, common.Big1
+			}
+			return common.Big1, common.Big1
+		},
+	))
+	registry.AddContract(config.SortedOraclesRegistryId, sortedOracleAddress)
+
+	var (
+		key, _ = crypto.GenerateKey()
+		signer = types.LatestSigner(params.TestChainConfig)
+		parent common.Hash
+	)
+	for i := 0; i < n; i++ {
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     big.NewInt(int64(i)),
+			GasLimit:   1000,
+			GasUsed:    500,
+			BaseFee:    big.NewInt(100),
+		}
+		txs := []*types.Transaction{
+			types.MustSignNewTx(key, signer, &types.CeloDynamicFeeTxV2{
+				ChainID:   params.TestChainConfig.ChainID,
+				Nonce:     uint64(2 * i),
+				GasTipCap: big.NewInt(10),
+				GasFeeCap: big.NewInt(1000),
+			}),
+			types.MustSignNewTx(key, signer, &types.CeloDynamicFeeTxV2{
+				ChainID:     params.TestChainConfig.ChainID,
+				Nonce:       uint64(2*i + 1),
+				GasTipCap:   big.NewInt(40),
+				GasFeeCap:   big.NewInt(1000),
+				FeeCurrency: &altFeeCurrency,
+			}),
+		}
+		block := types.NewBlock(header, txs, nil, nil, trie.NewStackTrie(nil))
+		b.blocks = append(b.blocks, block)
+		b.receipts = append(b.receipts, types.Receipts{{GasUsed: 300}, {GasUsed: 200}})
+		parent = block.Hash()
+	}
+	return b
+}
+
+func (b *testFeeBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number == rpc.LatestBlockNumber {
+		number = rpc.BlockNumber(len(b.blocks) - 1)
+	}
+	if number < 0 || int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number].Header(), nil
+}
+
+func (b *testFeeBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	if number == rpc.LatestBlockNumber {
+		number = rpc.BlockNumber(len(b.blocks) - 1)
+	}
+	if number < 0 || int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number], nil
+}
+
+func (b *testFeeBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	for _, block := range b.blocks {
+		if block.Hash() == hash {
+			return block, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *testFeeBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	for i, block := range b.blocks {
+		if block.Hash() == hash {
+			return b.receipts[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *testFeeBackend) PendingBlockAndReceipts() (*types.Block, types.Receipts) {
+	return nil, nil
+}
+
+func (b *testFeeBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	if number, ok := blockNrOrHash.Number(); ok {
+		header, _ := b.HeaderByNumber(ctx, number)
+		return nil, header, nil
+	}
+	hash, _ := blockNrOrHash.Hash()
+	block, _ := b.BlockByHash(ctx, hash)
+	if block == nil {
+		return nil, nil, errors.New("missing state")
+	}
+	return nil, block.Header(), nil
+}
+
+func (b *testFeeBackend) NewEVMRunner(header *types.Header, state vm.StateDB) vm.EVMRunner {
+	return b.runner
+}
+
+func (b *testFeeBackend) ChainConfig() *params.ChainConfig {
+	return params.TestChainConfig
+}
+
+func TestFeeHistory(t *testing.T) {
+	config := Config{MaxHeaderHistory: 1000, MaxBlockHistory: 1000}
+
+	t.Run("should return base fees and rewards in CELO", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		oracle := NewOracle(newTestFeeBackend(4), config)
+
+		oldest, reward, baseFee, gasUsedRatio, err := oracle.FeeHistory(context.Background(), 2, rpc.LatestBlockNumber, []float64{0, 100}, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(oldest.Uint64()).To(Equal(uint64(2)))
+		g.Expect(baseFee).To(Equal([]*big.Int{big.NewInt(100), big.NewInt(100), big.NewInt(123)}))
+		g.Expect(gasUsedRatio).To(Equal([]float64{0.5, 0.5}))
+		// The altFeeCurrency tip of 40 is worth 20 CELO
+		g.Expect(reward).To(Equal([][]*big.Int{{big.NewInt(10), big.NewInt(20)}, {big.NewInt(10), big.NewInt(20)}}))
+	})
+
+	t.Run("should convert base fees and rewards to the fee currency", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		oracle := NewOracle(newTestFeeBackend(4), config)
+
+		oldest, reward, baseFee, _, err := oracle.FeeHistory(context.Background(), 2, 1, []float64{0, 100}, &altFeeCurrency)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(oldest.Uint64()).To(Equal(uint64(0)))
+		g.Expect(baseFee).To(Equal([]*big.Int{big.NewInt(200), big.NewInt(200), big.NewInt(246)}))
+		g.Expect(reward).To(Equal([][]*big.Int{{big.NewInt(20), big.NewInt(40)}, {big.NewInt(20), big.NewInt(40)}}))
+	})
+
+	t.Run("should take the next base fee from the next header", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		oracle := NewOracle(newTestFeeBackend(4), config)
+
+		_, reward, baseFee, _, err := oracle.FeeHistory(context.Background(), 1, 2, nil, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(reward).To(BeNil())
+		g.Expect(baseFee).To(Equal([]*big.Int{big.NewInt(100), big.NewInt(100)}))
+	})
+
+	t.Run("should reject invalid requests", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		oracle := NewOracle(newTestFeeBackend(4), config)
+
+		_, _, _, _, err := oracle.FeeHistory(context.Background(), 2, rpc.LatestBlockNumber, []float64{50, 10}, nil)
+		g.Expect(errors.Is(err, errInvalidPercentile)).To(BeTrue())
+
+		_, _, _, _, err = oracle.FeeHistory(context.Background(), 2, 10, nil, nil)
+		g.Expect(errors.Is(err, errRequestBeyondHead)).To(BeTrue())
+
+		// The test backend has no header for the latest epoch block
+		_, _, _, _, err = oracle.FeeHistory(context.Background(), 2, rpc.LatestEpochBlockNumber, nil, nil)
+		g.Expect(errors.Is(err, errMissingBlock)).To(BeTrue())
+	})
+}
+
+func TestSuggestTipCap(t *testing.T) {
+	config := Config{Blocks: 3, Percentile: 60, MaxHeaderHistory: 1000, MaxBlockHistory: 1000}
+
+	t.Run("should sample tips in CELO", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		oracle := NewOracle(newTestFeeBackend(4), config)
+
+		// Samples are 10 and 20 for each block, the 60th percentile is 20
+		tip, err := oracle.SuggestTipCap(context.Background(), nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(tip).To(Equal(big.NewInt(20)))
+	})
+
+	t.Run("should sample tips in the fee currency", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		oracle := NewOracle(newTestFeeBackend(4), config)
+
+		tip, err := oracle.SuggestTipCap(context.Background(), &altFeeCurrency)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(tip).To(Equal(big.NewInt(40)))
+	})
+
+	t.Run("should respect the percentile, ignore price and max price", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		lowest := config
+		lowest.Percentile = 0
+		tip, err := NewOracle(newTestFeeBackend(4), lowest).SuggestTipCap(context.Background(), nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(tip).To(Equal(big.NewInt(10)))
+
+		lowest.IgnorePrice = big.NewInt(15)
+		tip, err = NewOracle(newTestFeeBackend(4), lowest).SuggestTipCap(context.Background(), nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(tip).To(Equal(big.NewInt(20)))
+
+		// The cap is set in CELO and converted to the fee currency
+		capped := config
+		capped.MaxPrice = big.NewInt(15)
+		tip, err = NewOracle(newTestFeeBackend(4), capped).SuggestTipCap(context.Background(), &altFeeCurrency)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(tip).To(Equal(big.NewInt(30)))
+	})
+}
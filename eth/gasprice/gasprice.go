@@ -17,19 +17,397 @@
 package gasprice
 
 import (
+	"context"
 	"math/big"
+	"sort"
+	"sync"
 
 	"github.com/celo-org/celo-blockchain/common"
 	cm "github.com/celo-org/celo-blockchain/contracts/currency"
 	gpm "github.com/celo-org/celo-blockchain/contracts/gasprice_minimum"
+	"github.com/celo-org/celo-blockchain/core/state"
+	"github.com/celo-org/celo-blockchain/core/types"
 	"github.com/celo-org/celo-blockchain/core/vm"
+	"github.com/celo-org/celo-blockchain/log"
 	"github.com/celo-org/celo-blockchain/params"
+	"github.com/celo-org/celo-blockchain/rpc"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const sampleNumber = 3 // Number of transactions sampled in a block
+
+var (
+	DefaultMaxPrice    = big.NewInt(500 * params.GWei)
+	DefaultIgnorePrice = big.NewInt(2 * params.Wei)
 )
 
 var (
 	suggestionMultiplier *big.Int = big.NewInt(5) // The multiplier that we apply to the minimum when suggesting gas price
 )
 
+// Config contains the settings of the gas price oracle. Prices are
+// denominated in CELO and converted to other fee currencies when sampling.
+type Config struct {
+	Blocks           int
+	Percentile       int
+	MaxHeaderHistory int      // Maximum number of blocks eth_feeHistory returns without rewards
+	MaxBlockHistory  int      // Maximum number of blocks eth_feeHistory returns with rewards
+	MaxPrice         *big.Int `toml:",omitempty"`
+	IgnorePrice      *big.Int `toml:",omitempty"`
+}
+
+// OracleBackend includes all necessary background APIs for oracle.
+type OracleBackend interface {
+	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error)
+	BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error)
+	BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error)
+	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
+	PendingBlockAndReceipts() (*types.Block, types.Receipts)
+	StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error)
+	NewEVMRunner(header *types.Header, state vm.StateDB) vm.EVMRunner
+	ChainConfig() *params.ChainConfig
+}
+
+// tipSuggestion is the last tip cap suggested in a fee currency.
+type tipSuggestion struct {
+	head  common.Hash
+	price *big.Int
+}
+
+// Oracle recommends gas prices based on the content of recent
+// blocks. Suitable for both light and full clients.
+type Oracle struct {
+	backend     OracleBackend
+	maxPrice    *big.Int
+	ignorePrice *big.Int
+	fetchLock   sync.Mutex
+
+	checkBlocks, percentile           int
+	maxHeaderHistory, maxBlockHistory int
+
+	tipCache, historyCache *lru.Cache
+}
+
+// NewOracle returns a new gasprice oracle which can recommend suitable
+// gasprice for newly created transaction.
+func NewOracle(backend OracleBackend, params Config) *Oracle {
+	blocks := params.Blocks
+	if blocks < 1 {
+		blocks = 1
+		log.Warn("Sanitizing invalid gasprice oracle sample blocks", "provided", params.Blocks, "updated", blocks)
+	}
+	percent := params.Percentile
+	if percent < 0 {
+		percent = 0
+		log.Warn("Sanitizing invalid gasprice oracle sample percentile", "provided", params.Percentile, "updated", percent)
+	}
+	if percent > 100 {
+		percent = 100
+		log.Warn("Sanitizing invalid gasprice oracle sample percentile", "provided", params.Percentile, "updated", percent)
+	}
+	maxPrice := params.MaxPrice
+	if maxPrice == nil || maxPrice.Int64() <= 0 {
+		maxPrice = DefaultMaxPrice
+		log.Warn("Sanitizing invalid gasprice oracle price cap", "provided", params.MaxPrice, "updated", maxPrice)
+	}
+	ignorePrice := params.IgnorePrice
+	if ignorePrice == nil || ignorePrice.Int64() <= 0 {
+		ignorePrice = DefaultIgnorePrice
+		log.Warn("Sanitizing invalid gasprice oracle ignore price", "provided", params.IgnorePrice, "updated", ignorePrice)
+	} else if ignorePrice.Int64() > 0 {
+		log.Info("Gasprice oracle is ignoring threshold set", "threshold", ignorePrice)
+	}
+	maxHeaderHistory := params.MaxHeaderHistory
+	if maxHeaderHistory < 1 {
+		maxHeaderHistory = 1
+		log.Warn("Sanitizing invalid gasprice oracle max header history", "provided", params.MaxHeaderHistory, "updated", maxHeaderHistory)
+	}
+	maxBlockHistory := params.MaxBlockHistory
+	if maxBlockHistory < 1 {
+		maxBlockHistory = 1
+		log.Warn("Sanitizing invalid gasprice oracle max block history", "provided", params.MaxBlockHistory, "updated", maxBlockHistory)
+	}
+
+	// Entries are keyed by block hash, so reorgs never need a purge.
+	historyCache, _ := lru.New(2048)
+	// Suggestions are only reused on the head they were made for.
+	tipCache, _ := lru.New(64)
+	return &Oracle{
+		backend:          backend,
+		maxPrice:         maxPrice,
+		ignorePrice:      ignorePrice,
+		checkBlocks:      blocks,
+		percentile:       percent,
+		maxHeaderHistory: maxHeaderHistory,
+		maxBlockHistory:  maxBlockHistory,
+		tipCache:         tipCache,
+		historyCache:     historyCache,
+	}
+}
+
+// SuggestTipCap returns a tip cap in the given fee currency so that newly
+// created transactions have a very high chance to be included in the
+// following blocks. It samples the lowest tips of recent blocks, converted to
+// the fee currency, and picks the configured percentile. Suggestions are
+// cached per fee currency until the head changes.
+func (oracle *Oracle) SuggestTipCap(ctx context.Context, feeCurrency *common.Address) (*big.Int, error) {
+	head, err := oracle.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	headHash := head.Hash()
+
+	var cacheKey common.Address
+	if feeCurrency != nil {
+		cacheKey = *feeCurrency
+	}
+	// If the latest suggestion is still valid, return it.
+	var lastPrice *big.Int
+	if cached, ok := oracle.tipCache.Get(cacheKey); ok {
+		last := cached.(tipSuggestion)
+		if last.head == headHash {
+			return new(big.Int).Set(last.price), nil
+		}
+		lastPrice = last.price
+	}
+	oracle.fetchLock.Lock()
+	defer oracle.fetchLock.Unlock()
+
+	// Try checking the cache again, maybe the last fetch fetched what we need
+	if cached, ok := oracle.tipCache.Get(cacheKey); ok {
+		last := cached.(tipSuggestion)
+		if last.head == headHash {
+			return new(big.Int).Set(last.price), nil
+		}
+		lastPrice = last.price
+	}
+
+	// The thresholds are configured in CELO, so convert them at the head's rates.
+	maxPrice, ignorePrice := oracle.maxPrice, oracle.ignorePrice
+	if feeCurrency != nil || lastPrice == nil {
+		state, header, err := oracle.backend.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHash{BlockHash: &headHash})
+		if err != nil {
+			return nil, err
+		}
+		vmRunner := oracle.backend.NewEVMRunner(header, state)
+		if lastPrice == nil {
+			// Nothing sampled yet, start from the static suggestion.
+			if lastPrice, err = GetGasTipCapSuggestion(vmRunner, feeCurrency); err != nil {
+				return nil, err
+			}
+		}
+		exchangeRate, err := cm.GetExchangeRate(vmRunner, feeCurrency)
+		if err != nil {
+			return nil, err
+		}
+		maxPrice, ignorePrice = exchangeRate.FromBase(maxPrice), exchangeRate.FromBase(ignorePrice)
+	}
+
+	var (
+		sent, exp int
+		number    = head.Number.Uint64()
+		result    = make(chan results, oracle.checkBlocks)
+		quit      = make(chan struct{})
+		results   []*big.Int
+	)
+	for sent < oracle.checkBlocks && number > 0 {
+		go oracle.getBlockValues(ctx, number, sampleNumber, ignorePrice, feeCurrency, result, quit)
+		sent++
+		exp++
+		number--
+	}
+	for exp > 0 {
+		res := <-result
+		if res.err != nil {
+			close(quit)
+			return new(big.Int).Set(lastPrice), res.err
+		}
+		exp--
+		// Nothing returned. There are two special cases here:
+		// - The block is empty
+		// - All the transactions included are sent by the validator itself.
+		// In these cases, use the latest calculated price for sampling.
+		if len(res.values) == 0 {
+			res.values = []*big.Int{lastPrice}
+		}
+		// Besides, in order to collect enough data for sampling, if nothing
+		// meaningful returned, try to query more blocks. But the maximum
+		// is 2*checkBlocks.
+		if len(res.values) == 1 && len(results)+1+exp < oracle.checkBlocks*2 && number > 0 {
+			go oracle.getBlockValues(ctx, number, sampleNumber, ignorePrice, feeCurrency, result, quit)
+			sent++
+			exp++
+			number--
+		}
+		results = append(results, res.values...)
+	}
+	price := lastPrice
+	if len(results) > 0 {
+		sort.Sort(bigIntArray(results))
+		price = results[(len(results)-1)*oracle.percentile/100]
+	}
+	if price.Cmp(maxPrice) > 0 {
+		price = new(big.Int).Set(maxPrice)
+	}
+	oracle.tipCache.Add(cacheKey, tipSuggestion{head: headHash, price: price})
+
+	return new(big.Int).Set(price), nil
+}
+
+type results struct {
+	values []*big.Int
+	err    error
+}
+
+// getBlockValues calculates the lowest transaction tips in a given block, in
+// the given fee currency, and sends them to the result channel. The tips are
+// sorted in ascending order and those sent by the validator of the block
+// itself are left out. Tips below ignoreUnder are ignored.
+func (oracle *Oracle) getBlockValues(ctx context.Context, blockNum uint64, limit int, ignoreUnder *big.Int, feeCurrency *common.Address, result chan results, quit chan struct{}) {
+	values, err := oracle.blockValues(ctx, blockNum, limit, ignoreUnder, feeCurrency)
+	select {
+	case result <- results{values, err}:
+	case <-quit:
+	}
+}
+
+func (oracle *Oracle) blockValues(ctx context.Context, blockNum uint64, limit int, ignoreUnder *big.Int, feeCurrency *common.Address) ([]*big.Int, error) {
+	block, err := oracle.backend.BlockByNumber(ctx, rpc.BlockNumber(blockNum))
+	if block == nil {
+		return nil, err
+	}
+	if len(block.Transactions()) == 0 {
+		return nil, nil
+	}
+	parentRunner := oracle.parentRunner(ctx, block.Header())
+	baseFee, err := blockBaseFee(block.Header(), feeCurrency, parentRunner)
+	if err != nil {
+		return nil, err
+	}
+	tips, err := effectiveTips(block.Header(), block.Transactions(), baseFee, feeCurrency, parentRunner)
+	if err != nil {
+		return nil, err
+	}
+	// Sort the transactions by effective tip in ascending order.
+	sorter := make(sortTxsByTip, len(tips))
+	for i, tip := range tips {
+		sorter[i] = txTip{tx: block.Transactions()[i], tip: tip}
+	}
+	sort.Stable(sorter)
+
+	var (
+		signer = types.MakeSigner(oracle.backend.ChainConfig(), block.Number())
+		prices []*big.Int
+	)
+	for _, s := range sorter {
+		if ignoreUnder != nil && s.tip.Cmp(ignoreUnder) == -1 {
+			continue
+		}
+		sender, err := types.Sender(signer, s.tx)
+		if err == nil && sender != block.Coinbase() {
+			prices = append(prices, s.tip)
+			if len(prices) >= limit {
+				break
+			}
+		}
+	}
+	return prices, nil
+}
+
+type txTip struct {
+	tx  *types.Transaction
+	tip *big.Int
+}
+
+type sortTxsByTip []txTip
+
+func (s sortTxsByTip) Len() int           { return len(s) }
+func (s sortTxsByTip) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s sortTxsByTip) Less(i, j int) bool { return s[i].tip.Cmp(s[j].tip) < 0 }
+
+type bigIntArray []*big.Int
+
+func (s bigIntArray) Len() int           { return len(s) }
+func (s bigIntArray) Less(i, j int) bool { return s[i].Cmp(s[j]) < 0 }
+func (s bigIntArray) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// parentRunner returns a function that lazily opens an EVM runner on the state
+// of the header's parent, which holds the gas price minimum and exchange rates
+// in effect for the block.
+func (oracle *Oracle) parentRunner(ctx context.Context, header *types.Header) func() (vm.EVMRunner, error) {
+	var vmRunner vm.EVMRunner
+	return func() (vm.EVMRunner, error) {
+		if vmRunner == nil {
+			hash := header.ParentOrGenesisHash()
+			state, parent, err := oracle.backend.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHash{BlockHash: &hash})
+			if err != nil {
+				return nil, err
+			}
+			vmRunner = oracle.backend.NewEVMRunner(parent, state)
+		}
+		return vmRunner, nil
+	}
+}
+
+// blockBaseFee returns the base fee of a block in the given fee currency. The
+// parent state is only needed when the header alone is not enough.
+func blockBaseFee(header *types.Header, feeCurrency *common.Address, parentRunner func() (vm.EVMRunner, error)) (*big.Int, error) {
+	if header.BaseFee != nil && feeCurrency == nil {
+		return header.BaseFee, nil
+	}
+	runner, err := parentRunner()
+	if err != nil {
+		return nil, err
+	}
+	return GetBaseFeeForCurrency(runner, feeCurrency, header.BaseFee)
+}
+
+// effectiveTips returns the effective tip of each transaction of a block in the
+// given fee currency, where baseFee is the block's base fee in that currency.
+// Tips paid in another currency are converted through CELO at the exchange
+// rates in effect for the block.
+func effectiveTips(header *types.Header, txs types.Transactions, baseFee *big.Int, feeCurrency *common.Address, parentRunner func() (vm.EVMRunner, error)) ([]*big.Int, error) {
+	var (
+		currencies *cm.CurrencyManager
+		baseFees   = make(map[common.Address]*big.Int)
+		tips       = make([]*big.Int, len(txs))
+	)
+	for i, tx := range txs {
+		if common.AreEqualAddresses(tx.FeeCurrency(), feeCurrency) {
+			tips[i] = tx.EffectiveGasTipValue(baseFee)
+			continue
+		}
+		runner, err := parentRunner()
+		if err != nil {
+			return nil, err
+		}
+		var key common.Address
+		if tx.FeeCurrency() != nil {
+			key = *tx.FeeCurrency()
+		}
+		txBaseFee, ok := baseFees[key]
+		if !ok {
+			if txBaseFee, err = GetBaseFeeForCurrency(runner, tx.FeeCurrency(), header.BaseFee); err != nil {
+				return nil, err
+			}
+			baseFees[key] = txBaseFee
+		}
+		if currencies == nil {
+			currencies = cm.NewManager(runner)
+		}
+		from, err := currencies.GetCurrency(tx.FeeCurrency())
+		if err != nil {
+			return nil, err
+		}
+		to, err := currencies.GetCurrency(feeCurrency)
+		if err != nil {
+			return nil, err
+		}
+		tips[i] = to.FromCELO(from.ToCELO(tx.EffectiveGasTipValue(txBaseFee)))
+	}
+	return tips, nil
+}
+
 func GetBaseFeeForCurrency(vmRunner vm.EVMRunner, currencyAddress *common.Address, baseFee *big.Int) (*big.Int, error) {
 	if baseFee == nil {
 		return gpm.GetGasPriceMinimum(vmRunner, currencyAddress)
@@ -86,6 +86,7 @@ type handlerConfig struct {
 	BloomCache   uint64                    // Megabytes to alloc for fast sync bloom
 	EventMux     *event.TypeMux            // Legacy event mux, deprecate for `feed`
 	Checkpoint   *params.TrustedCheckpoint // Hard coded checkpoint for sync challenges
+	TrustedEpoch *params.TrustedEpoch      // Epoch header lightest sync starts verifying from
 	Whitelist    map[uint64]common.Hash    // Hard coded whitelist for sync challenged
 	server       *p2p.Server
 	proxyServer  *p2p.Server
@@ -196,7 +197,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 	if atomic.LoadUint32(&h.fastSync) == 1 && atomic.LoadUint32(&h.snapSync) == 0 {
 		h.stateBloom = trie.NewSyncBloom(config.BloomCache, config.Database)
 	}
-	h.downloader = downloader.New(h.checkpointNumber, config.Database, h.stateBloom, h.eventMux, h.chain, nil, h.removePeer)
+	h.downloader = downloader.New(h.checkpointNumber, config.TrustedEpoch, config.Database, h.stateBloom, h.eventMux, h.chain, nil, h.removePeer)
 
 	// Construct the fetcher (short sync)
 	validator := func(header *types.Header) error {
@@ -542,6 +543,10 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 	)
 	// Broadcast transactions to a batch of peers not knowing about it
 	for _, tx := range txs {
+		// Conditional transactions are only meant for the local miner
+		if tx.Conditional() != nil {
+			continue
+		}
 		peers := h.peers.peersWithoutTransaction(tx.Hash())
 		// Send the tx unconditionally to a subset of our peers
 		numDirect := int(math.Sqrt(float64(len(peers))))
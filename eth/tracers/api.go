@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -184,6 +185,13 @@ type TraceConfig struct {
 	Tracer  *string
 	Timeout *string
 	Reexec  *uint64
+	// Config specific to given tracer. Note struct logger
+	// config are historically embedded in main object.
+	TracerConfig json.RawMessage
+	// SystemCalls includes the state changing system calls made outside of
+	// transactions, like randomness commitments and block finalization, in
+	// block traces as pseudo-transactions.
+	SystemCalls bool
 }
 
 // TraceCallConfig is the config for traceCall API. It holds one more
@@ -193,6 +201,7 @@ type TraceCallConfig struct {
 	Tracer         *string
 	Timeout        *string
 	Reexec         *uint64
+	TracerConfig   json.RawMessage
 	StateOverrides *ethapi.StateOverride
 }
 
@@ -205,9 +214,10 @@ type StdTraceConfig struct {
 
 // txTraceResult is the result of a single transaction trace.
 type txTraceResult struct {
-	TxHash common.Hash `json:"txHash"`           // transaction hash
-	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
-	Error  string      `json:"error,omitempty"`  // Trace failure produced by the tracer
+	TxHash     common.Hash `json:"txHash"`               // transaction hash
+	SystemCall *systemCall `json:"systemCall,omitempty"` // System call traced as a pseudo-transaction, if any
+	Result     interface{} `json:"result,omitempty"`     // Trace results produced by the tracer
+	Error      string      `json:"error,omitempty"`      // Trace failure produced by the tracer
 }
 
 // blockTraceTask represents a single block trace task when an entire chain is
@@ -627,10 +637,18 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	if config != nil && config.Reexec != nil {
 		reexec = *config.Reexec
 	}
-	statedb, err := api.backend.StateAtBlock(ctx, parent, reexec, nil, true, false, true)
+	// Trace the random commitment ourselves when tracing system calls
+	systemCalls := config != nil && config.SystemCalls
+	statedb, err := api.backend.StateAtBlock(ctx, parent, reexec, nil, true, false, !systemCalls)
 	if err != nil {
 		return nil, err
 	}
+	var randomness []*txTraceResult
+	if systemCalls {
+		if randomness, err = api.traceRandomness(ctx, block, statedb, config); err != nil {
+			return nil, err
+		}
+	}
 	// Execute all the transaction contained within the block concurrently
 	var (
 		signer  = types.MakeSigner(api.backend.ChainConfig(), block.Number())
@@ -703,6 +721,10 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	if failed != nil {
 		return nil, failed
 	}
+	if systemCalls {
+		finalize := api.traceFinalize(ctx, block, statedb, config)
+		results = append(append(randomness, results...), finalize...)
+	}
 	return results, nil
 }
 
@@ -944,10 +966,11 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 	var traceConfig *TraceConfig
 	if config != nil {
 		traceConfig = &TraceConfig{
-			LogConfig: config.LogConfig,
-			Tracer:    config.Tracer,
-			Timeout:   config.Timeout,
-			Reexec:    config.Reexec,
+			LogConfig:    config.LogConfig,
+			Tracer:       config.Tracer,
+			Timeout:      config.Timeout,
+			Reexec:       config.Reexec,
+			TracerConfig: config.TracerConfig,
 		}
 	}
 	vmctx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
@@ -959,11 +982,25 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
 func (api *API) traceTx(ctx context.Context, message core.Message, txctx *Context, vmctx vm.BlockContext, vmRunner vm.EVMRunner, statedb *state.StateDB, sysCtx *core.SysContractCallCtx, config *TraceConfig) (interface{}, error) {
+	txContext := core.NewEVMTxContext(message)
+
+	// Call Prepare to clear out the statedb access list
+	statedb.Prepare(txctx.TxHash, txctx.TxIndex)
+
+	return api.trace(ctx, txctx, statedb, config, func(tracer vm.EVMLogger) (*core.ExecutionResult, error) {
+		vmenv := vm.NewEVM(vmctx, txContext, statedb, api.backend.ChainConfig(), vm.Config{Debug: true, Tracer: tracer, NoBaseFee: true})
+		return core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()), vmRunner, sysCtx)
+	})
+}
+
+// trace configures a new tracer according to the provided configuration, and
+// runs the given execution on top of statedb with it. The return value will be
+// tracer dependent.
+func (api *API) trace(ctx context.Context, txctx *Context, statedb *state.StateDB, config *TraceConfig, run func(tracer vm.EVMLogger) (*core.ExecutionResult, error)) (interface{}, error) {
 	// Assemble the structured logger or the JavaScript tracer
 	var (
-		tracer    vm.EVMLogger
-		err       error
-		txContext = core.NewEVMTxContext(message)
+		tracer vm.EVMLogger
+		err    error
 	)
 	switch {
 	case config == nil:
@@ -976,7 +1013,7 @@ func (api *API) traceTx(ctx context.Context, message core.Message, txctx *Contex
 				return nil, err
 			}
 		}
-		if t, err := New(*config.Tracer, txctx); err != nil {
+		if t, err := New(*config.Tracer, txctx, config.TracerConfig); err != nil {
 			return nil, err
 		} else {
 			deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
@@ -992,16 +1029,18 @@ func (api *API) traceTx(ctx context.Context, message core.Message, txctx *Contex
 	default:
 		tracer = vm.NewStructLogger(config.LogConfig)
 	}
-	// Run the transaction with tracing enabled.
-	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.backend.ChainConfig(), vm.Config{Debug: true, Tracer: tracer, NoBaseFee: true})
-
-	// Call Prepare to clear out the statedb access list
-	statedb.Prepare(txctx.TxHash, txctx.TxIndex)
-
-	result, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()), vmRunner, sysCtx)
+	// Run with tracing enabled.
+	stateTracer, _ := tracer.(StateTracer)
+	if stateTracer != nil {
+		stateTracer.CaptureTxStart(statedb.Copy())
+	}
+	result, err := run(tracer)
 	if err != nil {
 		return nil, fmt.Errorf("tracing failed: %w", err)
 	}
+	if stateTracer != nil {
+		stateTracer.CaptureTxEnd(statedb)
+	}
 
 	// Depending on the tracer type, format and return the output.
 	switch tracer := tracer.(type) {
@@ -60,6 +60,10 @@ type testBackend struct {
 }
 
 func newTestBackend(t *testing.T, n int, gspec *core.Genesis, generator func(i int, b *core.BlockGen)) *testBackend {
+	return newTestBackendWithEngine(t, n, gspec, mockEngine.NewFaker(), generator)
+}
+
+func newTestBackendWithEngine(t *testing.T, n int, gspec *core.Genesis, engine consensus.Engine, generator func(i int, b *core.BlockGen)) *testBackend {
 	chainConfig := params.TestChainConfig
 	if gspec.Config != nil {
 		chainConfig = gspec.Config
@@ -67,7 +71,7 @@ func newTestBackend(t *testing.T, n int, gspec *core.Genesis, generator func(i i
 	chainConfig.Faker = true
 	backend := &testBackend{
 		chainConfig: chainConfig,
-		engine:      mockEngine.NewFaker(),
+		engine:      engine,
 		chaindb:     rawdb.NewMemoryDatabase(),
 	}
 	// Generate blocks for testing
@@ -432,6 +436,139 @@ func TestTraceBlock(t *testing.T) {
 	}
 }
 
+// systemCallEngine is a fake engine incrementing the first storage slot of a
+// contract when finalizing blocks, through a system call if the chain allows.
+type systemCallEngine struct {
+	*mockEngine.MockEngine
+	counter common.Address
+}
+
+func (e *systemCallEngine) Finalize(chain consensus.ChainHeaderReader, header *types.Header, statedb *state.StateDB, txs []*types.Transaction) {
+	if chain, ok := chain.(consensus.ChainContext); ok {
+		chain.NewEVMRunner(header, statedb).Execute(e.counter, nil, 100000, common.Big0)
+	} else {
+		e.increment(statedb)
+	}
+	e.MockEngine.Finalize(chain, header, statedb, txs)
+}
+
+func (e *systemCallEngine) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, statedb *state.StateDB, txs []*types.Transaction, receipts []*types.Receipt, randomness *types.Randomness) (*types.Block, error) {
+	e.increment(statedb)
+	return e.MockEngine.FinalizeAndAssemble(chain, header, statedb, txs, receipts, randomness)
+}
+
+func (e *systemCallEngine) increment(statedb *state.StateDB) {
+	count := statedb.GetState(e.counter, common.Hash{}).Big()
+	statedb.SetState(e.counter, common.Hash{}, common.BigToHash(count.Add(count, common.Big1)))
+}
+
+func TestTraceBlockSystemCalls(t *testing.T) {
+	t.Parallel()
+
+	accounts := newAccounts(2)
+	counter := common.HexToAddress("0x00000000000000000000000000000000c0ffee")
+	genesis := &core.Genesis{Alloc: core.GenesisAlloc{
+		accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		counter: {
+			Balance: common.Big0,
+			// slot 0 += 1
+			Code: []byte{
+				byte(vm.PUSH1), 0x0, byte(vm.SLOAD), byte(vm.PUSH1), 0x1, byte(vm.ADD),
+				byte(vm.PUSH1), 0x0, byte(vm.SSTORE),
+			},
+		},
+	}}
+	genBlocks := 2
+	signer := types.HomesteadSigner{}
+	var txHash common.Hash
+	engine := &systemCallEngine{MockEngine: mockEngine.NewFaker(), counter: counter}
+	backend := newTestBackendWithEngine(t, genBlocks, genesis, engine, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), accounts[1].addr, big.NewInt(1000), params.TxGas, b.MinimumGasPrice(nil), nil), signer, accounts[0].key)
+		b.AddTx(tx)
+		txHash = tx.Hash()
+	})
+	api := NewAPI(backend)
+
+	// Without the option only the transactions are traced
+	results, err := api.TraceBlockByNumber(context.Background(), rpc.BlockNumber(genBlocks), nil)
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	if len(results) != 1 || results[0].TxHash != txHash {
+		t.Fatalf("unexpected traces: %v", results)
+	}
+	results, err = api.TraceBlockByNumber(context.Background(), rpc.BlockNumber(genBlocks), &TraceConfig{SystemCalls: true})
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	if len(results) != 2 || results[0].TxHash != txHash || results[0].SystemCall != nil {
+		t.Fatalf("unexpected traces: %v", results)
+	}
+	want := systemCall{Phase: systemCallFinalize, From: common.ZeroAddress, To: counter}
+	if call := results[1].SystemCall; call == nil || *call != want {
+		t.Fatalf("system call mismatch: have %v, want %v", call, want)
+	}
+	res, ok := results[1].Result.(*ethapi.ExecutionResult)
+	if !ok || res.Failed || len(res.StructLogs) != 7 {
+		t.Fatalf("unexpected system call trace: %v", results[1].Result)
+	}
+	// The system call ran on top of the state left by the block's transactions
+	if storage := res.StructLogs[5].Storage; storage == nil || (*storage)[fmt.Sprintf("%x", common.Hash{})] != fmt.Sprintf("%x", common.BigToHash(big.NewInt(int64(genBlocks)))) {
+		t.Fatalf("unexpected counter update: %v", storage)
+	}
+}
+
+func TestSystemCallRunnerGasMetering(t *testing.T) {
+	t.Parallel()
+
+	reader := common.HexToAddress("0x00000000000000000000000000000000c0ffee")
+	genesis := &core.Genesis{Alloc: core.GenesisAlloc{
+		reader: {
+			Balance: common.Big0,
+			// return slot 0
+			Code: []byte{
+				byte(vm.PUSH1), 0x0, byte(vm.SLOAD), byte(vm.PUSH1), 0x0, byte(vm.MSTORE),
+				byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x0, byte(vm.RETURN),
+			},
+			Storage: map[common.Hash]common.Hash{{}: common.BigToHash(big.NewInt(42))},
+		},
+	}}
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {})
+	api := NewAPI(backend)
+	block := backend.chain.CurrentBlock()
+	statedb, err := backend.chain.StateAt(block.Root())
+	if err != nil {
+		t.Fatalf("failed to get state: %v", err)
+	}
+	runner := api.newSystemCallRunner(context.Background(), systemCallFinalize, block, 0, statedb, &TraceConfig{})
+
+	// Without gas, the calls only succeed once gas metering is stopped
+	want := common.BigToHash(big.NewInt(42)).Bytes()
+	for _, metered := range []bool{true, false} {
+		if metered {
+			runner.StartGasMetering()
+		} else {
+			runner.StopGasMetering()
+		}
+		calls := map[string]func() ([]byte, error){
+			"Query": func() ([]byte, error) { return runner.Query(reader, nil, 0) },
+			"ExecuteAndDiscardChanges": func() ([]byte, error) {
+				return runner.ExecuteAndDiscardChanges(reader, nil, 0, common.Big0)
+			},
+			"Execute": func() ([]byte, error) { return runner.Execute(reader, nil, 0, common.Big0) },
+		}
+		for name, call := range calls {
+			ret, err := call()
+			if metered && !errors.Is(err, vm.ErrOutOfGas) {
+				t.Errorf("metered %s: have %v, want %v", name, err, vm.ErrOutOfGas)
+			}
+			if !metered && (err != nil || !bytes.Equal(ret, want)) {
+				t.Errorf("unmetered %s: have %x %v, want %x", name, ret, err, want)
+			}
+		}
+	}
+}
+
 func TestTracingWithOverrides(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
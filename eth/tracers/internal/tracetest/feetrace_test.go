@@ -0,0 +1,114 @@
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/common/hexutil"
+	"github.com/celo-org/celo-blockchain/contracts/testutil"
+	"github.com/celo-org/celo-blockchain/core"
+	"github.com/celo-org/celo-blockchain/core/rawdb"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/core/vm"
+	"github.com/celo-org/celo-blockchain/core/vm/vmcontext"
+	"github.com/celo-org/celo-blockchain/crypto"
+	"github.com/celo-org/celo-blockchain/eth/tracers"
+	"github.com/celo-org/celo-blockchain/params"
+	"github.com/celo-org/celo-blockchain/tests"
+)
+
+type feeCurrencyResult struct {
+	FeeCurrency *common.Address `json:"feeCurrency"`
+	Debited     *hexutil.Big    `json:"debited"`
+	Refund      *hexutil.Big    `json:"refund"`
+	Tip         *hexutil.Big    `json:"tip"`
+	Coinbase    common.Address  `json:"coinbase"`
+	BaseFee     *hexutil.Big    `json:"baseFee"`
+}
+
+func TestFeeCurrencyTracer(t *testing.T) {
+	celoMock := testutil.NewCeloMock()
+	var (
+		to       = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+		coinbase = common.HexToAddress("0x00000000000000000000000000000000c0ffee00")
+	)
+	privkey, err := crypto.HexToECDSA("0000000000000000deadbeef00000000000000000000000000000000deadbeef")
+	if err != nil {
+		t.Fatalf("err %v", err)
+	}
+	signer := types.NewEIP155Signer(big.NewInt(1))
+	tx, err := types.SignNewTx(privkey, signer, &types.LegacyTx{
+		GasPrice: big.NewInt(3),
+		Gas:      50000,
+		To:       &to,
+		Value:    big.NewInt(7),
+	})
+	if err != nil {
+		t.Fatalf("err %v", err)
+	}
+	origin, _ := signer.Sender(tx)
+	txContext := vm.TxContext{
+		Origin:   origin,
+		GasPrice: tx.GasPrice(),
+	}
+	context := vm.BlockContext{
+		CanTransfer: vmcontext.CanTransfer,
+		Transfer:    vmcontext.TobinTransfer,
+		Coinbase:    coinbase,
+		BlockNumber: new(big.Int).SetUint64(8000000),
+		Time:        new(big.Int).SetUint64(5),
+	}
+	var alloc = core.GenesisAlloc{
+		origin: core.GenesisAccount{
+			Nonce:   0,
+			Balance: big.NewInt(500000000000000),
+		},
+	}
+	_, statedb := tests.MakePreState(rawdb.NewMemoryDatabase(), alloc, false)
+
+	tracer, err := tracers.New("feeCurrencyTracer", new(tracers.Context), nil)
+	if err != nil {
+		t.Fatalf("failed to create fee currency tracer: %v", err)
+	}
+	evm := vm.NewEVM(context, txContext, statedb, params.MainnetChainConfig, vm.Config{Debug: true, Tracer: tracer})
+	msg, err := tx.AsMessage(signer, nil)
+	if err != nil {
+		t.Fatalf("failed to prepare transaction for tracing: %v", err)
+	}
+	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(tx.Gas()), celoMock.Runner, nil)
+	result, err := st.TransitionDb()
+	if err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	have := new(feeCurrencyResult)
+	if err := json.Unmarshal(res, have); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	if have.FeeCurrency != nil {
+		t.Fatalf("fee currency mismatch: have %v, want CELO", have.FeeCurrency)
+	}
+	if have.Coinbase != coinbase {
+		t.Fatalf("coinbase mismatch: have %v, want %v", have.Coinbase, coinbase)
+	}
+	if want := big.NewInt(3 * 50000); have.Debited.ToInt().Cmp(want) != 0 {
+		t.Fatalf("debited mismatch: have %v, want %v", have.Debited, want)
+	}
+	credited := new(big.Int).Add(have.Refund.ToInt(), have.Tip.ToInt())
+	credited.Add(credited, have.BaseFee.ToInt())
+	if credited.Cmp(have.Debited.ToInt()) != 0 {
+		t.Fatalf("credits do not add up to the debit: have %v, want %v", credited, have.Debited)
+	}
+	// The tip is reported as credited to the coinbase
+	if statedb.GetBalance(coinbase).Cmp(have.Tip.ToInt()) != 0 {
+		t.Fatalf("coinbase balance mismatch: have %v, want %v", statedb.GetBalance(coinbase), have.Tip)
+	}
+	if want := new(big.Int).SetUint64(3 * (50000 - result.UsedGas)); have.Refund.ToInt().Cmp(want) < 0 {
+		t.Fatalf("refund too low: have %v, want at least %v", have.Refund, want)
+	}
+}
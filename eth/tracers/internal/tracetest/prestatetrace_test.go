@@ -0,0 +1,160 @@
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/common/hexutil"
+	"github.com/celo-org/celo-blockchain/contracts/testutil"
+	"github.com/celo-org/celo-blockchain/core"
+	"github.com/celo-org/celo-blockchain/core/rawdb"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/core/vm"
+	"github.com/celo-org/celo-blockchain/core/vm/vmcontext"
+	"github.com/celo-org/celo-blockchain/crypto"
+	"github.com/celo-org/celo-blockchain/eth/tracers"
+	"github.com/celo-org/celo-blockchain/params"
+	"github.com/celo-org/celo-blockchain/tests"
+)
+
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Code    hexutil.Bytes               `json:"code"`
+	Nonce   uint64                      `json:"nonce"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+type prestateDiff struct {
+	Pre  map[common.Address]*prestateAccount `json:"pre"`
+	Post map[common.Address]*prestateAccount `json:"post"`
+}
+
+// runPrestateTracer runs a tx writing storage slot 0 and reading slot 1 of the
+// called contract through the prestateTracer with the given config.
+func runPrestateTracer(t *testing.T, cfg string) (json.RawMessage, common.Address, common.Address) {
+	celoMock := testutil.NewCeloMock()
+	var to = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+	privkey, err := crypto.HexToECDSA("0000000000000000deadbeef00000000000000000000000000000000deadbeef")
+	if err != nil {
+		t.Fatalf("err %v", err)
+	}
+	signer := types.NewEIP155Signer(big.NewInt(1))
+	tx, err := types.SignNewTx(privkey, signer, &types.LegacyTx{
+		GasPrice: big.NewInt(1),
+		Gas:      50000,
+		To:       &to,
+		Value:    big.NewInt(7),
+	})
+	if err != nil {
+		t.Fatalf("err %v", err)
+	}
+	origin, _ := signer.Sender(tx)
+	txContext := vm.TxContext{
+		Origin:   origin,
+		GasPrice: tx.GasPrice(),
+	}
+	context := vm.BlockContext{
+		CanTransfer: vmcontext.CanTransfer,
+		Transfer:    vmcontext.TobinTransfer,
+		Coinbase:    common.Address{},
+		BlockNumber: new(big.Int).SetUint64(8000000),
+		Time:        new(big.Int).SetUint64(5),
+	}
+	var code = []byte{
+		byte(vm.PUSH1), 0x1, byte(vm.PUSH1), 0x0, byte(vm.SSTORE), // slot 0 = 1
+		byte(vm.PUSH1), 0x1, byte(vm.SLOAD), byte(vm.POP), // read slot 1
+	}
+	var alloc = core.GenesisAlloc{
+		to: core.GenesisAccount{
+			Nonce:   1,
+			Code:    code,
+			Storage: map[common.Hash]common.Hash{common.HexToHash("0x1"): common.HexToHash("0x2")},
+			Balance: big.NewInt(0),
+		},
+		origin: core.GenesisAccount{
+			Nonce:   0,
+			Balance: big.NewInt(500000000000000),
+		},
+	}
+	_, statedb := tests.MakePreState(rawdb.NewMemoryDatabase(), alloc, false)
+
+	tracer, err := tracers.New("prestateTracer", new(tracers.Context), json.RawMessage(cfg))
+	if err != nil {
+		t.Fatalf("failed to create prestate tracer: %v", err)
+	}
+	stateTracer, ok := tracer.(tracers.StateTracer)
+	if !ok {
+		t.Fatalf("prestate tracer does not implement StateTracer")
+	}
+	evm := vm.NewEVM(context, txContext, statedb, params.MainnetChainConfig, vm.Config{Debug: true, Tracer: tracer})
+	msg, err := tx.AsMessage(signer, nil)
+	if err != nil {
+		t.Fatalf("failed to prepare transaction for tracing: %v", err)
+	}
+	stateTracer.CaptureTxStart(statedb.Copy())
+	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(tx.Gas()), celoMock.Runner, nil)
+	if _, err = st.TransitionDb(); err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	stateTracer.CaptureTxEnd(statedb)
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	return res, origin, to
+}
+
+func TestPrestateTracer(t *testing.T) {
+	res, origin, to := runPrestateTracer(t, `{}`)
+	have := make(map[common.Address]*prestateAccount)
+	if err := json.Unmarshal(res, &have); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	if acc := have[origin]; acc == nil || acc.Balance.ToInt().Cmp(big.NewInt(500000000000000)) != 0 || acc.Nonce != 0 {
+		t.Fatalf("sender prestate mismatch: %+v", acc)
+	}
+	acc := have[to]
+	if acc == nil {
+		t.Fatalf("contract missing from prestate")
+	}
+	want := map[common.Hash]common.Hash{
+		common.HexToHash("0x0"): {},
+		common.HexToHash("0x1"): common.HexToHash("0x2"),
+	}
+	if len(acc.Storage) != len(want) {
+		t.Fatalf("contract storage mismatch: have %v, want %v", acc.Storage, want)
+	}
+	for slot, value := range want {
+		if acc.Storage[slot] != value {
+			t.Fatalf("contract slot %x mismatch: have %x, want %x", slot, acc.Storage[slot], value)
+		}
+	}
+}
+
+func TestPrestateTracerDiffMode(t *testing.T) {
+	res, origin, to := runPrestateTracer(t, `{"diffMode": true}`)
+	have := new(prestateDiff)
+	if err := json.Unmarshal(res, have); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	// The unchanged slot is left out, the written one is reported on both sides
+	pre, post := have.Pre[to], have.Post[to]
+	if pre == nil || post == nil {
+		t.Fatalf("contract missing from diff: %s", res)
+	}
+	if len(pre.Storage) != 1 || pre.Storage[common.HexToHash("0x0")] != (common.Hash{}) {
+		t.Fatalf("contract pre storage mismatch: %v", pre.Storage)
+	}
+	if len(post.Storage) != 1 || post.Storage[common.HexToHash("0x0")] != common.HexToHash("0x1") {
+		t.Fatalf("contract post storage mismatch: %v", post.Storage)
+	}
+	if post.Balance.ToInt().Cmp(big.NewInt(7)) != 0 {
+		t.Fatalf("contract post balance mismatch: have %v, want 7", post.Balance)
+	}
+	// The sender paid for the value and the gas, and bumped its nonce
+	if post := have.Post[origin]; post == nil || post.Nonce != 1 || post.Balance.ToInt().Cmp(big.NewInt(500000000000000)) >= 0 {
+		t.Fatalf("sender post state mismatch: %+v", post)
+	}
+}
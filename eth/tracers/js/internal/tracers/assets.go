@@ -8,7 +8,7 @@
 // evmdis_tracer.js (4.195kB)
 // noop_tracer.js (1.271kB)
 // opcount_tracer.js (1.372kB)
-// prestate_tracer.js (4.482kB)
+// prestate_tracer_legacy.js (4.482kB)
 // trigram_tracer.js (1.788kB)
 // unigram_tracer.js (1.469kB)
 
@@ -238,22 +238,22 @@ func opcount_tracerJs() (*asset, error) {
 	return a, nil
 }
 
-var _prestate_tracerJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x57\xdb\x6e\x1b\x39\x12\x7d\x56\x7f\x45\x21\x2f\x92\x36\x4a\x2b\xf1\x00\xb3\x80\xbc\x5e\xa0\xa3\x28\xb1\x00\x8f\x6d\x48\xf2\x7a\xbd\x83\x79\x60\x93\xd5\x2d\x8e\x28\xb2\x41\xb2\x25\x6b\x03\xff\xfb\xa2\xd8\x17\x5d\xe2\x4b\x66\xdf\xd4\x64\xf1\x54\xd5\x61\xd5\x61\x69\x38\x84\xb1\x29\x76\x56\xe6\x4b\x0f\x67\x1f\x3f\xfd\x1d\x16\x4b\x84\xdc\x7c\x40\xbf\x44\x8b\xe5\x1a\x92\xd2\x2f\x8d\x75\xd1\x70\x08\x8b\xa5\x74\x90\x49\x85\x20\x1d\x14\xcc\x7a\x30\x19\xf8\x13\x7b\x25\x53\xcb\xec\x2e\x8e\x86\xc3\xea\xcc\xb3\xdb\x84\x90\x59\x44\x70\x26\xf3\x5b\x66\x71\x04\x3b\x53\x02\x67\x1a\x2c\x0a\xe9\xbc\x95\x69\xe9\x11\xa4\x07\xa6\xc5\xd0\x58\x58\x1b\x21\xb3\x1d\x41\x4a\x0f\xa5\x16\x68\x83\x6b\x8f\x76\xed\x9a\x38\xbe\x5d\xdf\xc1\x15\x3a\x87\x16\xbe\xa1\x46\xcb\x14\xdc\x96\xa9\x92\x1c\xae\x24\x47\xed\x10\x98\x83\x82\x56\xdc\x12\x05\xa4\x01\x8e\x0e\x7e\xa5\x50\xe6\x75\x28\xf0\xd5\x94\x5a\x30\x2f\x8d\x1e\x00\x4a\x8a\x1c\x36\x68\x9d\x34\x1a\x7e\x69\x5c\xd5\x80\x03\x30\x96\x40\x7a\xcc\x53\x02\x16\x4c\x41\xe7\xfa\xc0\xf4\x0e\x14\xf3\xfb\xa3\x3f\x41\xc8\x3e\x6f\x01\x52\x07\x37\x4b\x53\x20\xf8\x25\xf3\x94\xf5\x56\x2a\x05\x29\x42\xe9\x30\x2b\xd5\x80\xd0\xd2\xd2\xc3\xfd\x74\x71\x79\x73\xb7\x80\xe4\xfa\x01\xee\x93\xd9\x2c\xb9\x5e\x3c\x9c\xc3\x56\xfa\xa5\x29\x3d\xe0\x06\x2b\x28\xb9\x2e\x94\x44\x01\x5b\x66\x2d\xd3\x7e\x07\x26\x23\x84\xdf\x26\xb3\xf1\x65\x72\xbd\x48\x3e\x4f\xaf\xa6\x8b\x07\x30\x16\xbe\x4e\x17\xd7\x93\xf9\x1c\xbe\xde\xcc\x20\x81\xdb\x64\xb6\x98\x8e\xef\xae\x92\x19\xdc\xde\xcd\x6e\x6f\xe6\x93\x18\xe6\x48\x51\x21\x9d\x7f\x9b\xf3\x2c\xdc\x9e\x45\x10\xe8\x99\x54\xae\x61\xe2\xc1\x94\xe0\x96\xa6\x54\x02\x96\x6c\x83\x60\x91\xa3\xdc\xa0\x00\x06\xdc\x14\xbb\x9f\xbe\x54\xc2\x62\xca\xe8\x3c\xe4\xfc\x62\x41\xc2\x34\x03\x6d\xfc\x00\x1c\x22\xfc\x63\xe9\x7d\x31\x1a\x0e\xb7\xdb\x6d\x9c\xeb\x32\x36\x36\x1f\xaa\x0a\xce\x0d\xff\x19\x47\x84\x59\x58\x74\x9e\x79\x5c\x58\xc6\xd1\x82\x29\x7d\x51\x7a\x07\xae\xcc\x32\xc9\x25\x6a\x0f\x52\x67\xc6\xae\x43\xa5\x80\x37\xc0\x2d\x32\x8f\xc0\x40\x19\xce\x14\xe0\x23\xf2\x32\xec\x55\x4c\x87\x72\xb5\x4c\x3b\xc6\xc3\x6a\x66\xcd\x9a\x72\x2d\x9d\xa7\x1f\xce\xe1\x3a\x55\x28\x20\x47\x8d\x4e\x3a\x48\x95\xe1\xab\x38\xfa\x1e\x75\x0e\x82\xa1\x3a\x09\x19\xd6\x46\xa1\x36\xb6\xd8\xb5\x08\x69\x29\x95\x90\x3a\x8f\xa3\x4e\x63\x3d\x02\x5d\x2a\x35\x88\x02\x84\x32\x66\x55\x16\x09\xe7\xa6\x0c\xb1\xff\x89\xdc\x57\x60\xae\x40\x2e\x33\x2a\x0e\xd6\xee\x7a\x13\xb6\x5a\xbf\x26\x25\xfb\x38\xea\x1c\xc1\x8c\x20\x2b\x75\x48\xa7\xc7\x84\xb0\x03\x10\x69\xff\x7b\xd4\xe9\x6c\x98\x25\x2c\xb8\x00\x6f\x2e\xf1\x31\x6c\xf6\xcf\xa3\x4e\x47\x66\xd0\xf3\x4b\xe9\xe2\x06\xf8\x77\xc6\xf9\x1f\x70\x71\x71\x11\x9a\x3a\x93\x1a\x45\x1f\x08\xa2\xf3\x9c\x59\xb5\xd3\x49\x99\x62\x9a\xe3\x08\xba\x1f\x1f\xbb\xf0\x1e\x44\x1a\xe7\xe8\x3f\x57\xab\x95\xb3\xd8\x9b\xb9\xb7\x52\xe7\xbd\x4f\xbf\xf6\x07\xe1\x94\x36\xe1\x0c\xd4\xe6\xd7\xa6\x35\xae\xf6\xb9\x11\x61\xbb\x8e\xb9\xb2\x1a\x1b\x51\x1b\xd5\x56\xce\x1b\xcb\x72\x1c\xc1\xf7\x27\xfa\x7e\xa2\xac\x9e\xa2\xce\xd3\x11\xcb\xf3\xca\xe8\x05\x96\x6b\x08\x40\xed\x6d\x5b\xe7\xb9\xa4\x4e\x3d\xbc\x80\x80\xf7\xda\x25\xcc\x9b\x50\x4e\x2e\x61\x85\xbb\xb7\x6f\x82\x36\xa4\x78\x6c\x37\x56\xb8\xeb\x9f\x47\x2f\x5e\x51\x5c\x07\xfd\xbb\x14\x8f\x3f\x7b\x5f\x27\x67\x8e\x78\x9d\x93\xd5\x3e\xde\x7e\xff\x84\x47\x8b\xae\x54\x9e\xca\x5d\xea\x8d\x59\x91\x70\x2d\x89\x1f\xa5\x02\x25\xa6\xa0\xdb\x72\x95\x72\xa4\x88\x1a\xa4\x47\xcb\x48\x3a\xcd\x06\x2d\xbd\x1a\x60\xd1\x97\x56\xbb\x96\xc6\x4c\x6a\xa6\x1a\xe0\x9a\x75\x6f\x19\xaf\x7a\xa6\x5a\x3f\xe0\x92\xfb\xc7\xc0\x62\xc8\xee\x07\x52\x02\x05\xd4\x5d\xcf\x65\x4f\x85\x1a\x0a\x83\x5c\x4f\x33\xf0\x8f\xa1\x6f\xa9\xf9\x33\xb4\x1f\x8c\x56\xbb\x41\x70\x6f\x91\xcb\x22\x68\x49\x7d\xf1\xf5\x99\x25\x73\xba\xeb\xab\xc4\x0a\x53\x94\xf4\x94\x88\xb8\xf5\x73\xd4\x83\x14\x68\xec\x4d\x88\xb5\x26\x91\x20\x12\x0f\x64\x0b\x85\x91\xda\x0f\x60\x8b\xa0\x11\x05\xe9\x94\x40\x51\x72\x1f\xfc\x77\x37\x4c\x95\xd8\xad\xb4\x88\x14\x3d\x1c\x35\x25\x3d\x5c\x07\x5a\x35\x08\x7c\xae\xcd\x26\xbc\xc8\x29\xe3\x2b\xa8\xf5\xc1\x58\x99\x4b\x1d\xbd\x18\x17\x01\xd7\x91\xd5\x35\x47\x2b\x9f\x99\x82\x0b\x48\x65\x3e\xd5\xfe\xa4\xd6\xaa\x1a\x69\x8e\xf6\xff\x88\xeb\x5e\x8f\x1d\xe9\x73\xef\xac\x3f\x80\x4f\xbf\xb6\x05\xec\x0d\x41\xc1\xdb\x60\xde\xbc\x0c\x15\x9d\xd6\xee\xf3\xc7\x82\x1b\x12\x9c\xf7\xc1\x6b\xec\xca\x94\xaa\xa7\xca\x33\xf0\x78\x2c\x3a\xe7\xaf\xe0\x1e\xe7\xd6\xe0\xd6\xd4\xc4\x4c\x88\x43\x50\xfa\x0c\xdf\x39\x73\x77\x0e\x05\xbc\x07\xfa\x92\x9a\x5c\x39\xc9\xbf\x31\xd7\x87\xbf\x41\x6d\x71\x6b\x25\xff\x21\x92\xea\x5e\xbf\x20\xb7\xb8\xa6\x6a\xa3\xab\xe3\x4c\x29\xb4\x5d\x07\x41\x17\x07\x75\xcb\x84\x4b\xc6\x75\xe1\x77\xcd\x7b\xe6\x99\xcd\xd1\xbb\xb7\xb3\x09\x38\x1f\x3e\x34\x32\x1f\xf8\xdb\x15\xd4\x29\xd0\x1d\xcf\x26\xc9\x62\xd2\xad\x9b\x65\x38\x84\x7b\x0c\xd3\x5e\xaa\x64\x2a\xd4\x0e\x04\x2a\xf4\x58\xc5\x65\x74\xe0\xb5\x95\xbd\x01\x8d\x6d\x34\x50\xe1\xa3\x74\x5e\xea\x1c\xaa\x26\xdb\xd2\xec\xd0\x36\xcc\x86\x00\x4b\xa2\xe7\xf4\xa1\xf5\x86\xa6\x26\x8b\xa4\x9d\xf4\xc6\x05\x49\x61\x4a\xb6\x53\x56\x26\xad\xf3\x50\x28\xc6\x31\xf4\x58\x1b\xcc\xcb\x45\x71\xd0\x68\xb3\x20\x33\x01\x68\xff\x88\x33\x45\x43\x00\xb9\x77\xd0\x6b\x30\xfa\x51\xa7\x63\x1b\xeb\x03\xec\xf3\xbd\xec\x39\x8f\xc5\xa1\xe8\xd1\xf0\x84\x1b\xa4\x67\x22\x28\x5e\xf5\xe0\x93\xaf\x7f\xfd\x56\x4f\x18\xe8\xe2\xa8\x43\xe7\x0e\xb4\x4b\x99\x7c\xaf\x5d\x24\x06\xa2\xa2\x85\x97\xd6\x1e\xa8\x0d\xc8\x8c\x84\xe1\xcf\xd2\x79\xe2\xd4\x12\x3d\xb5\x22\xbe\xae\x79\x6f\x48\x5e\xfd\x12\x57\x13\x6b\x61\x3c\x6a\x2f\x99\x52\x3b\xba\x87\xad\xa5\x51\x8d\x86\xb3\x01\x38\x49\x56\x41\xa6\x82\xa9\xd4\x5c\x95\xa2\x2a\x83\x50\xfc\x35\x9e\x0b\x31\x1f\xcf\x78\x6b\x74\x8e\xe5\x18\x53\x25\x65\xf2\xb1\x9e\x92\x35\x74\x2b\x21\xef\xf5\xbb\x2f\xe9\xa5\x32\x79\xdc\x14\x19\x3d\x45\x89\x10\x16\x9d\xeb\xf5\x4f\x25\xf4\x7e\x89\x9a\xc8\x07\x8d\x5b\x68\xc7\x2f\xc6\x39\x8d\xa3\x62\x00\x4c\x08\xd2\xc3\x93\x51\x29\xea\x74\xdc\x56\x7a\xbe\x84\xe0\xc9\x14\xfb\x5e\xec\xd7\xf5\xcf\x99\x43\x78\x37\xf9\xf7\x62\x7c\xf3\x65\x32\xbe\xb9\x7d\x78\x37\x82\xa3\xb5\xf9\xf4\x3f\x93\xd3\xb5\xcb\x64\x7e\xd9\xae\x7d\x4e\xae\x92\xeb\xf1\xe4\xdd\x28\xcc\x24\xcf\x24\xe9\x4d\x93\x16\x05\xe1\x3c\xe3\xab\xb8\x40\x5c\xf5\x3e\x1e\x6b\xc3\x3e\xe9\x4e\x27\xb5\xc8\x56\xe7\xfb\x00\xab\xa6\xad\x7d\x34\xda\x0d\x17\xf0\x22\x81\xe7\x2f\x47\x33\xae\xed\x7b\xcd\x8b\xb0\x1f\xc1\x82\x7c\xbc\x1d\xc7\xd9\x5f\x0e\x24\xf4\x13\xe3\xab\x11\x38\xa6\x68\xf2\x97\xff\xa5\x7f\x6c\x59\xe6\xd0\x0f\x00\xb5\x30\x5b\x52\xc3\x16\xb5\xda\xa9\x71\x0f\x28\xfb\xd4\xaf\xa4\xf8\x26\xeb\xf5\x5b\x63\x02\xfb\xd1\xf4\xec\x39\x53\xd4\x02\x2e\x1a\xf4\xf7\xe1\xe4\xdb\x44\x9d\xd5\x4c\x9d\x38\xf8\xe5\x64\xb2\x0d\xfb\x6b\x5c\x1b\xbb\xab\xdf\xb5\x83\xfc\x5e\x67\x35\xb9\xba\x6a\xeb\x89\x3e\xa8\xc8\xda\x85\x2f\x93\xab\xc9\xb7\x64\x31\x39\xb2\x9a\x2f\x92\xc5\x74\x5c\x2d\xfd\xe5\xc2\xfb\xf4\xd3\x85\xd7\x9d\xcf\x17\x37\xb3\x49\x77\x54\x7f\x5d\xdd\x24\x5f\xba\x3f\x38\xac\xa7\xdf\xd7\xda\xd9\x9b\x7b\x63\xc5\xff\xd3\x01\x07\x93\x68\xc6\x9e\x1b\x44\x83\xdc\x73\x5f\x9e\xfc\xd1\x03\xa6\x1b\xa5\xce\xaa\x3f\xbb\x9d\x70\xfe\x59\x6d\x7e\x8a\x9e\xa2\xff\x05\x00\x00\xff\xff\x78\x74\x1c\xa8\x82\x11\x00\x00")
+var _prestate_tracer_legacyJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x57\xdb\x6e\x1b\x39\x12\x7d\x56\x7f\x45\x21\x2f\x92\x36\x4a\x2b\xf1\x00\xb3\x80\xbc\x5e\xa0\xa3\x28\xb1\x00\x8f\x6d\x48\xf2\x7a\xbd\x83\x79\x60\x93\xd5\x2d\x8e\x28\xb2\x41\xb2\x25\x6b\x03\xff\xfb\xa2\xd8\x17\x5d\xe2\x4b\x66\xdf\xd4\x64\xf1\x54\xd5\x61\xd5\x61\x69\x38\x84\xb1\x29\x76\x56\xe6\x4b\x0f\x67\x1f\x3f\xfd\x1d\x16\x4b\x84\xdc\x7c\x40\xbf\x44\x8b\xe5\x1a\x92\xd2\x2f\x8d\x75\xd1\x70\x08\x8b\xa5\x74\x90\x49\x85\x20\x1d\x14\xcc\x7a\x30\x19\xf8\x13\x7b\x25\x53\xcb\xec\x2e\x8e\x86\xc3\xea\xcc\xb3\xdb\x84\x90\x59\x44\x70\x26\xf3\x5b\x66\x71\x04\x3b\x53\x02\x67\x1a\x2c\x0a\xe9\xbc\x95\x69\xe9\x11\xa4\x07\xa6\xc5\xd0\x58\x58\x1b\x21\xb3\x1d\x41\x4a\x0f\xa5\x16\x68\x83\x6b\x8f\x76\xed\x9a\x38\xbe\x5d\xdf\xc1\x15\x3a\x87\x16\xbe\xa1\x46\xcb\x14\xdc\x96\xa9\x92\x1c\xae\x24\x47\xed\x10\x98\x83\x82\x56\xdc\x12\x05\xa4\x01\x8e\x0e\x7e\xa5\x50\xe6\x75\x28\xf0\xd5\x94\x5a\x30\x2f\x8d\x1e\x00\x4a\x8a\x1c\x36\x68\x9d\x34\x1a\x7e\x69\x5c\xd5\x80\x03\x30\x96\x40\x7a\xcc\x53\x02\x16\x4c\x41\xe7\xfa\xc0\xf4\x0e\x14\xf3\xfb\xa3\x3f\x41\xc8\x3e\x6f\x01\x52\x07\x37\x4b\x53\x20\xf8\x25\xf3\x94\xf5\x56\x2a\x05\x29\x42\xe9\x30\x2b\xd5\x80\xd0\xd2\xd2\xc3\xfd\x74\x71\x79\x73\xb7\x80\xe4\xfa\x01\xee\x93\xd9\x2c\xb9\x5e\x3c\x9c\xc3\x56\xfa\xa5\x29\x3d\xe0\x06\x2b\x28\xb9\x2e\x94\x44\x01\x5b\x66\x2d\xd3\x7e\x07\x26\x23\x84\xdf\x26\xb3\xf1\x65\x72\xbd\x48\x3e\x4f\xaf\xa6\x8b\x07\x30\x16\xbe\x4e\x17\xd7\x93\xf9\x1c\xbe\xde\xcc\x20\x81\xdb\x64\xb6\x98\x8e\xef\xae\x92\x19\xdc\xde\xcd\x6e\x6f\xe6\x93\x18\xe6\x48\x51\x21\x9d\x7f\x9b\xf3\x2c\xdc\x9e\x45\x10\xe8\x99\x54\xae\x61\xe2\xc1\x94\xe0\x96\xa6\x54\x02\x96\x6c\x83\x60\x91\xa3\xdc\xa0\x00\x06\xdc\x14\xbb\x9f\xbe\x54\xc2\x62\xca\xe8\x3c\xe4\xfc\x62\x41\xc2\x34\x03\x6d\xfc\x00\x1c\x22\xfc\x63\xe9\x7d\x31\x1a\x0e\xb7\xdb\x6d\x9c\xeb\x32\x36\x36\x1f\xaa\x0a\xce\x0d\xff\x19\x47\x84\x59\x58\x74\x9e\x79\x5c\x58\xc6\xd1\x82\x29\x7d\x51\x7a\x07\xae\xcc\x32\xc9\x25\x6a\x0f\x52\x67\xc6\xae\x43\xa5\x80\x37\xc0\x2d\x32\x8f\xc0\x40\x19\xce\x14\xe0\x23\xf2\x32\xec\x55\x4c\x87\x72\xb5\x4c\x3b\xc6\xc3\x6a\x66\xcd\x9a\x72\x2d\x9d\xa7\x1f\xce\xe1\x3a\x55\x28\x20\x47\x8d\x4e\x3a\x48\x95\xe1\xab\x38\xfa\x1e\x75\x0e\x82\xa1\x3a\x09\x19\xd6\x46\xa1\x36\xb6\xd8\xb5\x08\x69\x29\x95\x90\x3a\x8f\xa3\x4e\x63\x3d\x02\x5d\x2a\x35\x88\x02\x84\x32\x66\x55\x16\x09\xe7\xa6\x0c\xb1\xff\x89\xdc\x57\x60\xae\x40\x2e\x33\x2a\x0e\xd6\xee\x7a\x13\xb6\x5a\xbf\x26\x25\xfb\x38\xea\x1c\xc1\x8c\x20\x2b\x75\x48\xa7\xc7\x84\xb0\x03\x10\x69\xff\x7b\xd4\xe9\x6c\x98\x25\x2c\xb8\x00\x6f\x2e\xf1\x31\x6c\xf6\xcf\xa3\x4e\x47\x66\xd0\xf3\x4b\xe9\xe2\x06\xf8\x77\xc6\xf9\x1f\x70\x71\x71\x11\x9a\x3a\x93\x1a\x45\x1f\x08\xa2\xf3\x9c\x59\xb5\xd3\x49\x99\x62\x9a\xe3\x08\xba\x1f\x1f\xbb\xf0\x1e\x44\x1a\xe7\xe8\x3f\x57\xab\x95\xb3\xd8\x9b\xb9\xb7\x52\xe7\xbd\x4f\xbf\xf6\x07\xe1\x94\x36\xe1\x0c\xd4\xe6\xd7\xa6\x35\xae\xf6\xb9\x11\x61\xbb\x8e\xb9\xb2\x1a\x1b\x51\x1b\xd5\x56\xce\x1b\xcb\x72\x1c\xc1\xf7\x27\xfa\x7e\xa2\xac\x9e\xa2\xce\xd3\x11\xcb\xf3\xca\xe8\x05\x96\x6b\x08\x40\xed\x6d\x5b\xe7\xb9\xa4\x4e\x3d\xbc\x80\x80\xf7\xda\x25\xcc\x9b\x50\x4e\x2e\x61\x85\xbb\xb7\x6f\x82\x36\xa4\x78\x6c\x37\x56\xb8\xeb\x9f\x47\x2f\x5e\x51\x5c\x07\xfd\xbb\x14\x8f\x3f\x7b\x5f\x27\x67\x8e\x78\x9d\x93\xd5\x3e\xde\x7e\xff\x84\x47\x8b\xae\x54\x9e\xca\x5d\xea\x8d\x59\x91\x70\x2d\x89\x1f\xa5\x02\x25\xa6\xa0\xdb\x72\x95\x72\xa4\x88\x1a\xa4\x47\xcb\x48\x3a\xcd\x06\x2d\xbd\x1a\x60\xd1\x97\x56\xbb\x96\xc6\x4c\x6a\xa6\x1a\xe0\x9a\x75\x6f\x19\xaf\x7a\xa6\x5a\x3f\xe0\x92\xfb\xc7\xc0\x62\xc8\xee\x07\x52\x02\x05\xd4\x5d\xcf\x65\x4f\x85\x1a\x0a\x83\x5c\x4f\x33\xf0\x8f\xa1\x6f\xa9\xf9\x33\xb4\x1f\x8c\x56\xbb\x41\x70\x6f\x91\xcb\x22\x68\x49\x7d\xf1\xf5\x99\x25\x73\xba\xeb\xab\xc4\x0a\x53\x94\xf4\x94\x88\xb8\xf5\x73\xd4\x83\x14\x68\xec\x4d\x88\xb5\x26\x91\x20\x12\x0f\x64\x0b\x85\x91\xda\x0f\x60\x8b\xa0\x11\x05\xe9\x94\x40\x51\x72\x1f\xfc\x77\x37\x4c\x95\xd8\xad\xb4\x88\x14\x3d\x1c\x35\x25\x3d\x5c\x07\x5a\x35\x08\x7c\xae\xcd\x26\xbc\xc8\x29\xe3\x2b\xa8\xf5\xc1\x58\x99\x4b\x1d\xbd\x18\x17\x01\xd7\x91\xd5\x35\x47\x2b\x9f\x99\x82\x0b\x48\x65\x3e\xd5\xfe\xa4\xd6\xaa\x1a\x69\x8e\xf6\xff\x88\xeb\x5e\x8f\x1d\xe9\x73\xef\xac\x3f\x80\x4f\xbf\xb6\x05\xec\x0d\x41\xc1\xdb\x60\xde\xbc\x0c\x15\x9d\xd6\xee\xf3\xc7\x82\x1b\x12\x9c\xf7\xc1\x6b\xec\xca\x94\xaa\xa7\xca\x33\xf0\x78\x2c\x3a\xe7\xaf\xe0\x1e\xe7\xd6\xe0\xd6\xd4\xc4\x4c\x88\x43\x50\xfa\x0c\xdf\x39\x73\x77\x0e\x05\xbc\x07\xfa\x92\x9a\x5c\x39\xc9\xbf\x31\xd7\x87\xbf\x41\x6d\x71\x6b\x25\xff\x21\x92\xea\x5e\xbf\x20\xb7\xb8\xa6\x6a\xa3\xab\xe3\x4c\x29\xb4\x5d\x07\x41\x17\x07\x75\xcb\x84\x4b\xc6\x75\xe1\x77\xcd\x7b\xe6\x99\xcd\xd1\xbb\xb7\xb3\x09\x38\x1f\x3e\x34\x32\x1f\xf8\xdb\x15\xd4\x29\xd0\x1d\xcf\x26\xc9\x62\xd2\xad\x9b\x65\x38\x84\x7b\x0c\xd3\x5e\xaa\x64\x2a\xd4\x0e\x04\x2a\xf4\x58\xc5\x65\x74\xe0\xb5\x95\xbd\x01\x8d\x6d\x34\x50\xe1\xa3\x74\x5e\xea\x1c\xaa\x26\xdb\xd2\xec\xd0\x36\xcc\x86\x00\x4b\xa2\xe7\xf4\xa1\xf5\x86\xa6\x26\x8b\xa4\x9d\xf4\xc6\x05\x49\x61\x4a\xb6\x53\x56\x26\xad\xf3\x50\x28\xc6\x31\xf4\x58\x1b\xcc\xcb\x45\x71\xd0\x68\xb3\x20\x33\x01\x68\xff\x88\x33\x45\x43\x00\xb9\x77\xd0\x6b\x30\xfa\x51\xa7\x63\x1b\xeb\x03\xec\xf3\xbd\xec\x39\x8f\xc5\xa1\xe8\xd1\xf0\x84\x1b\xa4\x67\x22\x28\x5e\xf5\xe0\x93\xaf\x7f\xfd\x56\x4f\x18\xe8\xe2\xa8\x43\xe7\x0e\xb4\x4b\x99\x7c\xaf\x5d\x24\x06\xa2\xa2\x85\x97\xd6\x1e\xa8\x0d\xc8\x8c\x84\xe1\xcf\xd2\x79\xe2\xd4\x12\x3d\xb5\x22\xbe\xae\x79\x6f\x48\x5e\xfd\x12\x57\x13\x6b\x61\x3c\x6a\x2f\x99\x52\x3b\xba\x87\xad\xa5\x51\x8d\x86\xb3\x01\x38\x49\x56\x41\xa6\x82\xa9\xd4\x5c\x95\xa2\x2a\x83\x50\xfc\x35\x9e\x0b\x31\x1f\xcf\x78\x6b\x74\x8e\xe5\x18\x53\x25\x65\xf2\xb1\x9e\x92\x35\x74\x2b\x21\xef\xf5\xbb\x2f\xe9\xa5\x32\x79\xdc\x14\x19\x3d\x45\x89\x10\x16\x9d\xeb\xf5\x4f\x25\xf4\x7e\x89\x9a\xc8\x07\x8d\x5b\x68\xc7\x2f\xc6\x39\x8d\xa3\x62\x00\x4c\x08\xd2\xc3\x93\x51\x29\xea\x74\xdc\x56\x7a\xbe\x84\xe0\xc9\x14\xfb\x5e\xec\xd7\xf5\xcf\x99\x43\x78\x37\xf9\xf7\x62\x7c\xf3\x65\x32\xbe\xb9\x7d\x78\x37\x82\xa3\xb5\xf9\xf4\x3f\x93\xd3\xb5\xcb\x64\x7e\xd9\xae\x7d\x4e\xae\x92\xeb\xf1\xe4\xdd\x28\xcc\x24\xcf\x24\xe9\x4d\x93\x16\x05\xe1\x3c\xe3\xab\xb8\x40\x5c\xf5\x3e\x1e\x6b\xc3\x3e\xe9\x4e\x27\xb5\xc8\x56\xe7\xfb\x00\xab\xa6\xad\x7d\x34\xda\x0d\x17\xf0\x22\x81\xe7\x2f\x47\x33\xae\xed\x7b\xcd\x8b\xb0\x1f\xc1\x82\x7c\xbc\x1d\xc7\xd9\x5f\x0e\x24\xf4\x13\xe3\xab\x11\x38\xa6\x68\xf2\x97\xff\xa5\x7f\x6c\x59\xe6\xd0\x0f\x00\xb5\x30\x5b\x52\xc3\x16\xb5\xda\xa9\x71\x0f\x28\xfb\xd4\xaf\xa4\xf8\x26\xeb\xf5\x5b\x63\x02\xfb\xd1\xf4\xec\x39\x53\xd4\x02\x2e\x1a\xf4\xf7\xe1\xe4\xdb\x44\x9d\xd5\x4c\x9d\x38\xf8\xe5\x64\xb2\x0d\xfb\x6b\x5c\x1b\xbb\xab\xdf\xb5\x83\xfc\x5e\x67\x35\xb9\xba\x6a\xeb\x89\x3e\xa8\xc8\xda\x85\x2f\x93\xab\xc9\xb7\x64\x31\x39\xb2\x9a\x2f\x92\xc5\x74\x5c\x2d\xfd\xe5\xc2\xfb\xf4\xd3\x85\xd7\x9d\xcf\x17\x37\xb3\x49\x77\x54\x7f\x5d\xdd\x24\x5f\xba\x3f\x38\xac\xa7\xdf\xd7\xda\xd9\x9b\x7b\x63\xc5\xff\xd3\x01\x07\x93\x68\xc6\x9e\x1b\x44\x83\xdc\x73\x5f\x9e\xfc\xd1\x03\xa6\x1b\xa5\xce\xaa\x3f\xbb\x9d\x70\xfe\x59\x6d\x7e\x8a\x9e\xa2\xff\x05\x00\x00\xff\xff\x78\x74\x1c\xa8\x82\x11\x00\x00")
 
-func prestate_tracerJsBytes() ([]byte, error) {
+func prestate_tracer_legacyJsBytes() ([]byte, error) {
 	return bindataRead(
-		_prestate_tracerJs,
-		"prestate_tracer.js",
+		_prestate_tracer_legacyJs,
+		"prestate_tracer_legacy.js",
 	)
 }
 
-func prestate_tracerJs() (*asset, error) {
-	bytes, err := prestate_tracerJsBytes()
+func prestate_tracer_legacyJs() (*asset, error) {
+	bytes, err := prestate_tracer_legacyJsBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "prestate_tracer.js", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "prestate_tracer_legacy.js", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x52, 0x80, 0x86, 0xa9, 0xa9, 0x19, 0xc0, 0xc0, 0x23, 0x24, 0x59, 0xd8, 0xf6, 0xb0, 0x20, 0x47, 0x84, 0x3c, 0x55, 0x12, 0x85, 0x6, 0x28, 0x57, 0x4f, 0xf8, 0xdd, 0xc0, 0x71, 0xc0, 0xf6, 0x27}}
 	return a, nil
 }
@@ -389,17 +389,17 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"4byte_tracer.js":        _4byte_tracerJs,
-	"4byte_tracer_legacy.js": _4byte_tracer_legacyJs,
-	"bigram_tracer.js":       bigram_tracerJs,
-	"call_tracer_js.js":      call_tracer_jsJs,
-	"call_tracer_legacy.js":  call_tracer_legacyJs,
-	"evmdis_tracer.js":       evmdis_tracerJs,
-	"noop_tracer.js":         noop_tracerJs,
-	"opcount_tracer.js":      opcount_tracerJs,
-	"prestate_tracer.js":     prestate_tracerJs,
-	"trigram_tracer.js":      trigram_tracerJs,
-	"unigram_tracer.js":      unigram_tracerJs,
+	"4byte_tracer.js":           _4byte_tracerJs,
+	"4byte_tracer_legacy.js":    _4byte_tracer_legacyJs,
+	"bigram_tracer.js":          bigram_tracerJs,
+	"call_tracer_js.js":         call_tracer_jsJs,
+	"call_tracer_legacy.js":     call_tracer_legacyJs,
+	"evmdis_tracer.js":          evmdis_tracerJs,
+	"noop_tracer.js":            noop_tracerJs,
+	"opcount_tracer.js":         opcount_tracerJs,
+	"prestate_tracer_legacy.js": prestate_tracer_legacyJs,
+	"trigram_tracer.js":         trigram_tracerJs,
+	"unigram_tracer.js":         unigram_tracerJs,
 }
 
 // AssetDebug is true if the assets were built with the debug flag enabled.
@@ -448,17 +448,17 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
-	"4byte_tracer.js":        {_4byte_tracerJs, map[string]*bintree{}},
-	"4byte_tracer_legacy.js": {_4byte_tracer_legacyJs, map[string]*bintree{}},
-	"bigram_tracer.js":       {bigram_tracerJs, map[string]*bintree{}},
-	"call_tracer_js.js":      {call_tracer_jsJs, map[string]*bintree{}},
-	"call_tracer_legacy.js":  {call_tracer_legacyJs, map[string]*bintree{}},
-	"evmdis_tracer.js":       {evmdis_tracerJs, map[string]*bintree{}},
-	"noop_tracer.js":         {noop_tracerJs, map[string]*bintree{}},
-	"opcount_tracer.js":      {opcount_tracerJs, map[string]*bintree{}},
-	"prestate_tracer.js":     {prestate_tracerJs, map[string]*bintree{}},
-	"trigram_tracer.js":      {trigram_tracerJs, map[string]*bintree{}},
-	"unigram_tracer.js":      {unigram_tracerJs, map[string]*bintree{}},
+	"4byte_tracer.js":           {_4byte_tracerJs, map[string]*bintree{}},
+	"4byte_tracer_legacy.js":    {_4byte_tracer_legacyJs, map[string]*bintree{}},
+	"bigram_tracer.js":          {bigram_tracerJs, map[string]*bintree{}},
+	"call_tracer_js.js":         {call_tracer_jsJs, map[string]*bintree{}},
+	"call_tracer_legacy.js":     {call_tracer_legacyJs, map[string]*bintree{}},
+	"evmdis_tracer.js":          {evmdis_tracerJs, map[string]*bintree{}},
+	"noop_tracer.js":            {noop_tracerJs, map[string]*bintree{}},
+	"opcount_tracer.js":         {opcount_tracerJs, map[string]*bintree{}},
+	"prestate_tracer_legacy.js": {prestate_tracer_legacyJs, map[string]*bintree{}},
+	"trigram_tracer.js":         {trigram_tracerJs, map[string]*bintree{}},
+	"unigram_tracer.js":         {unigram_tracerJs, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
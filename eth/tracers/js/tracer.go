@@ -56,7 +56,9 @@ func init() {
 		name := camel(strings.TrimSuffix(file, ".js"))
 		assetTracers[name] = string(tracers.MustAsset(file))
 	}
-	tracers2.RegisterLookup(true, newJsTracer)
+	tracers2.RegisterLookup(true, func(code string, ctx *tracers2.Context, _ json.RawMessage) (tracers2.Tracer, error) {
+		return newJsTracer(code, ctx)
+	})
 }
 
 // makeSlice convert an unsafe memory pointer with the given type into a Go byte
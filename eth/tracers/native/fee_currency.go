@@ -0,0 +1,102 @@
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/common/hexutil"
+	"github.com/celo-org/celo-blockchain/core"
+	"github.com/celo-org/celo-blockchain/eth/tracers"
+)
+
+func init() {
+	register("feeCurrencyTracer", newFeeCurrencyTracer)
+}
+
+type exchangeRate struct {
+	Numerator   *hexutil.Big `json:"numerator"`
+	Denominator *hexutil.Big `json:"denominator"`
+}
+
+type feeCurrencyResult struct {
+	FeeCurrency         *common.Address `json:"feeCurrency"` // nil for CELO
+	ExchangeRate        *exchangeRate   `json:"exchangeRate,omitempty"`
+	Debited             *hexutil.Big    `json:"debited"`
+	Refund              *hexutil.Big    `json:"refund"`
+	Tip                 *hexutil.Big    `json:"tip"`
+	Coinbase            common.Address  `json:"coinbase"`
+	BaseFee             *hexutil.Big    `json:"baseFee"`
+	FeeHandler          common.Address  `json:"feeHandler"`
+	GatewayFee          *hexutil.Big    `json:"gatewayFee,omitempty"`
+	GatewayFeeRecipient *common.Address `json:"gatewayFeeRecipient,omitempty"`
+}
+
+// feeCurrencyTracer reports how the fees of a transaction were charged: the
+// amount debited from the sender, and how it was split between the refund,
+// the coinbase and the fee handler. The debit and credit are system calls
+// made outside of the traced EVM, so they are reported through
+// core.FeeTracer rather than the call frames.
+type feeCurrencyTracer struct {
+	noopTracer
+	result    *feeCurrencyResult
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+// newFeeCurrencyTracer returns a native go tracer which reports the fees
+// charged for a tx, and implements vm.EVMLogger and core.FeeTracer.
+func newFeeCurrencyTracer(ctx *tracers.Context, _ json.RawMessage) (tracers.Tracer, error) {
+	return &feeCurrencyTracer{}, nil
+}
+
+// CaptureFees implements the FeeTracer interface to record the fees charged.
+func (t *feeCurrencyTracer) CaptureFees(fees *core.TxFees) {
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+	res := &feeCurrencyResult{
+		FeeCurrency: fees.FeeCurrency,
+		Debited:     (*hexutil.Big)(fees.Debited),
+		Refund:      (*hexutil.Big)(fees.Refund),
+		Tip:         (*hexutil.Big)(fees.Tip),
+		Coinbase:    fees.Coinbase,
+		BaseFee:     (*hexutil.Big)(fees.BaseFee),
+		FeeHandler:  fees.FeeHandler,
+	}
+	if fees.ExchangeRate != nil {
+		res.ExchangeRate = &exchangeRate{
+			Numerator:   (*hexutil.Big)(fees.ExchangeRate.Numerator()),
+			Denominator: (*hexutil.Big)(fees.ExchangeRate.Denominator()),
+		}
+	}
+	if fees.GatewayFee != nil {
+		recipient := fees.GatewayFeeRecipient
+		res.GatewayFee = (*hexutil.Big)(fees.GatewayFee)
+		res.GatewayFeeRecipient = &recipient
+	}
+	t.result = res
+}
+
+// GetResult returns the json-encoded fees charged for the transaction, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *feeCurrencyTracer) GetResult() (json.RawMessage, error) {
+	if t.reason != nil {
+		return nil, t.reason
+	}
+	if t.result == nil {
+		return nil, errors.New("no fees charged for the transaction")
+	}
+	res, err := json.Marshal(t.result)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), nil
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *feeCurrencyTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
@@ -0,0 +1,282 @@
+package native
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/common/hexutil"
+	"github.com/celo-org/celo-blockchain/core/state"
+	"github.com/celo-org/celo-blockchain/core/vm"
+	"github.com/celo-org/celo-blockchain/eth/tracers"
+)
+
+func init() {
+	register("prestateTracer", newPrestateTracer)
+}
+
+// transferPrecompile is the address of the precompile moving CELO on behalf
+// of the GoldToken contract.
+var transferPrecompile = common.BytesToAddress([]byte{0xfd})
+
+type accountMap map[common.Address]*account
+
+type account struct {
+	Balance *hexutil.Big                `json:"balance,omitempty"`
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Nonce   uint64                      `json:"nonce,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+type prestateTracerConfig struct {
+	DiffMode bool `json:"diffMode"` // If true, this tracer will return state modifications
+}
+
+// prestateTracer reports the accounts and storage slots touched by a
+// transaction, as they were before it. In diff mode it reports the values of
+// the modified ones before and after the transaction instead.
+//
+// The pre and post values are read from the state before and after the whole
+// transaction, so changes made outside of the traced EVM, like fee currency
+// debits and credits, are included.
+type prestateTracer struct {
+	noopTracer
+	env       *vm.EVM
+	config    prestateTracerConfig
+	pre       *state.StateDB
+	touched   map[common.Address]map[common.Hash]struct{}
+	result    json.RawMessage
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+// newPrestateTracer returns a native go tracer which reports the state touched
+// by a tx, and implements vm.EVMLogger and tracers.StateTracer.
+func newPrestateTracer(ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
+	var config prestateTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	return &prestateTracer{
+		config:  config,
+		touched: make(map[common.Address]map[common.Hash]struct{}),
+	}, nil
+}
+
+// CaptureTxStart implements the StateTracer interface to keep the state from
+// before the transaction.
+func (t *prestateTracer) CaptureTxStart(pre *state.StateDB) {
+	t.pre = pre
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *prestateTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.lookupAccount(from)
+	t.lookupAccount(to)
+	t.lookupAccount(env.Context.Coinbase)
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *prestateTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *prestateTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	// Skip if tracing was interrupted
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		t.env.Cancel()
+		return
+	}
+	if err != nil {
+		return
+	}
+	stack := scope.Stack.Data()
+	if len(stack) == 0 {
+		return
+	}
+	top := stack[len(stack)-1]
+	switch op {
+	case vm.SLOAD, vm.SSTORE:
+		t.lookupStorage(scope.Contract.Address(), common.Hash(top.Bytes32()))
+	case vm.EXTCODECOPY, vm.EXTCODEHASH, vm.EXTCODESIZE, vm.BALANCE, vm.SELFDESTRUCT:
+		t.lookupAccount(common.Address(top.Bytes20()))
+	}
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *prestateTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// CELO transfers through the precompile move the balances of the
+	// accounts in its input rather than its own
+	if to == transferPrecompile && len(input) >= 64 {
+		t.lookupAccount(common.BytesToAddress(input[0:32]))
+		t.lookupAccount(common.BytesToAddress(input[32:64]))
+		return
+	}
+	if !t.isPrecompiled(to) {
+		t.lookupAccount(to)
+	}
+}
+
+// CaptureTxEnd implements the StateTracer interface to assemble the result from
+// the state before and after the transaction.
+func (t *prestateTracer) CaptureTxEnd(post *state.StateDB) {
+	if t.pre == nil {
+		return
+	}
+	// System calls run outside of the traced EVM: gather what they read from
+	// the access list and what they wrote from the modified state
+	for _, tuple := range post.AccessList() {
+		if t.isPrecompiled(tuple.Address) {
+			continue
+		}
+		t.lookupAccount(tuple.Address)
+		for _, slot := range tuple.StorageKeys {
+			t.lookupStorage(tuple.Address, slot)
+		}
+	}
+	for addr, slots := range post.Dirties() {
+		t.lookupAccount(addr)
+		for _, slot := range slots {
+			t.lookupStorage(addr, slot)
+		}
+	}
+	pre := make(accountMap, len(t.touched))
+	for addr, slots := range t.touched {
+		pre[addr] = readAccount(t.pre, addr, slots)
+	}
+	var (
+		res []byte
+		err error
+	)
+	if t.config.DiffMode {
+		res, err = json.Marshal(struct {
+			Pre  accountMap `json:"pre"`
+			Post accountMap `json:"post"`
+		}{pre, t.diff(pre, post)})
+	} else {
+		res, err = json.Marshal(pre)
+	}
+	if err != nil {
+		t.reason = err
+		return
+	}
+	t.result = json.RawMessage(res)
+}
+
+// diff returns the values of the modified accounts after the transaction, and
+// removes the unmodified ones from pre.
+func (t *prestateTracer) diff(pre accountMap, post *state.StateDB) accountMap {
+	diff := make(accountMap)
+	for addr, preAccount := range pre {
+		// Accounts created by the transaction had no state before it
+		if !t.pre.Exist(addr) {
+			delete(pre, addr)
+		}
+		// Destructed accounts are left out of the post state
+		if post.HasSuicided(addr) {
+			continue
+		}
+		var (
+			modified    = false
+			postAccount = &account{Storage: make(map[common.Hash]common.Hash)}
+			newBalance  = post.GetBalance(addr)
+			newNonce    = post.GetNonce(addr)
+			newCode     = post.GetCode(addr)
+		)
+		if newBalance.Cmp(preAccount.Balance.ToInt()) != 0 {
+			modified = true
+			postAccount.Balance = (*hexutil.Big)(newBalance)
+		}
+		if newNonce != preAccount.Nonce {
+			modified = true
+			postAccount.Nonce = newNonce
+		}
+		if !bytes.Equal(newCode, preAccount.Code) {
+			modified = true
+			postAccount.Code = newCode
+		}
+		for slot, value := range preAccount.Storage {
+			newValue := post.GetState(addr, slot)
+			if newValue == value {
+				delete(preAccount.Storage, slot)
+				continue
+			}
+			modified = true
+			if newValue != (common.Hash{}) {
+				postAccount.Storage[slot] = newValue
+			}
+		}
+		if modified {
+			diff[addr] = postAccount
+		} else {
+			delete(pre, addr)
+		}
+	}
+	return diff
+}
+
+// GetResult returns the json-encoded state touched by the transaction, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *prestateTracer) GetResult() (json.RawMessage, error) {
+	if t.reason != nil {
+		return nil, t.reason
+	}
+	if t.result == nil {
+		return nil, errors.New("state before and after the transaction not captured")
+	}
+	return t.result, nil
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *prestateTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// lookupAccount marks the account as touched.
+func (t *prestateTracer) lookupAccount(addr common.Address) {
+	if _, ok := t.touched[addr]; !ok {
+		t.touched[addr] = make(map[common.Hash]struct{})
+	}
+}
+
+// lookupStorage marks the storage slot of the account as touched.
+func (t *prestateTracer) lookupStorage(addr common.Address, slot common.Hash) {
+	t.lookupAccount(addr)
+	t.touched[addr][slot] = struct{}{}
+}
+
+// isPrecompiled returns whether addr is a precompile active in the traced block.
+func (t *prestateTracer) isPrecompiled(addr common.Address) bool {
+	if t.env == nil {
+		return false
+	}
+	rules := t.env.ChainConfig().Rules(t.env.Context.BlockNumber)
+	for _, p := range vm.ActivePrecompiles(rules) {
+		if p == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// readAccount reads the account and the given storage slots from the state.
+func readAccount(statedb *state.StateDB, addr common.Address, slots map[common.Hash]struct{}) *account {
+	acc := &account{
+		Balance: (*hexutil.Big)(statedb.GetBalance(addr)),
+		Nonce:   statedb.GetNonce(addr),
+		Code:    statedb.GetCode(addr),
+		Storage: make(map[common.Hash]common.Hash, len(slots)),
+	}
+	for slot := range slots {
+		acc.Storage[slot] = statedb.GetState(addr, slot)
+	}
+	return acc
+}
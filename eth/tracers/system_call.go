@@ -0,0 +1,222 @@
+package tracers
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/consensus"
+	"github.com/celo-org/celo-blockchain/core"
+	"github.com/celo-org/celo-blockchain/core/state"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/core/vm"
+	"github.com/celo-org/celo-blockchain/core/vm/vmcontext"
+	"github.com/celo-org/celo-blockchain/rpc"
+)
+
+const (
+	// systemCallRandomness is the phase of the random commitment made before
+	// the transactions of a block.
+	systemCallRandomness = "randomness"
+
+	// systemCallFinalize is the phase of the system calls made by the consensus
+	// engine after the transactions of a block, like gas price minimum updates
+	// and epoch rewards.
+	systemCallFinalize = "finalize"
+)
+
+// systemCall identifies a system call traced as a pseudo-transaction.
+type systemCall struct {
+	Phase string         `json:"phase"` // Block processing phase the call was made in
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+}
+
+// systemCallRunner is an EVMRunner tracing each state changing call it makes
+// as a pseudo-transaction. Read-only calls are not traced.
+type systemCallRunner struct {
+	api          *API
+	ctx          context.Context
+	phase        string
+	txctx        *Context
+	vmctx        vm.BlockContext
+	statedb      *state.StateDB
+	config       *TraceConfig
+	results      []*txTraceResult
+	dontMeterGas bool
+}
+
+func (api *API) newSystemCallRunner(ctx context.Context, phase string, block *types.Block, txIndex int, statedb *state.StateDB, config *TraceConfig) *systemCallRunner {
+	return &systemCallRunner{
+		api:     api,
+		ctx:     ctx,
+		phase:   phase,
+		txctx:   &Context{BlockHash: block.Hash(), TxIndex: txIndex},
+		vmctx:   core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil),
+		statedb: statedb,
+		config:  config,
+	}
+}
+
+func (r *systemCallRunner) newEVM(from common.Address, tracer vm.EVMLogger) *vm.EVM {
+	var cfg vm.Config
+	if tracer != nil {
+		cfg = vm.Config{Debug: true, Tracer: tracer}
+	}
+	txContext := vm.TxContext{
+		Origin:   from,
+		GasPrice: common.Big0,
+	}
+	return vm.NewEVM(r.vmctx, txContext, r.statedb, r.api.backend.ChainConfig(), cfg)
+}
+
+// call makes a traced call and records its trace.
+func (r *systemCallRunner) call(from, to common.Address, input []byte, gas uint64, value *big.Int) ([]byte, error) {
+	var (
+		ret      []byte
+		vmErr    error
+		executed bool
+	)
+	res, err := r.api.trace(r.ctx, r.txctx, r.statedb, r.config, func(tracer vm.EVMLogger) (*core.ExecutionResult, error) {
+		var leftOverGas uint64
+		evm := r.newEVM(from, tracer)
+		if r.dontMeterGas {
+			evm.StopGasMetering()
+		}
+		ret, leftOverGas, vmErr = evm.Call(vm.AccountRef(from), to, input, gas, value)
+		executed = true
+		return &core.ExecutionResult{UsedGas: gas - leftOverGas, Err: vmErr, ReturnData: ret}, nil
+	})
+	// The block must be processed the same way whether its calls can be traced
+	// or not
+	if !executed {
+		evm := r.newEVM(from, nil)
+		if r.dontMeterGas {
+			evm.StopGasMetering()
+		}
+		ret, _, vmErr = evm.Call(vm.AccountRef(from), to, input, gas, value)
+	}
+	result := &txTraceResult{
+		SystemCall: &systemCall{Phase: r.phase, From: from, To: to},
+		Result:     res,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	r.results = append(r.results, result)
+	return ret, vmErr
+}
+
+// Execute implements vm.EVMRunner.Execute
+func (r *systemCallRunner) Execute(recipient common.Address, input []byte, gas uint64, value *big.Int) ([]byte, error) {
+	return r.call(vmcontext.VMAddress, recipient, input, gas, value)
+}
+
+// ExecuteFrom implements vm.EVMRunner.ExecuteFrom
+func (r *systemCallRunner) ExecuteFrom(sender, recipient common.Address, input []byte, gas uint64, value *big.Int) ([]byte, error) {
+	return r.call(sender, recipient, input, gas, value)
+}
+
+// ExecuteAndDiscardChanges implements vm.EVMRunner.ExecuteAndDiscardChanges
+func (r *systemCallRunner) ExecuteAndDiscardChanges(recipient common.Address, input []byte, gas uint64, value *big.Int) ([]byte, error) {
+	evm := r.newEVM(vmcontext.VMAddress, nil)
+	if r.dontMeterGas {
+		evm.StopGasMetering()
+	}
+	snapshot := r.statedb.Snapshot()
+	ret, _, err := evm.Call(vm.AccountRef(vmcontext.VMAddress), recipient, input, gas, value)
+	r.statedb.RevertToSnapshot(snapshot)
+	return ret, err
+}
+
+// Query implements vm.EVMRunner.Query
+func (r *systemCallRunner) Query(recipient common.Address, input []byte, gas uint64) ([]byte, error) {
+	evm := r.newEVM(vmcontext.VMAddress, nil)
+	if r.dontMeterGas {
+		evm.StopGasMetering()
+	}
+	ret, _, err := evm.StaticCall(vm.AccountRef(vmcontext.VMAddress), recipient, input, gas)
+	return ret, err
+}
+
+// StopGasMetering implements vm.EVMRunner.StopGasMetering
+func (r *systemCallRunner) StopGasMetering() {
+	r.dontMeterGas = true
+}
+
+// StartGasMetering implements vm.EVMRunner.StartGasMetering
+func (r *systemCallRunner) StartGasMetering() {
+	r.dontMeterGas = false
+}
+
+// GetStateDB implements vm.EVMRunner.GetStateDB
+func (r *systemCallRunner) GetStateDB() vm.StateDB {
+	return r.statedb
+}
+
+// systemCallChain is the chain handed to the consensus engine to finalize a
+// traced block, making its system calls through a systemCallRunner. It is
+// read-only, so that the engine doesn't persist anything while tracing.
+type systemCallChain struct {
+	*chainContext
+	runner *systemCallRunner
+}
+
+// The consensus engine only uses the runner of chains it can create runners
+// with
+var (
+	_ consensus.ChainContext  = (*systemCallChain)(nil)
+	_ consensus.ReadOnlyChain = (*systemCallChain)(nil)
+)
+
+// ReadOnly implements consensus.ReadOnlyChain.ReadOnly
+func (c *systemCallChain) ReadOnly() bool {
+	return true
+}
+
+// CurrentHeader implements consensus.ChainHeaderReader.CurrentHeader
+func (c *systemCallChain) CurrentHeader() *types.Header {
+	header, _ := c.api.backend.HeaderByNumber(c.ctx, rpc.LatestBlockNumber)
+	return header
+}
+
+// GetHeaderByHash implements consensus.ChainHeaderReader.GetHeaderByHash
+func (c *systemCallChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	header, _ := c.api.backend.HeaderByHash(c.ctx, hash)
+	return header
+}
+
+// NewEVMRunner implements consensus.ChainContext.NewEVMRunner
+func (c *systemCallChain) NewEVMRunner(header *types.Header, state vm.StateDB) vm.EVMRunner {
+	return c.runner
+}
+
+// NewEVMRunnerForCurrentBlock implements consensus.ChainContext.NewEVMRunnerForCurrentBlock
+func (c *systemCallChain) NewEVMRunnerForCurrentBlock() (vm.EVMRunner, error) {
+	return nil, errors.New("not supported while tracing system calls")
+}
+
+// traceRandomness applies the random commitment of the block on top of its
+// parent state, and returns the traces of the system calls it made.
+func (api *API) traceRandomness(ctx context.Context, block *types.Block, statedb *state.StateDB, config *TraceConfig) ([]*txTraceResult, error) {
+	runner := api.newSystemCallRunner(ctx, systemCallRandomness, block, 0, statedb, config)
+	var vmRunner vm.EVMRunner = runner
+	if err := core.ApplyBlockRandomnessTx(block, &vmRunner, statedb, api.chainContext(ctx)); err != nil {
+		return nil, err
+	}
+	return runner.results, nil
+}
+
+// traceFinalize finalizes the block on top of the state left by its
+// transactions, and returns the traces of the system calls it made.
+func (api *API) traceFinalize(ctx context.Context, block *types.Block, statedb *state.StateDB, config *TraceConfig) []*txTraceResult {
+	txs := block.Transactions()
+	runner := api.newSystemCallRunner(ctx, systemCallFinalize, block, len(txs), statedb, config)
+	chain := &systemCallChain{
+		chainContext: &chainContext{api: api, ctx: ctx},
+		runner:       runner,
+	}
+	api.backend.Engine().Finalize(chain, block.Header(), statedb, txs)
+	return runner.results
+}
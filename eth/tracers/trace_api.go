@@ -0,0 +1,154 @@
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/core"
+	"github.com/celo-org/celo-blockchain/core/rawdb"
+	"github.com/celo-org/celo-blockchain/rpc"
+)
+
+// maxTraceFilterBlocks is the maximum number of blocks trace_filter searches.
+const maxTraceFilterBlocks = 10000
+
+// TraceAPI is the collection of Parity style trace APIs, serving the flattened
+// call traces of blocks from the trace index.
+type TraceAPI struct {
+	api     *API
+	indexed func() (uint64, bool) // Last block of the trace index, false if nothing was indexed yet
+}
+
+// NewTraceAPI creates a new API definition for the trace methods, serving the
+// traces stored by the given trace indexer.
+func NewTraceAPI(backend Backend, indexer *core.ChainIndexer) *TraceAPI {
+	return &TraceAPI{
+		api: NewAPI(backend),
+		indexed: func() (uint64, bool) {
+			sections, head, _ := indexer.Sections()
+			return head, sections > 0
+		},
+	}
+}
+
+// TraceFilterArgs holds the criteria of trace_filter. Traces match if they
+// originate from any of the from addresses and go to any of the to addresses,
+// where an empty list matches all addresses.
+type TraceFilterArgs struct {
+	FromBlock   *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock     *rpc.BlockNumber `json:"toBlock"`
+	FromAddress []common.Address `json:"fromAddress"`
+	ToAddress   []common.Address `json:"toAddress"`
+}
+
+// Block returns the flattened call traces of the transactions of the block. A
+// block which isn't indexed is traced on demand.
+func (api *TraceAPI) Block(ctx context.Context, number rpc.BlockNumber) ([]*flatCallTrace, error) {
+	block, err := api.api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return api.api.blockTraces(ctx, block)
+}
+
+// Filter returns the flattened call traces of the given range of blocks which
+// match the filter criteria. Only indexed blocks are searched, the range
+// defaulting to all of them.
+func (api *TraceAPI) Filter(ctx context.Context, args TraceFilterArgs) ([]*flatCallTrace, error) {
+	tail, head, err := api.indexRange()
+	if err != nil {
+		return nil, err
+	}
+	from, err := api.resolveBlockNumber(ctx, args.FromBlock, tail)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.resolveBlockNumber(ctx, args.ToBlock, head)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, errors.New("fromBlock is after toBlock")
+	}
+	if from < tail || to > head {
+		return nil, fmt.Errorf("blocks #%d to #%d not indexed, traces are available from #%d to #%d", from, to, tail, head)
+	}
+	if to-from >= maxTraceFilterBlocks {
+		return nil, fmt.Errorf("block range too large: %d blocks, max %d", to-from+1, maxTraceFilterBlocks)
+	}
+	var (
+		db            = api.api.backend.ChainDb()
+		fromAddresses = addressSet(args.FromAddress)
+		toAddresses   = addressSet(args.ToAddress)
+		traces        = make([]*flatCallTrace, 0)
+	)
+	for number := from; number <= to; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		data := rawdb.ReadCallTraces(db, number, rawdb.ReadCanonicalHash(db, number))
+		if data == nil {
+			return nil, fmt.Errorf("traces of block #%d not found", number)
+		}
+		var blockTraces []*flatCallTrace
+		if err := json.Unmarshal(data, &blockTraces); err != nil {
+			return nil, err
+		}
+		for _, trace := range blockTraces {
+			if !matchAddress(fromAddresses, trace.from()) || !matchAddress(toAddresses, trace.to()) {
+				continue
+			}
+			traces = append(traces, trace)
+		}
+	}
+	return traces, nil
+}
+
+// indexRange returns the first and last blocks of the trace index.
+func (api *TraceAPI) indexRange() (uint64, uint64, error) {
+	tail := rawdb.ReadTraceIndexTail(api.api.backend.ChainDb())
+	head, ok := api.indexed()
+	// The sections before the tail are processed without indexing anything
+	if tail == nil || !ok || head < *tail {
+		return 0, 0, errors.New("no blocks indexed yet")
+	}
+	return *tail, head, nil
+}
+
+// resolveBlockNumber returns the number of the given block, or def if nil.
+func (api *TraceAPI) resolveBlockNumber(ctx context.Context, number *rpc.BlockNumber, def uint64) (uint64, error) {
+	if number == nil {
+		return def, nil
+	}
+	if *number >= 0 {
+		return uint64(*number), nil
+	}
+	header, err := api.api.backend.HeaderByNumber(ctx, *number)
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, fmt.Errorf("block #%d not found", *number)
+	}
+	return header.Number.Uint64(), nil
+}
+
+func addressSet(addresses []common.Address) map[common.Address]struct{} {
+	set := make(map[common.Address]struct{}, len(addresses))
+	for _, addr := range addresses {
+		set[addr] = struct{}{}
+	}
+	return set
+}
+
+// matchAddress returns whether addr is in the set, or the set is empty.
+func matchAddress(set map[common.Address]struct{}, addr common.Address) bool {
+	if len(set) == 0 {
+		return true
+	}
+	_, ok := set[addr]
+	return ok
+}
@@ -0,0 +1,334 @@
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/common/hexutil"
+	"github.com/celo-org/celo-blockchain/core"
+	"github.com/celo-org/celo-blockchain/core/rawdb"
+	"github.com/celo-org/celo-blockchain/core/state"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/ethdb"
+	"github.com/celo-org/celo-blockchain/log"
+	"github.com/celo-org/celo-blockchain/rpc"
+)
+
+const (
+	// traceIndexSectionSize is the number of blocks traced and committed to the
+	// database at once by the trace indexer.
+	traceIndexSectionSize = 32
+
+	// traceIndexConfirms is the number of confirmations before a section is
+	// traced. Blocks are final as soon as they are sealed.
+	traceIndexConfirms = 0
+
+	// traceIndexThrottling is the time to wait between processing two
+	// consecutive sections.
+	traceIndexThrottling = 100 * time.Millisecond
+)
+
+// callFrame is a call traced by the callTracer.
+type callFrame struct {
+	Type    string         `json:"type"`
+	From    common.Address `json:"from"`
+	To      common.Address `json:"to"`
+	Value   *hexutil.Big   `json:"value"`
+	Gas     hexutil.Uint64 `json:"gas"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Input   hexutil.Bytes  `json:"input"`
+	Output  hexutil.Bytes  `json:"output"`
+	Error   string         `json:"error"`
+	Calls   []callFrame    `json:"calls"`
+}
+
+// flatCallAction is the action of a flattened call trace. Calls and creations
+// fill in the from, gas and value fields, self-destructs the address, refund
+// address and balance ones.
+type flatCallAction struct {
+	CallType       string          `json:"callType,omitempty"`
+	CreationMethod string          `json:"creationMethod,omitempty"`
+	From           *common.Address `json:"from,omitempty"`
+	To             *common.Address `json:"to,omitempty"`
+	Gas            *hexutil.Uint64 `json:"gas,omitempty"`
+	Input          hexutil.Bytes   `json:"input,omitempty"`
+	Init           hexutil.Bytes   `json:"init,omitempty"`
+	Value          *hexutil.Big    `json:"value,omitempty"`
+	Address        *common.Address `json:"address,omitempty"`
+	RefundAddress  *common.Address `json:"refundAddress,omitempty"`
+	Balance        *hexutil.Big    `json:"balance,omitempty"`
+}
+
+// flatCallResult is the result of a successful flattened call trace.
+type flatCallResult struct {
+	Address *common.Address `json:"address,omitempty"`
+	Code    hexutil.Bytes   `json:"code,omitempty"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Output  hexutil.Bytes   `json:"output,omitempty"`
+}
+
+// flatCallTrace is a call trace in the flat format of the Parity trace module.
+// The position of the call in the tree of calls of its transaction is given by
+// its trace address.
+type flatCallTrace struct {
+	Action              flatCallAction  `json:"action"`
+	BlockHash           common.Hash     `json:"blockHash"`
+	BlockNumber         uint64          `json:"blockNumber"`
+	Error               string          `json:"error,omitempty"`
+	Result              *flatCallResult `json:"result,omitempty"`
+	Subtraces           int             `json:"subtraces"`
+	TraceAddress        []int           `json:"traceAddress"`
+	TransactionHash     common.Hash     `json:"transactionHash"`
+	TransactionPosition uint64          `json:"transactionPosition"`
+	Type                string          `json:"type"`
+}
+
+// from returns the account the traced call originated from.
+func (t *flatCallTrace) from() common.Address {
+	if t.Action.From != nil {
+		return *t.Action.From
+	}
+	if t.Action.Address != nil {
+		return *t.Action.Address
+	}
+	return common.Address{}
+}
+
+// to returns the account the traced call was made to, or created.
+func (t *flatCallTrace) to() common.Address {
+	switch {
+	case t.Action.To != nil:
+		return *t.Action.To
+	case t.Action.RefundAddress != nil:
+		return *t.Action.RefundAddress
+	case t.Result != nil && t.Result.Address != nil:
+		return *t.Result.Address
+	}
+	return common.Address{}
+}
+
+// flattenCallFrame appends the flattened traces of the frame and its subcalls
+// to traces, in depth-first order.
+func flattenCallFrame(frame *callFrame, template flatCallTrace, traceAddress []int, traces []*flatCallTrace) []*flatCallTrace {
+	trace := template
+	trace.Subtraces = len(frame.Calls)
+	trace.TraceAddress = traceAddress
+	trace.Error = frame.Error
+
+	from, to, gas := frame.From, frame.To, frame.Gas
+	value := frame.Value
+	if value == nil {
+		value = new(hexutil.Big)
+	}
+	switch typ := strings.ToUpper(frame.Type); typ {
+	case "CREATE", "CREATE2":
+		trace.Type = "create"
+		trace.Action = flatCallAction{
+			CreationMethod: strings.ToLower(typ),
+			From:           &from,
+			Gas:            &gas,
+			Init:           frame.Input,
+			Value:          value,
+		}
+		if frame.Error == "" {
+			trace.Result = &flatCallResult{Address: &to, Code: frame.Output, GasUsed: frame.GasUsed}
+		}
+	case "SELFDESTRUCT":
+		trace.Type = "suicide"
+		trace.Action = flatCallAction{
+			Address:       &from,
+			RefundAddress: &to,
+			Balance:       value,
+		}
+	default:
+		trace.Type = "call"
+		trace.Action = flatCallAction{
+			CallType: strings.ToLower(typ),
+			From:     &from,
+			To:       &to,
+			Gas:      &gas,
+			Input:    frame.Input,
+			Value:    value,
+		}
+		if frame.Error == "" {
+			trace.Result = &flatCallResult{GasUsed: frame.GasUsed, Output: frame.Output}
+		}
+	}
+	traces = append(traces, &trace)
+
+	for i := range frame.Calls {
+		// Subcalls need their own copy of the trace address
+		childAddress := make([]int, len(traceAddress)+1)
+		copy(childAddress, traceAddress)
+		childAddress[len(traceAddress)] = i
+		traces = flattenCallFrame(&frame.Calls[i], template, childAddress, traces)
+	}
+	return traces
+}
+
+// flatTraceBlock traces the calls made by the transactions of the block with
+// the callTracer and flattens them.
+func (api *API) flatTraceBlock(ctx context.Context, block *types.Block) ([]*flatCallTrace, error) {
+	traces := make([]*flatCallTrace, 0)
+	if block.NumberU64() == 0 {
+		return traces, nil
+	}
+	tracer := "callTracer"
+	results, err := api.traceBlock(ctx, block, &TraceConfig{Tracer: &tracer})
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range results {
+		if result.Error != "" {
+			return nil, fmt.Errorf("failed to trace transaction %#x: %s", result.TxHash, result.Error)
+		}
+		raw, ok := result.Result.(json.RawMessage)
+		if !ok {
+			return nil, fmt.Errorf("unexpected trace result type %T", result.Result)
+		}
+		frame := new(callFrame)
+		if err := json.Unmarshal(raw, frame); err != nil {
+			return nil, err
+		}
+		template := flatCallTrace{
+			BlockHash:           block.Hash(),
+			BlockNumber:         block.NumberU64(),
+			TransactionHash:     result.TxHash,
+			TransactionPosition: uint64(i),
+		}
+		traces = flattenCallFrame(frame, template, []int{}, traces)
+	}
+	return traces, nil
+}
+
+// blockTraces returns the flattened call traces of the block, from the trace
+// index if it was indexed, or by tracing it otherwise.
+func (api *API) blockTraces(ctx context.Context, block *types.Block) ([]*flatCallTrace, error) {
+	if data := rawdb.ReadCallTraces(api.backend.ChainDb(), block.NumberU64(), block.Hash()); data != nil {
+		var traces []*flatCallTrace
+		if err := json.Unmarshal(data, &traces); err != nil {
+			return nil, err
+		}
+		return traces, nil
+	}
+	return api.flatTraceBlock(ctx, block)
+}
+
+// traceIndexer implements core.ChainIndexerBackend, storing the flattened call
+// traces of each block of the canonical chain into the database.
+type traceIndexer struct {
+	api     *API
+	db      ethdb.Database
+	batch   ethdb.Batch
+	archive bool    // Whether the node keeps the state of every block it processes
+	tail    *uint64 // First indexed block, nil until the first section is processed
+}
+
+// NewTraceIndexer returns a chain indexer that stores the flattened call traces
+// of the canonical chain for trace_filter and trace_block. Blocks are traced on
+// top of the state of their parent, so only archive nodes which processed the
+// chain index it from the genesis. Other nodes start with the section of their
+// head at the time the index is first enabled, older blocks only being traced
+// on demand by trace_block.
+func NewTraceIndexer(backend Backend, archive bool) *core.ChainIndexer {
+	db := backend.ChainDb()
+	indexer := &traceIndexer{
+		api:     NewAPI(backend),
+		db:      db,
+		archive: archive,
+		tail:    rawdb.ReadTraceIndexTail(db),
+	}
+	table := rawdb.NewTable(db, string(rawdb.TraceIndexPrefix))
+
+	return core.NewChainIndexer(db, table, indexer, traceIndexSectionSize, traceIndexConfirms, traceIndexThrottling, "traces", backend.ChainConfig().FullHeaderChainAvailable)
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new trace index section.
+// The first section processed decides which block the index starts with.
+func (t *traceIndexer) Reset(ctx context.Context, section uint64, prevHead common.Hash) error {
+	if t.tail == nil {
+		tail, err := t.indexTail(ctx, section)
+		if err != nil {
+			return err
+		}
+		rawdb.WriteTraceIndexTail(t.db, tail)
+		t.tail = &tail
+		log.Info("Started call trace index", "tail", tail)
+	}
+	t.batch = t.db.NewBatch()
+	return nil
+}
+
+// indexTail returns the first block to index when the index starts with the
+// given section. It's the first block of the section if the node has the state
+// of its parent and of every block after, and the first block of the section
+// of the current head otherwise, whose parent state is recent enough to be
+// regenerated.
+func (t *traceIndexer) indexTail(ctx context.Context, section uint64) (uint64, error) {
+	start := section * traceIndexSectionSize
+	if t.archive {
+		// Fast synced nodes have the genesis state too, but not the one of the
+		// first block
+		parent := start
+		if parent > 0 {
+			parent--
+		} else {
+			parent = 1
+		}
+		header, err := t.api.backend.HeaderByNumber(ctx, rpc.BlockNumber(parent))
+		if err == nil && header != nil {
+			if _, err := state.NewDatabase(t.db).OpenTrie(header.Root); err == nil {
+				return start, nil
+			}
+		}
+	}
+	head, err := t.api.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+	number := head.Number.Uint64()
+	return number - number%traceIndexSectionSize, nil
+}
+
+// Process implements core.ChainIndexerBackend, tracing the block of the header.
+// Blocks before the tail of the index are skipped.
+func (t *traceIndexer) Process(ctx context.Context, header *types.Header) error {
+	if header.Number.Uint64() < *t.tail {
+		return nil
+	}
+	block, err := t.api.blockByNumberAndHash(ctx, rpc.BlockNumber(header.Number.Int64()), header.Hash())
+	if err != nil {
+		return err
+	}
+	traces, err := t.api.flatTraceBlock(ctx, block)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(traces)
+	if err != nil {
+		return err
+	}
+	rawdb.WriteCallTraces(t.batch, block.NumberU64(), block.Hash(), data)
+	if t.batch.ValueSize() >= ethdb.IdealBatchSize {
+		if err := t.batch.Write(); err != nil {
+			return err
+		}
+		t.batch.Reset()
+	}
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, writing out the traces of the
+// section into the database.
+func (t *traceIndexer) Commit() error {
+	return t.batch.Write()
+}
+
+// Prune returns an empty error since we don't support pruning here.
+func (t *traceIndexer) Prune(threshold uint64) error {
+	return nil
+}
@@ -0,0 +1,231 @@
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/core"
+	"github.com/celo-org/celo-blockchain/core/rawdb"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/params"
+	"github.com/celo-org/celo-blockchain/rpc"
+)
+
+func TestFlattenCallFrame(t *testing.T) {
+	t.Parallel()
+
+	var frame callFrame
+	err := json.Unmarshal([]byte(`{
+		"type": "CALL", "from": "0x0000000000000000000000000000000000000001", "to": "0x0000000000000000000000000000000000000002",
+		"value": "0x1", "gas": "0x5208", "gasUsed": "0x5000", "input": "0x01", "output": "0x02",
+		"calls": [
+			{"type": "STATICCALL", "from": "0x0000000000000000000000000000000000000002", "to": "0x0000000000000000000000000000000000000003",
+				"gas": "0x100", "gasUsed": "0x10", "input": "0x", "output": "0x"},
+			{"type": "CREATE2", "from": "0x0000000000000000000000000000000000000002", "to": "0x0000000000000000000000000000000000000004",
+				"value": "0x0", "gas": "0x200", "gasUsed": "0x20", "input": "0x6000", "output": "0x00",
+				"calls": [
+					{"type": "SELFDESTRUCT", "from": "0x0000000000000000000000000000000000000004", "to": "0x0000000000000000000000000000000000000005",
+						"value": "0x0", "gas": "0x0", "gasUsed": "0x0", "input": "0x"}
+				]},
+			{"type": "CALL", "from": "0x0000000000000000000000000000000000000002", "to": "0x0000000000000000000000000000000000000006",
+				"value": "0x0", "gas": "0x100", "gasUsed": "0x100", "input": "0x", "error": "out of gas"}
+		]
+	}`), &frame)
+	if err != nil {
+		t.Fatalf("failed to unmarshal call frame: %v", err)
+	}
+	template := flatCallTrace{BlockNumber: 3, TransactionPosition: 1}
+	traces := flattenCallFrame(&frame, template, []int{}, nil)
+
+	type want struct {
+		typ          string
+		traceAddress []int
+		subtraces    int
+		from, to     common.Address
+		result       bool
+	}
+	wants := []want{
+		{"call", []int{}, 3, common.HexToAddress("0x1"), common.HexToAddress("0x2"), true},
+		{"call", []int{0}, 0, common.HexToAddress("0x2"), common.HexToAddress("0x3"), true},
+		{"create", []int{1}, 1, common.HexToAddress("0x2"), common.HexToAddress("0x4"), true},
+		// Self-destructs and failed calls have no result
+		{"suicide", []int{1, 0}, 0, common.HexToAddress("0x4"), common.HexToAddress("0x5"), false},
+		{"call", []int{2}, 0, common.HexToAddress("0x2"), common.HexToAddress("0x6"), false},
+	}
+	if len(traces) != len(wants) {
+		t.Fatalf("trace count mismatch: have %d, want %d", len(traces), len(wants))
+	}
+	for i, want := range wants {
+		trace := traces[i]
+		if trace.Type != want.typ || !reflect.DeepEqual(trace.TraceAddress, want.traceAddress) || trace.Subtraces != want.subtraces {
+			t.Errorf("trace %d mismatch: have %s %v %d, want %s %v %d", i, trace.Type, trace.TraceAddress, trace.Subtraces, want.typ, want.traceAddress, want.subtraces)
+		}
+		if trace.from() != want.from || trace.to() != want.to {
+			t.Errorf("trace %d addresses mismatch: have %x -> %x, want %x -> %x", i, trace.from(), trace.to(), want.from, want.to)
+		}
+		if result := trace.Result != nil; result != want.result {
+			t.Errorf("trace %d result mismatch: have %v, want %v", i, result, want.result)
+		}
+		if trace.BlockNumber != 3 || trace.TransactionPosition != 1 {
+			t.Errorf("trace %d position mismatch: have block %d tx %d", i, trace.BlockNumber, trace.TransactionPosition)
+		}
+	}
+	if traces[1].Action.CallType != "staticcall" || traces[1].Action.Value.ToInt().Sign() != 0 {
+		t.Errorf("static call action mismatch: %+v", traces[1].Action)
+	}
+	if traces[2].Action.CreationMethod != "create2" || len(traces[2].Action.Init) != 2 {
+		t.Errorf("create action mismatch: %+v", traces[2].Action)
+	}
+}
+
+func TestTraceFilter(t *testing.T) {
+	t.Parallel()
+
+	accounts := newAccounts(2)
+	genesis := &core.Genesis{Alloc: core.GenesisAlloc{
+		accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+	}}
+	genBlocks := 3
+	signer := types.HomesteadSigner{}
+	backend := newTestBackend(t, genBlocks, genesis, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), accounts[1].addr, big.NewInt(1000), params.TxGas, b.MinimumGasPrice(nil), nil), signer, accounts[0].key)
+		b.AddTx(tx)
+	})
+	// Index a call from each block's number to the next one
+	for i := 1; i <= genBlocks; i++ {
+		block := backend.chain.GetBlockByNumber(uint64(i))
+		frame := &callFrame{
+			Type: "CALL",
+			From: common.BigToAddress(big.NewInt(int64(i))),
+			To:   common.BigToAddress(big.NewInt(int64(i + 1))),
+		}
+		template := flatCallTrace{BlockHash: block.Hash(), BlockNumber: block.NumberU64(), TransactionHash: block.Transactions()[0].Hash()}
+		data, err := json.Marshal(flattenCallFrame(frame, template, []int{}, nil))
+		if err != nil {
+			t.Fatalf("failed to encode traces: %v", err)
+		}
+		rawdb.WriteCallTraces(backend.chaindb, block.NumberU64(), block.Hash(), data)
+	}
+	rawdb.WriteTraceIndexTail(backend.chaindb, 1)
+	indexed := func(head uint64) func() (uint64, bool) {
+		return func() (uint64, bool) { return head, true }
+	}
+	api := &TraceAPI{api: NewAPI(backend), indexed: indexed(uint64(genBlocks))}
+
+	traces, err := api.Block(context.Background(), rpc.BlockNumber(2))
+	if err != nil {
+		t.Fatalf("failed to get block traces: %v", err)
+	}
+	if len(traces) != 1 || traces[0].from() != common.BigToAddress(big.NewInt(2)) {
+		t.Fatalf("indexed block traces mismatch: %+v", traces)
+	}
+
+	number := func(n int64) *rpc.BlockNumber {
+		bn := rpc.BlockNumber(n)
+		return &bn
+	}
+	var testSuite = []struct {
+		args   TraceFilterArgs
+		blocks []uint64
+		expErr bool
+	}{
+		// Everything indexed
+		{args: TraceFilterArgs{}, blocks: []uint64{1, 2, 3}},
+		{args: TraceFilterArgs{ToBlock: number(int64(rpc.LatestBlockNumber))}, blocks: []uint64{1, 2, 3}},
+		{args: TraceFilterArgs{FromBlock: number(2), ToBlock: number(2)}, blocks: []uint64{2}},
+		{args: TraceFilterArgs{FromAddress: []common.Address{common.BigToAddress(big.NewInt(1)), common.BigToAddress(big.NewInt(3))}}, blocks: []uint64{1, 3}},
+		{args: TraceFilterArgs{ToAddress: []common.Address{common.BigToAddress(big.NewInt(3))}}, blocks: []uint64{2}},
+		// Both criteria must match
+		{args: TraceFilterArgs{FromAddress: []common.Address{common.BigToAddress(big.NewInt(1))}, ToAddress: []common.Address{common.BigToAddress(big.NewInt(3))}}, blocks: []uint64{}},
+		{args: TraceFilterArgs{FromBlock: number(3), ToBlock: number(1)}, expErr: true},
+		// Blocks outside of the index are not traced
+		{args: TraceFilterArgs{FromBlock: number(0)}, expErr: true},
+		{args: TraceFilterArgs{ToBlock: number(4)}, expErr: true},
+	}
+	for i, tc := range testSuite {
+		traces, err := api.Filter(context.Background(), tc.args)
+		if tc.expErr {
+			if err == nil {
+				t.Errorf("test %d: expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: failed to filter traces: %v", i, err)
+			continue
+		}
+		blocks := make([]uint64, 0, len(traces))
+		for _, trace := range traces {
+			blocks = append(blocks, trace.BlockNumber)
+		}
+		if !reflect.DeepEqual(blocks, tc.blocks) {
+			t.Errorf("test %d: traced blocks mismatch: have %v, want %v", i, blocks, tc.blocks)
+		}
+	}
+
+	// Large ranges are rejected even if indexed
+	api.indexed = indexed(maxTraceFilterBlocks + 1)
+	if _, err := api.Filter(context.Background(), TraceFilterArgs{}); err == nil {
+		t.Error("expected error for a range too large")
+	}
+	// Nothing can be filtered until the first section is indexed
+	api.indexed = func() (uint64, bool) { return 0, false }
+	if _, err := api.Filter(context.Background(), TraceFilterArgs{FromBlock: number(1), ToBlock: number(1)}); err == nil {
+		t.Error("expected error without an index")
+	}
+}
+
+func TestTraceIndexTail(t *testing.T) {
+	t.Parallel()
+
+	genBlocks := traceIndexSectionSize + 8
+	ctx := context.Background()
+
+	// Archive nodes which processed the whole chain index it from the genesis
+	backend := newTestBackend(t, genBlocks, &core.Genesis{}, nil)
+	indexer := &traceIndexer{api: NewAPI(backend), db: backend.chaindb, archive: true}
+	if err := indexer.Reset(ctx, 0, common.Hash{}); err != nil {
+		t.Fatalf("failed to reset indexer: %v", err)
+	}
+	if tail := rawdb.ReadTraceIndexTail(backend.chaindb); tail == nil || *tail != 0 {
+		t.Fatalf("archive index tail mismatch: have %v, want 0", tail)
+	}
+
+	// Other nodes start with the section of their head
+	backend = newTestBackend(t, genBlocks, &core.Genesis{}, nil)
+	indexer = &traceIndexer{api: NewAPI(backend), db: backend.chaindb}
+	if err := indexer.Reset(ctx, 0, common.Hash{}); err != nil {
+		t.Fatalf("failed to reset indexer: %v", err)
+	}
+	if tail := rawdb.ReadTraceIndexTail(backend.chaindb); tail == nil || *tail != traceIndexSectionSize {
+		t.Fatalf("index tail mismatch: have %v, want %d", tail, traceIndexSectionSize)
+	}
+	// Blocks before the tail are skipped
+	for _, number := range []uint64{1, traceIndexSectionSize} {
+		if err := indexer.Process(ctx, backend.chain.GetHeaderByNumber(number)); err != nil {
+			t.Fatalf("failed to process block %d: %v", number, err)
+		}
+	}
+	if err := indexer.Commit(); err != nil {
+		t.Fatalf("failed to commit section: %v", err)
+	}
+	if traces := rawdb.ReadCallTraces(backend.chaindb, 1, backend.chain.GetHeaderByNumber(1).Hash()); traces != nil {
+		t.Fatalf("block before the tail indexed: %s", traces)
+	}
+	head := backend.chain.GetHeaderByNumber(traceIndexSectionSize)
+	if traces := rawdb.ReadCallTraces(backend.chaindb, head.Number.Uint64(), head.Hash()); traces == nil {
+		t.Fatalf("block at the tail not indexed")
+	}
+	// The tail is kept once decided
+	indexer = &traceIndexer{api: NewAPI(backend), db: backend.chaindb, archive: true, tail: rawdb.ReadTraceIndexTail(backend.chaindb)}
+	if err := indexer.Reset(ctx, 1, common.Hash{}); err != nil {
+		t.Fatalf("failed to reset indexer: %v", err)
+	}
+	if *indexer.tail != traceIndexSectionSize {
+		t.Fatalf("index tail changed: have %d, want %d", *indexer.tail, traceIndexSectionSize)
+	}
+}
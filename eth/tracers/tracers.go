@@ -22,6 +22,7 @@ import (
 	"errors"
 
 	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/core/state"
 	"github.com/celo-org/celo-blockchain/core/vm"
 )
 
@@ -42,7 +43,17 @@ type Tracer interface {
 	Stop(err error)
 }
 
-type lookupFunc func(string, *Context) (Tracer, error)
+// StateTracer is implemented by tracers that need to inspect the state before
+// and after the traced transaction, e.g. to observe the fee debits and credits
+// made by system calls outside of the traced EVM.
+type StateTracer interface {
+	// CaptureTxStart is called with a copy of the state before the transaction.
+	CaptureTxStart(pre *state.StateDB)
+	// CaptureTxEnd is called with the state once the transaction is applied.
+	CaptureTxEnd(post *state.StateDB)
+}
+
+type lookupFunc func(string, *Context, json.RawMessage) (Tracer, error)
 
 var (
 	lookups []lookupFunc
@@ -61,10 +72,10 @@ func RegisterLookup(wildcard bool, lookup lookupFunc) {
 }
 
 // New returns a new instance of a tracer, by iterating through the
-// registered lookups.
-func New(code string, ctx *Context) (Tracer, error) {
+// registered lookups. The tracer specific configuration cfg may be nil.
+func New(code string, ctx *Context, cfg json.RawMessage) (Tracer, error) {
 	for _, lookup := range lookups {
-		if tracer, err := lookup(code, ctx); err == nil {
+		if tracer, err := lookup(code, ctx, cfg); err == nil {
 			return tracer, nil
 		}
 	}
@@ -184,6 +184,38 @@ func (ec *Client) SubscribePendingTransactions(ctx context.Context, ch chan<- co
 	return ec.c.EthSubscribe(ctx, ch, "newPendingTransactions")
 }
 
+// PendingTransactionsFilter selects the transactions sent by a filtered pending
+// transactions subscription. Unset fields match all transactions.
+type PendingTransactionsFilter struct {
+	From          []common.Address
+	To            []common.Address
+	FeeCurrencies []common.Address // the zero address selects CELO
+	MinTip        *big.Int         // in the fee currency of each transaction
+}
+
+// SubscribeFilteredPendingTransactions subscribes to the new pending transactions
+// matching the filter.
+func (ec *Client) SubscribeFilteredPendingTransactions(ctx context.Context, filter PendingTransactionsFilter, ch chan<- *types.Transaction) (*rpc.ClientSubscription, error) {
+	return ec.c.EthSubscribe(ctx, ch, "newPendingTransactions", true, toPendingTransactionsFilterArg(filter))
+}
+
+func toPendingTransactionsFilterArg(filter PendingTransactionsFilter) interface{} {
+	arg := map[string]interface{}{}
+	if len(filter.From) > 0 {
+		arg["from"] = filter.From
+	}
+	if len(filter.To) > 0 {
+		arg["to"] = filter.To
+	}
+	if len(filter.FeeCurrencies) > 0 {
+		arg["feeCurrencies"] = filter.FeeCurrencies
+	}
+	if filter.MinTip != nil {
+		arg["minTip"] = (*hexutil.Big)(filter.MinTip)
+	}
+	return arg
+}
+
 func toBlockNumArg(number *big.Int) string {
 	if number == nil {
 		return "latest"
@@ -125,6 +125,9 @@ func TestGethClient(t *testing.T) {
 		}, {
 			"TestSubscribePendingTxs",
 			func(t *testing.T) { testSubscribeFullPendingTransactions(t, client) },
+		}, {
+			"TestSubscribeFilteredPendingTxs",
+			func(t *testing.T) { testSubscribeFilteredPendingTransactions(t, client) },
 		}, {
 			"TestCallContract",
 			func(t *testing.T) { testCallContract(t, client) },
@@ -320,6 +323,43 @@ func testSubscribeFullPendingTransactions(t *testing.T, client *rpc.Client) {
 	}
 }
 
+func testSubscribeFilteredPendingTransactions(t *testing.T, client *rpc.Client) {
+	ec := New(client)
+	ethcl := ethclient.NewClient(client)
+	// Subscribe to transactions sent to a single recipient
+	ch := make(chan *types.Transaction)
+	filter := PendingTransactionsFilter{
+		From:          []common.Address{testAddr},
+		To:            []common.Address{{2}},
+		FeeCurrencies: []common.Address{{}},
+	}
+	if _, err := ec.SubscribeFilteredPendingTransactions(context.Background(), filter, ch); err != nil {
+		t.Fatal(err)
+	}
+	chainID, err := ethcl.ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	// Send a transaction to another recipient first, which must be filtered out
+	var sent []*types.Transaction
+	for i, to := range []common.Address{{1}, {2}} {
+		tx, err := types.SignTx(types.NewTransaction(uint64(2+i), to, big.NewInt(1), 22000, big.NewInt(1), nil), signer, testKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ethcl.SendTransaction(context.Background(), tx); err != nil {
+			t.Fatal(err)
+		}
+		sent = append(sent, tx)
+	}
+	// Check that only the matching transaction was sent over the channel
+	tx := <-ch
+	if tx.Hash() != sent[1].Hash() {
+		t.Fatalf("Invalid tx hash received, got %v, want %v", tx.Hash(), sent[1].Hash())
+	}
+}
+
 func testCallContract(t *testing.T, client *rpc.Client) {
 	ec := New(client)
 	msg := ethereum.CallMsg{
@@ -30,6 +30,7 @@ import (
 	"github.com/celo-org/celo-blockchain/common"
 	"github.com/celo-org/celo-blockchain/common/hexutil"
 	"github.com/celo-org/celo-blockchain/common/math"
+	"github.com/celo-org/celo-blockchain/contracts/currency"
 	"github.com/celo-org/celo-blockchain/core"
 	"github.com/celo-org/celo-blockchain/core/state"
 	"github.com/celo-org/celo-blockchain/core/types"
@@ -69,40 +70,41 @@ func (s *PublicEthereumAPI) MaxPriorityFeePerGas(ctx context.Context, feeCurrenc
 	return (*hexutil.Big)(tipcap), err
 }
 
-// TODO: Implement FeeHistory (https://github.com/celo-org/celo-blockchain/issues/1744)
-// type feeHistoryResult struct {
-// 	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
-// 	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
-// 	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
-// 	GasUsedRatio []float64        `json:"gasUsedRatio"`
-// }
-
-// func (s *PublicEthereumAPI) FeeHistory(ctx context.Context, blockCount rpc.DecimalOrHex, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
-// 	oldest, reward, baseFee, gasUsed, err := s.b.FeeHistory(ctx, int(blockCount), lastBlock, rewardPercentiles)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	results := &feeHistoryResult{
-// 		OldestBlock:  (*hexutil.Big)(oldest),
-// 		GasUsedRatio: gasUsed,
-// 	}
-// 	if reward != nil {
-// 		results.Reward = make([][]*hexutil.Big, len(reward))
-// 		for i, w := range reward {
-// 			results.Reward[i] = make([]*hexutil.Big, len(w))
-// 			for j, v := range w {
-// 				results.Reward[i][j] = (*hexutil.Big)(v)
-// 			}
-// 		}
-// 	}
-// 	if baseFee != nil {
-// 		results.BaseFee = make([]*hexutil.Big, len(baseFee))
-// 		for i, v := range baseFee {
-// 			results.BaseFee[i] = (*hexutil.Big)(v)
-// 		}
-// 	}
-// 	return results, nil
-// }
+type feeHistoryResult struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// FeeHistory returns the fee market history. Base fees and rewards are
+// denominated in feeCurrency, or in CELO if it is omitted.
+func (s *PublicEthereumAPI) FeeHistory(ctx context.Context, blockCount rpc.DecimalOrHex, lastBlock rpc.BlockNumber, rewardPercentiles []float64, feeCurrency *common.Address) (*feeHistoryResult, error) {
+	oldest, reward, baseFee, gasUsed, err := s.b.FeeHistory(ctx, int(blockCount), lastBlock, rewardPercentiles, feeCurrency)
+	if err != nil {
+		return nil, err
+	}
+	results := &feeHistoryResult{
+		OldestBlock:  (*hexutil.Big)(oldest),
+		GasUsedRatio: gasUsed,
+	}
+	if reward != nil {
+		results.Reward = make([][]*hexutil.Big, len(reward))
+		for i, w := range reward {
+			results.Reward[i] = make([]*hexutil.Big, len(w))
+			for j, v := range w {
+				results.Reward[i][j] = (*hexutil.Big)(v)
+			}
+		}
+	}
+	if baseFee != nil {
+		results.BaseFee = make([]*hexutil.Big, len(baseFee))
+		for i, v := range baseFee {
+			results.BaseFee[i] = (*hexutil.Big)(v)
+		}
+	}
+	return results, nil
+}
 
 // Syncing returns false in case the node is currently not syncing with the network. It can be up to date or has not
 // yet received the latest block headers from its pears. In case it is synchronizing:
@@ -189,13 +191,116 @@ func (s *PublicTxPoolAPI) ContentFrom(addr common.Address) map[string]map[string
 	return content
 }
 
-// Status returns the number of pending and queued transaction in the pool.
-func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
+// ContentByFeeCurrency returns the transactions contained within the transaction
+// pool grouped by the currency they pay fees in. CELO is keyed by the zero address.
+func (s *PublicTxPoolAPI) ContentByFeeCurrency() map[string]map[string]map[string]map[string]*RPCTransaction {
+	content := make(map[string]map[string]map[string]map[string]*RPCTransaction)
+	pending, queue := s.b.TxPoolContent()
+	curHeader := s.b.CurrentHeader()
+
+	flatten := func(status string, txs map[common.Address]types.Transactions) {
+		for account, txs := range txs {
+			for _, tx := range txs {
+				key := feeCurrencyKey(tx.FeeCurrency())
+				if content[key] == nil {
+					content[key] = map[string]map[string]map[string]*RPCTransaction{
+						"pending": make(map[string]map[string]*RPCTransaction),
+						"queued":  make(map[string]map[string]*RPCTransaction),
+					}
+				}
+				dump := content[key][status][account.Hex()]
+				if dump == nil {
+					dump = make(map[string]*RPCTransaction)
+					content[key][status][account.Hex()] = dump
+				}
+				dump[fmt.Sprintf("%d", tx.Nonce())] = NewRPCPendingTransaction(tx, curHeader, s.b.ChainConfig())
+			}
+		}
+	}
+	flatten("pending", pending)
+	flatten("queued", queue)
+	return content
+}
+
+// TxPoolStatus is the number of pending and queued transactions in the pool,
+// along with a breakdown per fee currency. CELO is keyed by the zero address.
+// The breakdown is omitted by nodes whose pool doesn't track it.
+type TxPoolStatus struct {
+	Pending       hexutil.Uint                        `json:"pending"`
+	Queued        hexutil.Uint                        `json:"queued"`
+	FeeCurrencies map[string]*TxPoolFeeCurrencyStatus `json:"feeCurrencies,omitempty"`
+}
+
+// TxPoolFeeCurrencyStatus summarizes the transactions paying fees in a single
+// currency. The tips are the effective tips of the pending transactions at the
+// current gas price minimums, converted to CELO, and are omitted when there are
+// no pending transactions.
+type TxPoolFeeCurrencyStatus struct {
+	Pending         hexutil.Uint `json:"pending"`
+	Queued          hexutil.Uint `json:"queued"`
+	CheapestTip     *hexutil.Big `json:"cheapestTip,omitempty"`
+	MedianTip       *hexutil.Big `json:"medianTip,omitempty"`
+	GasPriceMinimum *hexutil.Big `json:"gasPriceMinimum"`
+}
+
+// Status returns the number of pending and queued transaction in the pool, in
+// total and for every whitelisted fee currency.
+func (s *PublicTxPoolAPI) Status() *TxPoolStatus {
 	pending, queue := s.b.Stats()
-	return map[string]hexutil.Uint{
-		"pending": hexutil.Uint(pending),
-		"queued":  hexutil.Uint(queue),
+	status := &TxPoolStatus{
+		Pending: hexutil.Uint(pending),
+		Queued:  hexutil.Uint(queue),
+	}
+	if stats := s.b.TxPoolFeeCurrencyStats(); stats != nil {
+		status.FeeCurrencies = make(map[string]*TxPoolFeeCurrencyStatus, len(stats))
+		for address, currencyStats := range stats {
+			status.FeeCurrencies[address.Hex()] = &TxPoolFeeCurrencyStatus{
+				Pending:         hexutil.Uint(currencyStats.Pending),
+				Queued:          hexutil.Uint(currencyStats.Queued),
+				CheapestTip:     (*hexutil.Big)(currencyStats.CheapestTip),
+				MedianTip:       (*hexutil.Big)(currencyStats.MedianTip),
+				GasPriceMinimum: (*hexutil.Big)(currencyStats.GasPriceMinimum),
+			}
+		}
 	}
+	return status
+}
+
+// feeCurrencyKey returns the key a fee currency is reported under, using the
+// zero address for CELO.
+func feeCurrencyKey(feeCurrency *common.Address) string {
+	if feeCurrency == nil {
+		return common.ZeroAddress.Hex()
+	}
+	return feeCurrency.Hex()
+}
+
+// PrivateTxPoolAPI offers administrative control over the transaction pool.
+type PrivateTxPoolAPI struct {
+	b Backend
+}
+
+// NewPrivateTxPoolAPI creates a new tx pool service to manage the transaction pool.
+func NewPrivateTxPoolAPI(b Backend) *PrivateTxPoolAPI {
+	return &PrivateTxPoolAPI{b}
+}
+
+// RemoveTransaction drops the transaction with the given hash from the pool and
+// returns whether it was found.
+func (s *PrivateTxPoolAPI) RemoveTransaction(hash common.Hash) bool {
+	return s.b.TxPoolRemoveTransaction(hash)
+}
+
+// EvictAccount drops all transactions of the given account from the pool and,
+// if a ban duration in seconds is given, rejects its new transactions for that
+// long. It returns the number of transactions dropped.
+func (s *PrivateTxPoolAPI) EvictAccount(addr common.Address, ban *uint64) (hexutil.Uint, error) {
+	var duration time.Duration
+	if ban != nil {
+		duration = time.Duration(*ban) * time.Second
+	}
+	evicted, err := s.b.TxPoolEvictAccount(addr, duration)
+	return hexutil.Uint(evicted), err
 }
 
 // Inspect retrieves the content of the transaction pool and flattens it into an
@@ -906,13 +1011,66 @@ func (diff *StateOverride) Apply(state *state.StateDB) error {
 	return nil
 }
 
-func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, timeout time.Duration, globalGasCap uint64, skipDebitCredit bool) (*core.ExecutionResult, error) {
+// ExchangeRateOverride is an overridden fee currency exchange rate, such that
+// numerator units of the currency are worth denominator units of CELO.
+type ExchangeRateOverride struct {
+	Numerator   *hexutil.Big `json:"numerator"`
+	Denominator *hexutil.Big `json:"denominator"`
+}
+
+// FeeCurrencyOverride is the collection of overridden fee currency parameters.
+type FeeCurrencyOverride struct {
+	Whitelist        *[]common.Address                        `json:"whitelist"`
+	GasPriceMinimums map[common.Address]*hexutil.Big          `json:"gasPriceMinimums"` // the zero address is CELO
+	ExchangeRates    map[common.Address]*ExchangeRateOverride `json:"exchangeRates"`
+}
+
+// Apply overrides the fee currency parameters of the given system contract context.
+func (diff *FeeCurrencyOverride) Apply(sysCtx *core.SysContractCallCtx) error {
+	if diff == nil {
+		return nil
+	}
+	if diff.Whitelist != nil {
+		sysCtx.OverrideWhitelist(*diff.Whitelist)
+	}
+	for addr, gasPriceMinimum := range diff.GasPriceMinimums {
+		if gasPriceMinimum == nil {
+			return fmt.Errorf("missing gas price minimum for %s", addr.Hex())
+		}
+		feeCurrency := &addr
+		if addr == common.ZeroAddress {
+			feeCurrency = nil
+		}
+		sysCtx.OverrideGasPriceMinimum(feeCurrency, gasPriceMinimum.ToInt())
+	}
+	for addr, rate := range diff.ExchangeRates {
+		if addr == common.ZeroAddress {
+			return errors.New("the exchange rate of CELO can't be overridden")
+		}
+		if rate == nil {
+			return fmt.Errorf("missing exchange rate for %s", addr.Hex())
+		}
+		exchangeRate, err := currency.NewExchangeRate(rate.Numerator.ToInt(), rate.Denominator.ToInt())
+		if err != nil {
+			return fmt.Errorf("invalid exchange rate for %s: %w", addr.Hex(), err)
+		}
+		sysCtx.OverrideExchangeRate(addr, exchangeRate)
+	}
+	return nil
+}
+
+func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, feeOverrides *FeeCurrencyOverride, timeout time.Duration, globalGasCap uint64, skipDebitCredit bool) (*core.ExecutionResult, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
 	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
+	return doCall(ctx, b, args, state, header, overrides, feeOverrides, timeout, globalGasCap, skipDebitCredit)
+}
+
+// doCall executes the call on top of the given state, which it modifies.
+func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.StateDB, header *types.Header, overrides *StateOverride, feeOverrides *FeeCurrencyOverride, timeout time.Duration, globalGasCap uint64, skipDebitCredit bool) (*core.ExecutionResult, error) {
 	if err := overrides.Apply(state); err != nil {
 		return nil, err
 	}
@@ -932,10 +1090,21 @@ func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash
 	var sysCtx *core.SysContractCallCtx
 	if b.ChainConfig().IsEspresso(header.Number) {
 		sysCtx = core.NewSysContractCallCtx(header, state, b)
+		if err := feeOverrides.Apply(sysCtx); err != nil {
+			return nil, err
+		}
+	} else if feeOverrides != nil {
+		return nil, errors.New("fee currency overrides are not supported before the Espresso fork")
 	}
 
+	// The gas price minimum of the fee currency, which may be overridden, is the
+	// base fee paid by the call
+	baseFee := header.BaseFee
+	if sysCtx != nil && baseFee != nil {
+		baseFee = sysCtx.GetGasPriceMinimum(args.FeeCurrency)
+	}
 	// Get a new instance of the EVM.
-	msg, err := args.ToMessage(globalGasCap, header.BaseFee)
+	msg, err := args.ToMessage(globalGasCap, baseFee)
 	if err != nil {
 		return nil, err
 	}
@@ -1000,12 +1169,13 @@ func (e *revertError) ErrorData() interface{} {
 
 // Call executes the given transaction on the state for the given block number.
 //
-// Additionally, the caller can specify a batch of contract for fields overriding.
+// Additionally, the caller can specify a batch of contract for fields overriding,
+// as well as the fee currency whitelist, gas price minimums and exchange rates.
 //
 // Note, this function doesn't make and changes in the state/blockchain and is
 // useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) (hexutil.Bytes, error) {
-	result, err := DoCall(ctx, s.b, args, blockNrOrHash, overrides, 50*time.Second, s.b.RPCGasCap(), false)
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, feeOverrides *FeeCurrencyOverride) (hexutil.Bytes, error) {
+	result, err := DoCall(ctx, s.b, args, blockNrOrHash, overrides, feeOverrides, 50*time.Second, s.b.RPCGasCap(), false)
 	if err != nil {
 		return nil, err
 	}
@@ -1016,7 +1186,7 @@ func (s *PublicBlockChainAPI) Call(ctx context.Context, args TransactionArgs, bl
 	return result.Return(), result.Err
 }
 
-func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, gasCap uint64) (hexutil.Uint64, error) {
+func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, feeOverrides *FeeCurrencyOverride, gasCap uint64) (hexutil.Uint64, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
 		lo  uint64 = params.TxGas - 1
@@ -1051,7 +1221,10 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 	executable := func(gas uint64) (bool, *core.ExecutionResult, error) {
 		args.Gas = (*hexutil.Uint64)(&gas)
 
-		result, err := DoCall(ctx, b, args, blockNrOrHash, nil, 0, gasCap, true)
+		// The fee overrides are applied to every call. As these are run without
+		// fees, the gas price minimums and exchange rates don't change the
+		// estimate, but invalid ones are still reported
+		result, err := DoCall(ctx, b, args, blockNrOrHash, nil, feeOverrides, 0, gasCap, true)
 		if err != nil {
 			if errors.Is(err, core.ErrIntrinsicGas) {
 				return true, nil, nil // Special case, raise gas limit
@@ -1099,13 +1272,14 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the
-// given transaction against the current pending block.
-func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+// given transaction against the current pending block, optionally under
+// overridden fee currency parameters.
+func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash, feeOverrides *FeeCurrencyOverride) (hexutil.Uint64, error) {
 	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
 	if blockNrOrHash != nil {
 		bNrOrHash = *blockNrOrHash
 	}
-	return DoEstimateGas(ctx, s.b, args, bNrOrHash, s.b.RPCGasCap())
+	return DoEstimateGas(ctx, s.b, args, bNrOrHash, feeOverrides, s.b.RPCGasCap())
 }
 
 // ExecutionResult groups all structured logs emitted by the EVM
@@ -1843,6 +2017,113 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, input
 	return SubmitTransaction(ctx, s.b, tx)
 }
 
+// maxConditionalSlots is the maximum number of storage slots a conditional
+// transaction may check, as they are checked again on every new block.
+const maxConditionalSlots = 1000
+
+// ConditionalOptions are the inclusion conditions of a transaction sent with
+// eth_sendRawTransactionConditional. Unset bounds are not checked.
+type ConditionalOptions struct {
+	KnownAccounts  map[common.Address]map[common.Hash]common.Hash `json:"knownAccounts"`
+	BlockNumberMin *hexutil.Big                                   `json:"blockNumberMin"`
+	BlockNumberMax *hexutil.Big                                   `json:"blockNumberMax"`
+	TimestampMin   *hexutil.Uint64                                `json:"timestampMin"`
+	TimestampMax   *hexutil.Uint64                                `json:"timestampMax"`
+}
+
+// toConditional converts the options into the conditional attached to the
+// transaction.
+func (opts *ConditionalOptions) toConditional() (*types.TransactionConditional, error) {
+	cond := &types.TransactionConditional{
+		KnownAccounts:  opts.KnownAccounts,
+		BlockNumberMin: opts.BlockNumberMin.ToInt(),
+		BlockNumberMax: opts.BlockNumberMax.ToInt(),
+		TimestampMin:   (*uint64)(opts.TimestampMin),
+		TimestampMax:   (*uint64)(opts.TimestampMax),
+	}
+	if slots := cond.Slots(); slots > maxConditionalSlots {
+		return nil, fmt.Errorf("too many conditional storage slots: have %d, max %d", slots, maxConditionalSlots)
+	}
+	if cond.BlockNumberMin != nil && cond.BlockNumberMax != nil && cond.BlockNumberMin.Cmp(cond.BlockNumberMax) > 0 {
+		return nil, errors.New("blockNumberMin is greater than blockNumberMax")
+	}
+	if cond.TimestampMin != nil && cond.TimestampMax != nil && *cond.TimestampMin > *cond.TimestampMax {
+		return nil, errors.New("timestampMin is greater than timestampMax")
+	}
+	return cond, nil
+}
+
+// SendRawTransactionConditional adds the signed transaction to the transaction
+// pool, to be included by the local miner only while the given conditions hold.
+// The transaction is not propagated to other nodes.
+func (s *PublicTransactionPoolAPI) SendRawTransactionConditional(ctx context.Context, input hexutil.Bytes, opts ConditionalOptions) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	cond, err := opts.toConditional()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx.SetConditional(cond)
+	return SubmitTransaction(ctx, s.b, tx)
+}
+
+// maxBundleTxs is the maximum number of transactions in a bundle.
+const maxBundleTxs = 64
+
+// SendBundleArgs is a bundle of signed transactions sent with eth_sendBundle.
+type SendBundleArgs struct {
+	Txs          []hexutil.Bytes `json:"txs"`
+	BlockNumber  hexutil.Uint64  `json:"blockNumber"`
+	MinTimestamp *hexutil.Uint64 `json:"minTimestamp"`
+	MaxTimestamp *hexutil.Uint64 `json:"maxTimestamp"`
+}
+
+// SendBundle submits a bundle of signed transactions to the local miner, to be
+// included in order at the top of the given block, or not at all. Bundles are
+// not propagated to other nodes.
+func (s *PublicTransactionPoolAPI) SendBundle(ctx context.Context, args SendBundleArgs) (common.Hash, error) {
+	if len(args.Txs) == 0 {
+		return common.Hash{}, core.ErrEmptyBundle
+	}
+	if len(args.Txs) > maxBundleTxs {
+		return common.Hash{}, fmt.Errorf("too many bundle transactions: have %d, max %d", len(args.Txs), maxBundleTxs)
+	}
+	bundle := &core.Bundle{
+		Txs:         make(types.Transactions, 0, len(args.Txs)),
+		BlockNumber: new(big.Int).SetUint64(uint64(args.BlockNumber)),
+	}
+	if args.MinTimestamp != nil {
+		bundle.MinTimestamp = uint64(*args.MinTimestamp)
+	}
+	if args.MaxTimestamp != nil {
+		bundle.MaxTimestamp = uint64(*args.MaxTimestamp)
+	}
+	if bundle.MaxTimestamp != 0 && bundle.MinTimestamp > bundle.MaxTimestamp {
+		return common.Hash{}, errors.New("minTimestamp is greater than maxTimestamp")
+	}
+	signer := types.LatestSigner(s.b.ChainConfig())
+	for i, input := range args.Txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			return common.Hash{}, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		if _, err := types.Sender(signer, tx); err != nil {
+			return common.Hash{}, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		if err := checkFeeFromCeloTx(ctx, s.b, tx); err != nil {
+			return common.Hash{}, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		bundle.Txs = append(bundle.Txs, tx)
+	}
+	if err := s.b.SendBundle(ctx, bundle); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted bundle", "hash", bundle.Hash().Hex(), "txs", len(bundle.Txs), "block", bundle.BlockNumber)
+	return bundle.Hash(), nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccack256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
@@ -1,16 +1,120 @@
 package ethapi
 
 import (
+	"context"
 	"errors"
 	"math/big"
 	"testing"
 
 	"github.com/celo-org/celo-blockchain/common"
 	"github.com/celo-org/celo-blockchain/common/hexutil"
+	"github.com/celo-org/celo-blockchain/consensus"
+	mockEngine "github.com/celo-org/celo-blockchain/consensus/consensustest"
+	"github.com/celo-org/celo-blockchain/contracts/config"
+	"github.com/celo-org/celo-blockchain/contracts/currency"
+	"github.com/celo-org/celo-blockchain/contracts/testutil"
+	"github.com/celo-org/celo-blockchain/core"
+	"github.com/celo-org/celo-blockchain/core/rawdb"
+	"github.com/celo-org/celo-blockchain/core/state"
 	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/core/vm"
+	"github.com/celo-org/celo-blockchain/params"
+	"github.com/celo-org/celo-blockchain/rpc"
 	"github.com/stretchr/testify/assert"
 )
 
+// testBackend is a Backend serving a chain consisting of the genesis block
+// only. Methods the tests don't need are left unimplemented and panic.
+type testBackend struct {
+	Backend
+	chain  *core.BlockChain
+	runner vm.EVMRunner // Runner of the system contract calls, the chain's one if nil
+	tip    *big.Int     // Suggested gas tip cap
+	gasCap uint64       // RPC gas cap, 25000000 if zero
+
+	feeCurrencyLimits       map[common.Address]float64
+	defaultFeeCurrencyLimit float64
+
+	pending, queued  int
+	feeCurrencyStats map[common.Address]*core.FeeCurrencyStats
+}
+
+func newTestBackend(t *testing.T, alloc core.GenesisAlloc) *testBackend {
+	config := params.TestChainConfig.DeepCopy()
+	config.Faker = true
+	db := rawdb.NewMemoryDatabase()
+	gspec := &core.Genesis{Config: config, Alloc: alloc}
+	gspec.MustCommit(db)
+	chain, err := core.NewBlockChain(db, nil, config, mockEngine.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	t.Cleanup(chain.Stop)
+	return &testBackend{chain: chain, tip: big.NewInt(10)}
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig { return b.chain.Config() }
+func (b *testBackend) Engine() consensus.Engine         { return b.chain.Engine() }
+func (b *testBackend) RPCGasInflationRate() float64     { return 1 }
+
+func (b *testBackend) RPCGasCap() uint64 {
+	if b.gasCap == 0 {
+		return 25000000
+	}
+	return b.gasCap
+}
+
+func (b *testBackend) SuggestGasTipCap(ctx context.Context, currencyAddress *common.Address) (*big.Int, error) {
+	return new(big.Int).Set(b.tip), nil
+}
+
+func (b *testBackend) Stats() (int, int) { return b.pending, b.queued }
+
+func (b *testBackend) TxPoolFeeCurrencyStats() map[common.Address]*core.FeeCurrencyStats {
+	return b.feeCurrencyStats
+}
+
+func (b *testBackend) FeeCurrencyLimits() (map[common.Address]float64, float64) {
+	return b.feeCurrencyLimits, b.defaultFeeCurrencyLimit
+}
+
+func (b *testBackend) GetBlockGasLimit(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) uint64 {
+	return params.DefaultGasLimit
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number < 0 {
+		return b.chain.CurrentHeader(), nil
+	}
+	return b.chain.GetHeaderByNumber(uint64(number)), nil
+}
+
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	header := b.chain.CurrentHeader()
+	if number, ok := blockNrOrHash.Number(); ok && number >= 0 {
+		header = b.chain.GetHeaderByNumber(uint64(number))
+	} else if hash, ok := blockNrOrHash.Hash(); ok {
+		header = b.chain.GetHeaderByHash(hash)
+	}
+	if header == nil {
+		return nil, nil, errors.New("header not found")
+	}
+	statedb, err := b.chain.StateAt(header.Root)
+	return statedb, header, err
+}
+
+func (b *testBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
+	context := core.NewEVMBlockContext(header, b.chain, nil)
+	return vm.NewEVM(context, core.NewEVMTxContext(msg), state, b.chain.Config(), *vmConfig), func() error { return nil }, nil
+}
+
+func (b *testBackend) NewEVMRunner(header *types.Header, state vm.StateDB) vm.EVMRunner {
+	if b.runner != nil {
+		return b.runner
+	}
+	return b.chain.NewEVMRunner(header, state)
+}
+
 // TestNewRPCTransactionCeloDynamicV2 tests the newRPCTransaction method with a celo dynamic fee tx v2 type.
 func TestNewRPCTransactionCeloDynamicV2(t *testing.T) {
 	currency := common.HexToAddress("0xCAFE")
@@ -159,3 +263,427 @@ func TestNewRPCTransactionDynamic(t *testing.T) {
 		assert.Equal(t, (*hexutil.Big)(bigFeeCap), rpcTx.GasPrice)
 	})
 }
+
+func TestTxPoolStatus(t *testing.T) {
+	cusd := common.HexToAddress("0xCAFE")
+	backend := newTestBackend(t, nil)
+	backend.pending, backend.queued = 3, 1
+	api := NewPublicTxPoolAPI(backend)
+
+	// Pools without fee currency stats only report the totals
+	status := api.Status()
+	assert.Equal(t, hexutil.Uint(3), status.Pending)
+	assert.Equal(t, hexutil.Uint(1), status.Queued)
+	assert.Nil(t, status.FeeCurrencies)
+
+	backend.feeCurrencyStats = map[common.Address]*core.FeeCurrencyStats{
+		common.ZeroAddress: {Pending: 3, CheapestTip: big.NewInt(10), MedianTip: big.NewInt(20), GasPriceMinimum: big.NewInt(100)},
+		cusd:               {Queued: 1, GasPriceMinimum: big.NewInt(200)},
+	}
+	status = api.Status()
+	assert.Len(t, status.FeeCurrencies, 2)
+	assert.Equal(t, &TxPoolFeeCurrencyStatus{
+		Pending:         3,
+		CheapestTip:     (*hexutil.Big)(big.NewInt(10)),
+		MedianTip:       (*hexutil.Big)(big.NewInt(20)),
+		GasPriceMinimum: (*hexutil.Big)(big.NewInt(100)),
+	}, status.FeeCurrencies[common.ZeroAddress.Hex()])
+	assert.Equal(t, &TxPoolFeeCurrencyStatus{
+		Queued:          1,
+		GasPriceMinimum: (*hexutil.Big)(big.NewInt(200)),
+	}, status.FeeCurrencies[cusd.Hex()])
+}
+
+func TestFeeCurrencyOverride(t *testing.T) {
+	var (
+		stable   = common.HexToAddress("0x765DE816845861e75A25fCA122bb6898B8B1282a")
+		unlisted = common.HexToAddress("0xD8763CBa276a3738E6DE85b4b3bF5FDed6D6cA73")
+	)
+	sysCtx := core.MockSysContractCallCtx(big.NewInt(100))
+	overrides := &FeeCurrencyOverride{
+		Whitelist: &[]common.Address{stable},
+		GasPriceMinimums: map[common.Address]*hexutil.Big{
+			common.ZeroAddress: (*hexutil.Big)(big.NewInt(200)),
+			stable:             (*hexutil.Big)(big.NewInt(400)),
+		},
+		ExchangeRates: map[common.Address]*ExchangeRateOverride{
+			stable: {Numerator: (*hexutil.Big)(big.NewInt(2)), Denominator: (*hexutil.Big)(big.NewInt(1))},
+		},
+	}
+	assert.NoError(t, overrides.Apply(sysCtx))
+
+	assert.True(t, sysCtx.IsWhitelisted(&stable))
+	assert.False(t, sysCtx.IsWhitelisted(&unlisted))
+	assert.Equal(t, big.NewInt(200), sysCtx.GetGasPriceMinimum(nil))
+	assert.Equal(t, big.NewInt(400), sysCtx.GetGasPriceMinimum(&stable))
+
+	rate, err := sysCtx.GetExchangeRate(nil, &stable)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(50), rate.ToBase(big.NewInt(100)))
+
+	// Exchange rates must be positive and can't be set for CELO
+	invalid := &FeeCurrencyOverride{ExchangeRates: map[common.Address]*ExchangeRateOverride{
+		stable: {Numerator: (*hexutil.Big)(big.NewInt(0)), Denominator: (*hexutil.Big)(big.NewInt(1))},
+	}}
+	assert.Error(t, invalid.Apply(sysCtx))
+	invalid = &FeeCurrencyOverride{ExchangeRates: map[common.Address]*ExchangeRateOverride{
+		common.ZeroAddress: {Numerator: (*hexutil.Big)(big.NewInt(1)), Denominator: (*hexutil.Big)(big.NewInt(1))},
+	}}
+	assert.Error(t, invalid.Apply(sysCtx))
+}
+
+func TestSimulatedHeader(t *testing.T) {
+	parent := &types.Header{
+		Number:   big.NewInt(10),
+		Time:     1000,
+		Coinbase: common.HexToAddress("0x01"),
+		GasLimit: 20000000,
+		BaseFee:  big.NewInt(5),
+	}
+	config := params.TestChainConfig
+
+	header, err := simulatedHeader(config, parent, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, parent.Hash(), header.ParentHash)
+	assert.Equal(t, big.NewInt(11), header.Number)
+	assert.Equal(t, uint64(1001), header.Time)
+	assert.Equal(t, parent.Coinbase, header.Coinbase)
+	assert.Equal(t, parent.BaseFee, header.BaseFee)
+
+	number, time, recipient := hexutil.Big(*big.NewInt(20)), hexutil.Uint64(2000), common.HexToAddress("0x02")
+	header, err = simulatedHeader(config, parent, &BlockOverrides{
+		Number:        &number,
+		Time:          &time,
+		FeeRecipient:  &recipient,
+		BaseFeePerGas: (*hexutil.Big)(big.NewInt(7)),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(20), header.Number)
+	assert.Equal(t, uint64(2000), header.Time)
+	assert.Equal(t, recipient, header.Coinbase)
+	assert.Equal(t, big.NewInt(7), header.BaseFee)
+
+	// Blocks must move forward
+	number, time = hexutil.Big(*big.NewInt(10)), hexutil.Uint64(1000)
+	_, err = simulatedHeader(config, parent, &BlockOverrides{Number: &number})
+	assert.Error(t, err)
+	_, err = simulatedHeader(config, parent, &BlockOverrides{Time: &time})
+	assert.Error(t, err)
+}
+
+// balanceProbe returns the code of a contract returning the CELO balance of
+// the given account.
+func balanceProbe(account common.Address) hexutil.Bytes {
+	code := append([]byte{byte(vm.PUSH20)}, account.Bytes()...)
+	return append(code, byte(vm.BALANCE), byte(vm.PUSH1), 0, byte(vm.MSTORE), byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN))
+}
+
+func TestSimulateV1(t *testing.T) {
+	var (
+		from      = common.HexToAddress("0x1234")
+		to        = common.HexToAddress("0x5678")
+		other     = common.HexToAddress("0x9abc")
+		recipient = common.HexToAddress("0xdef0")
+		probeFrom = common.HexToAddress("0xaa")
+		probeFee  = common.HexToAddress("0xbb")
+		funds     = big.NewInt(params.Ether)
+		fromCode  = balanceProbe(from)
+		feeCode   = balanceProbe(recipient)
+	)
+	backend := newTestBackend(t, core.GenesisAlloc{from: {Balance: funds}})
+	api := NewPublicBlockChainAPI(backend)
+
+	// The first block sends value with a tip to the fee recipient, the second
+	// spends the received value and reads the balances left by the first
+	opts := SimOpts{BlockStateCalls: []SimBlock{
+		{
+			BlockOverrides: &BlockOverrides{FeeRecipient: &recipient},
+			StateOverrides: &StateOverride{
+				probeFrom: {Code: &fromCode},
+				probeFee:  {Code: &feeCode},
+			},
+			Calls: []TransactionArgs{{
+				From:                 &from,
+				To:                   &to,
+				Value:                (*hexutil.Big)(big.NewInt(1000)),
+				MaxFeePerGas:         (*hexutil.Big)(big.NewInt(550)),
+				MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(100)),
+			}},
+		},
+		{
+			Calls: []TransactionArgs{
+				{From: &to, To: &other, Value: (*hexutil.Big)(big.NewInt(400))},
+				{To: &probeFrom},
+				{To: &probeFee},
+			},
+		},
+	}}
+	results, err := api.SimulateV1(context.Background(), opts, nil)
+	if !assert.NoError(t, err) || !assert.Len(t, results, 2) {
+		return
+	}
+	assert.Equal(t, results[0].Number+1, results[1].Number)
+	assert.Equal(t, recipient, results[1].FeeRecipient)
+	for i, block := range results {
+		for j, call := range block.Calls {
+			assert.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), call.Status, "block %d call %d: %s", i, j, call.Error)
+		}
+	}
+	assert.Equal(t, hexutil.Uint64(params.TxGas), results[0].GasUsed)
+
+	// The sender paid the value and the tip, which the fee recipient received
+	paid := new(big.Int).SetBytes(results[1].Calls[1].ReturnData)
+	tips := new(big.Int).SetBytes(results[1].Calls[2].ReturnData)
+	assert.Equal(t, 1, tips.Sign())
+	assert.Equal(t, new(big.Int).Sub(funds, big.NewInt(1000)), new(big.Int).Add(paid, tips))
+}
+
+// feeCurrencyCode is the code of a fee currency token keeping the balance of
+// each account in the storage slot of its address. It implements
+// debitGasFees, creditGasFees (refunding the sender only) and falls back to
+// balanceOf for any other call.
+var feeCurrencyCode = hexutil.MustDecode("0x60003560e01c806358cf96721460265780636a30b253146034576004355460005260206000f35b602435600435540360043555005b60843560043554016004355500")
+
+// newFeeCurrencyBackend returns a test backend whitelisting a fee currency worth
+// half a CELO, in which the given account holds the given funds.
+func newFeeCurrencyBackend(t *testing.T, from common.Address, funds *big.Int) (*testBackend, common.Address) {
+	var (
+		celo    = testutil.NewCeloMock()
+		oracles = testutil.NewSortedOraclesMock()
+		stable  = common.HexToAddress("0x0c")
+	)
+	celo.FeeCurrencyWhitelist.Whitelist = []common.Address{stable}
+	celo.Runner.RegisterContract(stable, celo.ERC20Token)
+	celo.Registry.AddContract(config.SortedOraclesRegistryId, common.HexToAddress("0x06"))
+	celo.Runner.RegisterContract(common.HexToAddress("0x06"), oracles)
+	oracles.Rates[stable] = [2]*big.Int{big.NewInt(2), big.NewInt(1)}
+
+	backend := newTestBackend(t, core.GenesisAlloc{
+		stable: {Balance: common.Big0, Code: feeCurrencyCode, Storage: map[common.Hash]common.Hash{from.Hash(): common.BigToHash(funds)}},
+	})
+	backend.runner = celo.Runner
+	return backend, stable
+}
+
+func TestSimulateV1FeeCurrency(t *testing.T) {
+	var (
+		from    = common.HexToAddress("0x1234")
+		to      = common.HexToAddress("0x5678")
+		funds   = big.NewInt(params.Ether)
+		gas     = hexutil.Uint64(100000)
+		baseFee = big.NewInt(100)
+	)
+	backend, stable := newFeeCurrencyBackend(t, from, funds)
+	api := NewPublicBlockChainAPI(backend)
+
+	// The sender pays in the fee currency, the second block reads the sender's
+	// token balance. Balance checks go through the mocked token, debits and
+	// credits through the token code.
+	balanceOf := hexutil.Bytes(common.GetEncodedAbi(hexutil.MustDecode("0x70a08231"), [][]byte{common.AddressToAbi(from)}))
+	opts := SimOpts{BlockStateCalls: []SimBlock{
+		{BlockOverrides: &BlockOverrides{BaseFeePerGas: (*hexutil.Big)(baseFee)}, Calls: []TransactionArgs{{
+			From:                 &from,
+			To:                   &to,
+			Gas:                  &gas,
+			FeeCurrency:          &stable,
+			MaxFeePerGas:         (*hexutil.Big)(big.NewInt(250)),
+			MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(100)),
+		}}},
+		{Calls: []TransactionArgs{{To: &stable, Data: &balanceOf}}},
+	}}
+	results, err := api.SimulateV1(context.Background(), opts, nil)
+	if !assert.NoError(t, err) || !assert.Len(t, results, 2) {
+		return
+	}
+	for i, block := range results {
+		for j, call := range block.Calls {
+			assert.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), call.Status, "block %d call %d: %s", i, j, call.Error)
+		}
+	}
+	// The gas is priced at the gas price minimum of the fee currency, twice the
+	// CELO one at the mocked rate, plus what's left of the tip within the fee
+	// cap. Without a fee handler the gas price minimum share is refunded, so
+	// the sender pays the tip only.
+	rate, _ := currency.NewExchangeRate(big.NewInt(2), big.NewInt(1))
+	tip := new(big.Int).Sub(big.NewInt(250), rate.FromBase(baseFee))
+	debited := new(big.Int).Mul(new(big.Int).SetUint64(uint64(results[0].GasUsed)), tip)
+	balance := new(big.Int).SetBytes(results[1].Calls[0].ReturnData)
+	assert.Equal(t, new(big.Int).Sub(funds, debited), balance)
+}
+
+func TestSimulateBlockBaseFeeOverride(t *testing.T) {
+	var (
+		from    = common.HexToAddress("0x1234")
+		to      = common.HexToAddress("0x5678")
+		funds   = big.NewInt(params.Ether)
+		gas     = hexutil.Uint64(params.TxGas)
+		baseFee = big.NewInt(100)
+		latest  = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	)
+	backend := newTestBackend(t, core.GenesisAlloc{from: {Balance: funds}})
+	state, parent, err := backend.StateAndHeaderByNumberOrHash(context.Background(), latest)
+	if !assert.NoError(t, err) {
+		return
+	}
+	overrides := &BlockOverrides{BaseFeePerGas: (*hexutil.Big)(baseFee)}
+	header, err := simulatedHeader(backend.ChainConfig(), parent, overrides)
+	if !assert.NoError(t, err) {
+		return
+	}
+	calls := []TransactionArgs{{
+		From:                 &from,
+		To:                   &to,
+		Gas:                  &gas,
+		MaxFeePerGas:         (*hexutil.Big)(big.NewInt(250)),
+		MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(200)),
+	}}
+	gp := new(core.GasPool).AddGas(backend.RPCGasCap())
+	result, err := simulateBlock(context.Background(), backend, state, header, overrides, calls, gp)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), result.Calls[0].Status, result.Calls[0].Error)
+	assert.Equal(t, (*hexutil.Big)(baseFee), result.BaseFeePerGas)
+
+	// The gas price is the overridden base fee plus what's left of the tip
+	// within the fee cap. Without a fee handler the base fee share is refunded,
+	// so the sender pays the tip only.
+	debited := new(big.Int).Mul(new(big.Int).SetUint64(params.TxGas), big.NewInt(250-100))
+	assert.Equal(t, new(big.Int).Sub(funds, debited), state.GetBalance(from))
+}
+
+func TestSimulateV1GasBudget(t *testing.T) {
+	var (
+		from = common.HexToAddress("0x1234")
+		to   = common.HexToAddress("0x5678")
+		gas  = hexutil.Uint64(params.TxGas)
+	)
+	backend := newTestBackend(t, core.GenesisAlloc{from: {Balance: big.NewInt(params.Ether)}})
+	backend.gasCap = params.TxGas + params.TxGas/2
+	api := NewPublicBlockChainAPI(backend)
+
+	transfer := SimBlock{Calls: []TransactionArgs{{From: &from, To: &to, Gas: &gas}}}
+	_, err := api.SimulateV1(context.Background(), SimOpts{BlockStateCalls: []SimBlock{transfer}}, nil)
+	assert.NoError(t, err)
+
+	// The second block can't use the gas the first one did, its call is capped
+	// to what's left of the budget
+	_, err = api.SimulateV1(context.Background(), SimOpts{BlockStateCalls: []SimBlock{transfer, transfer}}, nil)
+	assert.True(t, errors.Is(err, core.ErrIntrinsicGas), "have %v, want %v", err, core.ErrIntrinsicGas)
+
+	// Nothing is left once the budget is used up
+	backend.gasCap = params.TxGas
+	_, err = api.SimulateV1(context.Background(), SimOpts{BlockStateCalls: []SimBlock{transfer, transfer}}, nil)
+	assert.True(t, errors.Is(err, core.ErrGasLimitReached), "have %v, want %v", err, core.ErrGasLimitReached)
+}
+
+func TestDoCallFeeCurrencyOverride(t *testing.T) {
+	var (
+		from   = common.HexToAddress("0x1234")
+		to     = common.HexToAddress("0x5678")
+		gas    = hexutil.Uint64(params.TxGas)
+		latest = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	)
+	backend := newTestBackend(t, core.GenesisAlloc{from: {Balance: big.NewInt(params.Ether)}})
+	args := TransactionArgs{
+		From:                 &from,
+		To:                   &to,
+		Gas:                  &gas,
+		MaxFeePerGas:         (*hexutil.Big)(big.NewInt(550)),
+		MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(100)),
+	}
+	// call runs the call with the given CELO gas price minimum and returns the
+	// amount taken from the sender
+	call := func(gasPriceMinimum int64) (*big.Int, error) {
+		state, header, err := backend.StateAndHeaderByNumberOrHash(context.Background(), latest)
+		assert.NoError(t, err)
+		before := state.GetBalance(from)
+
+		feeOverrides := &FeeCurrencyOverride{GasPriceMinimums: map[common.Address]*hexutil.Big{
+			common.ZeroAddress: (*hexutil.Big)(big.NewInt(gasPriceMinimum)),
+		}}
+		result, err := doCall(context.Background(), backend, args, state, header, nil, feeOverrides, 0, backend.RPCGasCap(), false)
+		if err != nil {
+			return nil, err
+		}
+		assert.NoError(t, result.Err)
+		return new(big.Int).Sub(before, state.GetBalance(from)), nil
+	}
+	// The gas price is the gas price minimum plus the tip, within the fee cap.
+	// Without a fee handler the gas price minimum share is refunded, so the
+	// sender pays the tip only.
+	debited, err := call(100)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(int64(params.TxGas)*100), debited)
+
+	debited, err = call(500)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(int64(params.TxGas)*50), debited)
+
+	// A gas price minimum above the fee cap rejects the call
+	_, err = call(600)
+	assert.Error(t, err)
+}
+
+func TestDoCallFeeCurrency(t *testing.T) {
+	var (
+		from   = common.HexToAddress("0x1234")
+		to     = common.HexToAddress("0x5678")
+		funds  = big.NewInt(params.Ether)
+		gas    = hexutil.Uint64(100000)
+		latest = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	)
+	backend, stable := newFeeCurrencyBackend(t, from, funds)
+	state, header, err := backend.StateAndHeaderByNumberOrHash(context.Background(), latest)
+	if !assert.NoError(t, err) {
+		return
+	}
+	header = types.CopyHeader(header)
+	header.BaseFee = big.NewInt(100)
+
+	args := TransactionArgs{
+		From:                 &from,
+		To:                   &to,
+		Gas:                  &gas,
+		FeeCurrency:          &stable,
+		MaxFeePerGas:         (*hexutil.Big)(big.NewInt(250)),
+		MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(100)),
+	}
+	result, err := doCall(context.Background(), backend, args, state, header, nil, nil, 0, backend.RPCGasCap(), false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, result.Err)
+
+	// The gas is priced at the gas price minimum of the fee currency, twice the
+	// base fee at the mocked rate, rather than at the base fee itself. Without
+	// a fee handler the gas price minimum share is refunded, so the sender pays
+	// what's left of the tip within the fee cap.
+	debited := new(big.Int).Mul(new(big.Int).SetUint64(result.UsedGas), big.NewInt(250-200))
+	balance := state.GetState(stable, from.Hash()).Big()
+	assert.Equal(t, new(big.Int).Sub(funds, debited), balance)
+}
+
+func TestDoEstimateGasFeeOverrides(t *testing.T) {
+	backend := newTestBackend(t, nil)
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	to := common.HexToAddress("0x5678")
+
+	gas, err := DoEstimateGas(context.Background(), backend, TransactionArgs{To: &to}, latest, &FeeCurrencyOverride{
+		Whitelist:        &[]common.Address{},
+		GasPriceMinimums: map[common.Address]*hexutil.Big{common.ZeroAddress: (*hexutil.Big)(big.NewInt(1))},
+		ExchangeRates: map[common.Address]*ExchangeRateOverride{
+			common.HexToAddress("0x02"): {Numerator: (*hexutil.Big)(big.NewInt(1)), Denominator: (*hexutil.Big)(big.NewInt(1))},
+		},
+	}, backend.RPCGasCap())
+	assert.NoError(t, err)
+	assert.Equal(t, hexutil.Uint64(params.TxGas), gas)
+
+	// Invalid overrides are rejected
+	_, err = DoEstimateGas(context.Background(), backend, TransactionArgs{To: &to}, latest, &FeeCurrencyOverride{
+		ExchangeRates: map[common.Address]*ExchangeRateOverride{
+			common.ZeroAddress: {Numerator: (*hexutil.Big)(big.NewInt(1)), Denominator: (*hexutil.Big)(big.NewInt(1))},
+		},
+	}, backend.RPCGasCap())
+	assert.Error(t, err)
+}
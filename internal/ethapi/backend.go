@@ -20,6 +20,7 @@ package ethapi
 import (
 	"context"
 	"math/big"
+	"time"
 
 	ethereum "github.com/celo-org/celo-blockchain"
 	"github.com/celo-org/celo-blockchain/accounts"
@@ -49,6 +50,7 @@ type Backend interface {
 	// some cases when it can't retrieve the gas price minimum, this function
 	// returns an error and no gas price minimum when it encounters a problem.
 	RealGasPriceMinimumForHeader(ctx context.Context, currencyAddress *common.Address, header *types.Header) (*big.Int, error)
+	FeeHistory(ctx context.Context, blockCount int, lastBlock rpc.BlockNumber, rewardPercentiles []float64, feeCurrency *common.Address) (*big.Int, [][]*big.Int, []*big.Int, []float64, error)
 	SyncProgress() ethereum.SyncProgress
 
 	ChainDb() ethdb.Database
@@ -81,6 +83,7 @@ type Backend interface {
 
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
+	SendBundle(ctx context.Context, bundle *core.Bundle) error
 	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error)
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
@@ -88,6 +91,11 @@ type Backend interface {
 	Stats() (pending int, queued int)
 	TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
 	TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions)
+	// TxPoolFeeCurrencyStats returns the pool content stats per fee currency, or
+	// nil if the pool doesn't track them.
+	TxPoolFeeCurrencyStats() map[common.Address]*core.FeeCurrencyStats
+	TxPoolRemoveTransaction(hash common.Hash) bool
+	TxPoolEvictAccount(addr common.Address, ban time.Duration) (int, error)
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
 
 	// Filter API
@@ -102,6 +110,10 @@ type Backend interface {
 
 	GatewayFeeRecipient() common.Address
 	GatewayFee() *big.Int
+	// FeeCurrencyLimits returns the fractions of the block gas limit that fee
+	// currencies may use in blocks built by this node, along with the fraction
+	// applied to currencies without a configured limit.
+	FeeCurrencyLimits() (map[common.Address]float64, float64)
 	GetIntrinsicGasForAlternativeFeeCurrency(ctx context.Context) uint64
 	GetBlockGasLimit(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) uint64
 
@@ -131,11 +143,21 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicTransactionPoolAPI(apiBackend, nonceLock),
 			Public:    true,
+		}, {
+			Namespace: "celo",
+			Version:   "1.0",
+			Service:   NewPublicCeloAPI(apiBackend),
+			Public:    true,
 		}, {
 			Namespace: "txpool",
 			Version:   "1.0",
 			Service:   NewPublicTxPoolAPI(apiBackend),
 			Public:    true,
+		}, {
+			Namespace: "txpool",
+			Version:   "1.0",
+			Service:   NewPrivateTxPoolAPI(apiBackend),
+			Public:    false,
 		}, {
 			Namespace: "debug",
 			Version:   "1.0",
@@ -0,0 +1,277 @@
+// Copyright 2017 The Celo Authors
+// This file is part of the celo library.
+//
+// The celo library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The celo library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the celo library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/common/hexutil"
+	"github.com/celo-org/celo-blockchain/contracts/currency"
+	"github.com/celo-org/celo-blockchain/core"
+	gp "github.com/celo-org/celo-blockchain/eth/gasprice"
+	"github.com/celo-org/celo-blockchain/rpc"
+)
+
+// PublicCeloAPI provides an API to access Celo specific fee and currency
+// information.
+type PublicCeloAPI struct {
+	b Backend
+}
+
+// NewPublicCeloAPI creates a new Celo protocol API.
+func NewPublicCeloAPI(b Backend) *PublicCeloAPI {
+	return &PublicCeloAPI{b}
+}
+
+// FeeEstimate is the cost breakdown of a transaction. All prices and the
+// maximum cost are denominated in the fee currency, MaxCostInCelo holds the
+// same maximum cost converted to CELO.
+type FeeEstimate struct {
+	FeeCurrency          *common.Address `json:"feeCurrency"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	IntrinsicGasForFee   hexutil.Uint64  `json:"intrinsicGasForFeeCurrency"`
+	BaseFeePerGas        *hexutil.Big    `json:"baseFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	GatewayFee           *hexutil.Big    `json:"gatewayFee,omitempty"`
+	MaxCost              *hexutil.Big    `json:"maxCost"`
+	MaxCostInCelo        *hexutil.Big    `json:"maxCostInCelo"`
+}
+
+// EstimateFee returns the gas limit, fee prices and maximum cost of the given
+// transaction in its fee currency, as well as the maximum cost in CELO. The
+// gas limit already includes the intrinsic gas charged for paying fees in a
+// non-native currency. Prices the caller leaves unset are filled in the same
+// way eth_sendTransaction does.
+func (s *PublicCeloAPI) EstimateFee(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (*FeeEstimate, error) {
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, bNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	sysCtx := core.NewSysContractCallCtx(header, state, s.b)
+	if !sysCtx.IsWhitelisted(args.FeeCurrency) {
+		return nil, fmt.Errorf("%w: %s", core.ErrNonWhitelistedFeeCurrency, args.FeeCurrency.Hex())
+	}
+	feeCurrency, err := currency.NewManager(s.b.NewEVMRunner(header, state.Copy())).GetCurrency(args.FeeCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	gas := args.Gas
+	if gas == nil {
+		estimate, err := DoEstimateGas(ctx, s.b, args, bNrOrHash, nil, s.b.RPCGasCap())
+		if err != nil {
+			return nil, err
+		}
+		gas = &estimate
+	}
+	var intrinsicGas uint64
+	if args.FeeCurrency != nil {
+		intrinsicGas = sysCtx.GetIntrinsicGasForAlternativeFeeCurrency()
+	}
+	tip := args.MaxPriorityFeePerGas.ToInt()
+	if tip == nil && args.GasPrice == nil {
+		if tip, err = s.b.SuggestGasTipCap(ctx, args.FeeCurrency); err != nil {
+			return nil, err
+		}
+	}
+	return newFeeEstimate(args, uint64(*gas), intrinsicGas, sysCtx.GetGasPriceMinimum(args.FeeCurrency), tip, feeCurrency), nil
+}
+
+// newFeeEstimate fills in the fee prices of args that are left unset and
+// computes the maximum cost of a transaction spending at most gas. A legacy
+// gas price is used as the fee cap and pays whatever exceeds the base fee as
+// tip. An explicit fee cap limits the tip to what exceeds the base fee, and a
+// missing one defaults to the tip plus twice the base fee.
+func newFeeEstimate(args TransactionArgs, gas, intrinsicGas uint64, baseFee, tip *big.Int, feeCurrency *currency.Currency) *FeeEstimate {
+	var feeCap *big.Int
+	switch {
+	case args.GasPrice != nil:
+		feeCap = args.GasPrice.ToInt()
+		tip = new(big.Int).Sub(feeCap, baseFee)
+		if tip.Sign() < 0 {
+			tip.SetUint64(0)
+		}
+	case args.MaxFeePerGas != nil:
+		feeCap = args.MaxFeePerGas.ToInt()
+		// Only what the fee cap leaves over the base fee can be paid as tip
+		if maxTip := new(big.Int).Sub(feeCap, baseFee); tip.Cmp(maxTip) > 0 {
+			if maxTip.Sign() < 0 {
+				maxTip.SetUint64(0)
+			}
+			tip = maxTip
+		}
+	default:
+		feeCap = new(big.Int).Add(tip, new(big.Int).Mul(baseFee, big.NewInt(2)))
+	}
+	maxCost := new(big.Int).Mul(feeCap, new(big.Int).SetUint64(gas))
+	if args.GatewayFee != nil {
+		maxCost.Add(maxCost, args.GatewayFee.ToInt())
+	}
+	return &FeeEstimate{
+		FeeCurrency:          args.FeeCurrency,
+		Gas:                  hexutil.Uint64(gas),
+		IntrinsicGasForFee:   hexutil.Uint64(intrinsicGas),
+		BaseFeePerGas:        (*hexutil.Big)(baseFee),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tip),
+		MaxFeePerGas:         (*hexutil.Big)(feeCap),
+		GatewayFee:           args.GatewayFee,
+		MaxCost:              (*hexutil.Big)(maxCost),
+		MaxCostInCelo:        (*hexutil.Big)(feeCurrency.ToCELO(maxCost)),
+	}
+}
+
+// FeeCurrencyExchangeRate is the price of one CELO in a fee currency,
+// expressed as Numerator / Denominator token units.
+type FeeCurrencyExchangeRate struct {
+	Numerator   *hexutil.Big `json:"numerator"`
+	Denominator *hexutil.Big `json:"denominator"`
+}
+
+// FeeCurrencyInfo describes a currency that can be used to pay transaction
+// fees. Symbol and Decimals are omitted when the token does not expose them.
+type FeeCurrencyInfo struct {
+	Address               common.Address           `json:"address"`
+	Symbol                string                   `json:"symbol,omitempty"`
+	Decimals              *hexutil.Uint            `json:"decimals,omitempty"`
+	ExchangeRate          *FeeCurrencyExchangeRate `json:"exchangeRate"`
+	GasPriceMinimum       *hexutil.Big             `json:"gasPriceMinimum"`
+	BlockGasLimitFraction float64                  `json:"blockGasLimitFraction"`
+}
+
+// GetFeeCurrencies returns every whitelisted fee currency at the given block
+// together with its token metadata, exchange rate to CELO and gas price
+// minimum. The block gas limit fraction is the limit this node applies to the
+// currency when building blocks.
+func (s *PublicCeloAPI) GetFeeCurrencies(ctx context.Context, blockNrOrHash *rpc.BlockNumberOrHash) ([]*FeeCurrencyInfo, error) {
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, bNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	vmRunner := s.b.NewEVMRunner(header, state)
+	limits, defaultLimit := s.b.FeeCurrencyLimits()
+
+	whitelist, err := currency.CurrencyWhitelist(vmRunner)
+	if err != nil {
+		return nil, err
+	}
+	currencies := make([]*FeeCurrencyInfo, 0, len(whitelist))
+	for _, address := range whitelist {
+		address := address
+		exchangeRate, err := currency.GetExchangeRate(vmRunner, &address)
+		if err != nil {
+			return nil, err
+		}
+		info := &FeeCurrencyInfo{
+			Address: address,
+			ExchangeRate: &FeeCurrencyExchangeRate{
+				Numerator:   (*hexutil.Big)(exchangeRate.Numerator()),
+				Denominator: (*hexutil.Big)(exchangeRate.Denominator()),
+			},
+			BlockGasLimitFraction: defaultLimit,
+		}
+		gasPriceMinimum, err := gp.GetBaseFeeForCurrency(vmRunner, &address, header.BaseFee)
+		if err != nil {
+			return nil, err
+		}
+		info.GasPriceMinimum = (*hexutil.Big)(gasPriceMinimum)
+		if symbol, err := currency.GetSymbol(vmRunner, address); err == nil {
+			info.Symbol = symbol
+		}
+		if decimals, err := currency.GetDecimals(vmRunner, address); err == nil {
+			d := hexutil.Uint(decimals)
+			info.Decimals = &d
+		}
+		if fraction, ok := limits[address]; ok {
+			info.BlockGasLimitFraction = fraction
+		}
+		currencies = append(currencies, info)
+	}
+	return currencies, nil
+}
+
+// TokenBalance is the balance an account holds in an ERC20 token. Error is
+// set instead of Balance when the token could not be queried.
+type TokenBalance struct {
+	Token   common.Address `json:"token"`
+	Balance *hexutil.Big   `json:"balance,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// Balances holds the CELO balance of an account along with its balances in
+// every whitelisted fee currency and any additionally requested token.
+type Balances struct {
+	Celo   *hexutil.Big   `json:"celo"`
+	Tokens []TokenBalance `json:"tokens"`
+}
+
+// maxExtraBalanceTokens is the maximum number of extra tokens celo_getBalances
+// queries, each of which is an EVM call.
+const maxExtraBalanceTokens = 100
+
+// GetBalances returns the CELO balance of the given address together with its
+// balance in every whitelisted fee currency, followed by the balances in the
+// extra ERC20 tokens, all read from the state of a single block.
+func (s *PublicCeloAPI) GetBalances(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash, extraTokens *[]common.Address) (*Balances, error) {
+	if extraTokens != nil && len(*extraTokens) > maxExtraBalanceTokens {
+		return nil, fmt.Errorf("too many extra tokens: %d, max %d", len(*extraTokens), maxExtraBalanceTokens)
+	}
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	balances := &Balances{Celo: (*hexutil.Big)(state.GetBalance(address))}
+	if err := state.Error(); err != nil {
+		return nil, err
+	}
+	vmRunner := s.b.NewEVMRunner(header, state)
+
+	tokens, err := currency.CurrencyWhitelist(vmRunner)
+	if err != nil {
+		return nil, err
+	}
+	if extraTokens != nil {
+		tokens = append(tokens, *extraTokens...)
+	}
+	seen := make(map[common.Address]struct{}, len(tokens))
+	for _, token := range tokens {
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		seen[token] = struct{}{}
+
+		balance := TokenBalance{Token: token}
+		if amount, err := currency.GetTokenBalanceOf(vmRunner, address, token); err != nil {
+			balance.Error = err.Error()
+		} else {
+			balance.Balance = (*hexutil.Big)(amount)
+		}
+		balances.Tokens = append(balances.Tokens, balance)
+	}
+	return balances, nil
+}
@@ -0,0 +1,193 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/common/hexutil"
+	"github.com/celo-org/celo-blockchain/contracts/config"
+	"github.com/celo-org/celo-blockchain/contracts/currency"
+	"github.com/celo-org/celo-blockchain/contracts/testutil"
+	"github.com/celo-org/celo-blockchain/core"
+	"github.com/celo-org/celo-blockchain/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFeeEstimate(t *testing.T) {
+	feeCurrency := common.HexToAddress("0xCAFE")
+	exchangeRate, _ := currency.NewExchangeRate(big.NewInt(2), big.NewInt(1))
+	cusd := currency.NewCurrency(feeCurrency, *exchangeRate)
+	baseFee := big.NewInt(100)
+	tip := big.NewInt(10)
+
+	t.Run("Default fee cap is tip plus twice the base fee", func(t *testing.T) {
+		args := TransactionArgs{FeeCurrency: &feeCurrency}
+		estimate := newFeeEstimate(args, 71000, 50000, baseFee, tip, cusd)
+		assert.Equal(t, hexutil.Uint64(71000), estimate.Gas)
+		assert.Equal(t, hexutil.Uint64(50000), estimate.IntrinsicGasForFee)
+		assert.Equal(t, (*hexutil.Big)(big.NewInt(210)), estimate.MaxFeePerGas)
+		assert.Equal(t, (*hexutil.Big)(big.NewInt(210*71000)), estimate.MaxCost)
+		assert.Equal(t, (*hexutil.Big)(big.NewInt(105*71000)), estimate.MaxCostInCelo)
+	})
+
+	t.Run("Explicit fee cap limits the tip", func(t *testing.T) {
+		args := TransactionArgs{MaxFeePerGas: (*hexutil.Big)(big.NewInt(104))}
+		estimate := newFeeEstimate(args, 21000, 0, baseFee, tip, &currency.CELOCurrency)
+		assert.Equal(t, (*hexutil.Big)(big.NewInt(4)), estimate.MaxPriorityFeePerGas)
+		assert.Equal(t, big.NewInt(10), tip, "the suggested tip must not be modified")
+
+		args = TransactionArgs{MaxFeePerGas: (*hexutil.Big)(big.NewInt(90))}
+		estimate = newFeeEstimate(args, 21000, 0, baseFee, tip, &currency.CELOCurrency)
+		assert.Zero(t, estimate.MaxPriorityFeePerGas.ToInt().Sign())
+	})
+
+	t.Run("Explicit fee cap and gateway fee", func(t *testing.T) {
+		args := TransactionArgs{
+			FeeCurrency:  &feeCurrency,
+			MaxFeePerGas: (*hexutil.Big)(big.NewInt(500)),
+			GatewayFee:   (*hexutil.Big)(big.NewInt(1000)),
+		}
+		estimate := newFeeEstimate(args, 21000, 0, baseFee, tip, cusd)
+		assert.Equal(t, (*hexutil.Big)(tip), estimate.MaxPriorityFeePerGas)
+		assert.Equal(t, (*hexutil.Big)(big.NewInt(500*21000+1000)), estimate.MaxCost)
+	})
+
+	t.Run("Legacy gas price pays the excess over the base fee as tip", func(t *testing.T) {
+		args := TransactionArgs{GasPrice: (*hexutil.Big)(big.NewInt(150))}
+		estimate := newFeeEstimate(args, 21000, 0, baseFee, nil, &currency.CELOCurrency)
+		assert.Equal(t, (*hexutil.Big)(big.NewInt(50)), estimate.MaxPriorityFeePerGas)
+		assert.Equal(t, (*hexutil.Big)(big.NewInt(150)), estimate.MaxFeePerGas)
+		assert.Equal(t, estimate.MaxCost, estimate.MaxCostInCelo)
+	})
+}
+
+func TestEstimateFee(t *testing.T) {
+	celo := testutil.NewCeloMock()
+	backend := newTestBackend(t, nil)
+	backend.runner = celo.Runner
+	api := NewPublicCeloAPI(backend)
+	baseFee := backend.chain.CurrentHeader().BaseFee
+	gas := hexutil.Uint64(21000)
+
+	t.Run("CELO", func(t *testing.T) {
+		estimate, err := api.EstimateFee(context.Background(), TransactionArgs{Gas: &gas}, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, estimate.FeeCurrency)
+		assert.Equal(t, gas, estimate.Gas)
+		assert.Equal(t, hexutil.Uint64(0), estimate.IntrinsicGasForFee)
+		assert.Equal(t, (*hexutil.Big)(baseFee), estimate.BaseFeePerGas)
+		assert.Equal(t, (*hexutil.Big)(backend.tip), estimate.MaxPriorityFeePerGas)
+		feeCap := new(big.Int).Add(backend.tip, new(big.Int).Mul(baseFee, big.NewInt(2)))
+		assert.Equal(t, (*hexutil.Big)(feeCap), estimate.MaxFeePerGas)
+		assert.Equal(t, (*hexutil.Big)(new(big.Int).Mul(feeCap, big.NewInt(21000))), estimate.MaxCost)
+		assert.Equal(t, estimate.MaxCost, estimate.MaxCostInCelo)
+	})
+
+	t.Run("Whitelisted fee currency pays the intrinsic gas", func(t *testing.T) {
+		feeCurrency := common.HexToAddress("0x02")
+		estimate, err := api.EstimateFee(context.Background(), TransactionArgs{Gas: &gas, FeeCurrency: &feeCurrency}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, &feeCurrency, estimate.FeeCurrency)
+		assert.Equal(t, hexutil.Uint64(celo.BlockchainParameters.IntrinsicGasForAlternativeFeeCurrencyValue.Uint64()), estimate.IntrinsicGasForFee)
+	})
+
+	t.Run("Suggested tip is limited by the fee cap", func(t *testing.T) {
+		feeCap := new(big.Int).Add(baseFee, big.NewInt(3))
+		estimate, err := api.EstimateFee(context.Background(), TransactionArgs{Gas: &gas, MaxFeePerGas: (*hexutil.Big)(feeCap)}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, (*hexutil.Big)(big.NewInt(3)), estimate.MaxPriorityFeePerGas)
+		assert.Equal(t, (*hexutil.Big)(new(big.Int).Mul(feeCap, big.NewInt(21000))), estimate.MaxCost)
+	})
+
+	t.Run("Non whitelisted fee currency", func(t *testing.T) {
+		feeCurrency := common.HexToAddress("0x04")
+		_, err := api.EstimateFee(context.Background(), TransactionArgs{Gas: &gas, FeeCurrency: &feeCurrency}, nil)
+		assert.True(t, errors.Is(err, core.ErrNonWhitelistedFeeCurrency))
+	})
+}
+
+func TestGetFeeCurrencies(t *testing.T) {
+	var (
+		celo       = testutil.NewCeloMock()
+		oracles    = testutil.NewSortedOraclesMock()
+		stable     = common.HexToAddress("0x02") // whitelisted by the mock, with metadata
+		noMetadata = common.HexToAddress("0x05") // whitelisted by the mock, without metadata
+	)
+	celo.Registry.AddContract(config.SortedOraclesRegistryId, common.HexToAddress("0x06"))
+	celo.Runner.RegisterContract(common.HexToAddress("0x06"), oracles)
+	oracles.Rates[stable] = [2]*big.Int{big.NewInt(2), big.NewInt(1)}
+	celo.Runner.RegisterContract(stable, testutil.NewERC20MetadataMock("cUSD", 18))
+
+	backend := newTestBackend(t, nil)
+	backend.runner = celo.Runner
+	backend.feeCurrencyLimits = map[common.Address]float64{stable: 0.5}
+	backend.defaultFeeCurrencyLimit = 0.9
+	baseFee := backend.chain.CurrentHeader().BaseFee
+
+	currencies, err := NewPublicCeloAPI(backend).GetFeeCurrencies(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, currencies, 2)
+	byAddress := make(map[common.Address]*FeeCurrencyInfo)
+	for _, info := range currencies {
+		byAddress[info.Address] = info
+	}
+
+	info := byAddress[stable]
+	if assert.NotNil(t, info) {
+		decimals := hexutil.Uint(18)
+		assert.Equal(t, "cUSD", info.Symbol)
+		assert.Equal(t, &decimals, info.Decimals)
+		assert.Equal(t, 0.5, info.BlockGasLimitFraction)
+		assert.Equal(t, (*hexutil.Big)(big.NewInt(2)), info.ExchangeRate.Numerator)
+		assert.Equal(t, (*hexutil.Big)(big.NewInt(1)), info.ExchangeRate.Denominator)
+		rate, _ := currency.NewExchangeRate(big.NewInt(2), big.NewInt(1))
+		assert.Equal(t, (*hexutil.Big)(rate.FromBase(baseFee)), info.GasPriceMinimum)
+	}
+
+	// Tokens without symbol or decimals are reported without them
+	info = byAddress[noMetadata]
+	if assert.NotNil(t, info) {
+		assert.Empty(t, info.Symbol)
+		assert.Nil(t, info.Decimals)
+		assert.Equal(t, 0.9, info.BlockGasLimitFraction)
+	}
+}
+
+func TestGetBalances(t *testing.T) {
+	var (
+		celo     = testutil.NewCeloMock()
+		token    = testutil.NewERC20MetadataMock("TKN", 18)
+		account  = common.HexToAddress("0x1234")
+		extra    = common.HexToAddress("0x0e")
+		notToken = common.HexToAddress("0x0f")
+		latest   = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	)
+	token.Balances[account] = big.NewInt(42)
+	celo.Runner.RegisterContract(extra, token)
+
+	backend := newTestBackend(t, core.GenesisAlloc{account: {Balance: big.NewInt(1000)}})
+	backend.runner = celo.Runner
+	api := NewPublicCeloAPI(backend)
+
+	// Duplicates of whitelisted tokens are skipped, failing tokens report an error
+	extraTokens := []common.Address{extra, common.HexToAddress("0x02"), notToken}
+	balances, err := api.GetBalances(context.Background(), account, latest, &extraTokens)
+	assert.NoError(t, err)
+	assert.Equal(t, (*hexutil.Big)(big.NewInt(1000)), balances.Celo)
+	if assert.Len(t, balances.Tokens, 4) {
+		whitelisted := celo.ERC20Token.BalanceOf(account)
+		assert.Equal(t, TokenBalance{Token: common.HexToAddress("0x02"), Balance: (*hexutil.Big)(whitelisted)}, balances.Tokens[0])
+		assert.Equal(t, TokenBalance{Token: common.HexToAddress("0x05"), Balance: (*hexutil.Big)(whitelisted)}, balances.Tokens[1])
+		assert.Equal(t, TokenBalance{Token: extra, Balance: (*hexutil.Big)(big.NewInt(42))}, balances.Tokens[2])
+		assert.Equal(t, notToken, balances.Tokens[3].Token)
+		assert.Nil(t, balances.Tokens[3].Balance)
+		assert.NotEmpty(t, balances.Tokens[3].Error)
+	}
+
+	extraTokens = make([]common.Address, maxExtraBalanceTokens+1)
+	_, err = api.GetBalances(context.Background(), account, latest, &extraTokens)
+	assert.Error(t, err)
+}
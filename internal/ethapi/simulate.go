@@ -0,0 +1,272 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/common/hexutil"
+	"github.com/celo-org/celo-blockchain/core"
+	"github.com/celo-org/celo-blockchain/core/state"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/core/vm"
+	"github.com/celo-org/celo-blockchain/crypto"
+	"github.com/celo-org/celo-blockchain/params"
+	"github.com/celo-org/celo-blockchain/rpc"
+)
+
+const (
+	// maxSimulateBlocks is the maximum number of blocks a single simulation
+	// may span.
+	maxSimulateBlocks = 256
+
+	// simulateTimeout bounds the execution time of a whole simulation.
+	simulateTimeout = 50 * time.Second
+)
+
+// BlockOverrides are the header fields of a simulated block that differ from
+// the defaults derived from its parent.
+type BlockOverrides struct {
+	Number        *hexutil.Big    `json:"number"`
+	Time          *hexutil.Uint64 `json:"time"`
+	FeeRecipient  *common.Address `json:"feeRecipient"`
+	BaseFeePerGas *hexutil.Big    `json:"baseFeePerGas"`
+}
+
+// SimBlock is a block of calls to simulate, on top of the state left by the
+// previous one.
+type SimBlock struct {
+	BlockOverrides *BlockOverrides   `json:"blockOverrides"`
+	StateOverrides *StateOverride    `json:"stateOverrides"`
+	Calls          []TransactionArgs `json:"calls"`
+}
+
+// SimOpts are the arguments of eth_simulateV1.
+type SimOpts struct {
+	BlockStateCalls []SimBlock `json:"blockStateCalls"`
+}
+
+// SimCallResult is the outcome of a single simulated call.
+type SimCallResult struct {
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	Logs       []*types.Log   `json:"logs"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	Status     hexutil.Uint64 `json:"status"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// SimBlockResult is the outcome of a simulated block.
+type SimBlockResult struct {
+	Number        hexutil.Uint64  `json:"number"`
+	Time          hexutil.Uint64  `json:"timestamp"`
+	FeeRecipient  common.Address  `json:"feeRecipient"`
+	BaseFeePerGas *hexutil.Big    `json:"baseFeePerGas,omitempty"`
+	GasUsed       hexutil.Uint64  `json:"gasUsed"`
+	Calls         []SimCallResult `json:"calls"`
+}
+
+// SimulateV1 executes sequences of calls across several blocks on top of the
+// given block, each block starting from the state left by the previous one.
+// Fees are debited and credited as they would be for transactions, so fee
+// currency balances change accordingly. Nothing is persisted.
+//
+// Simulated blocks are not finalized: no epoch rewards or other system calls
+// are applied between them, and their hashes are unknown to the BLOCKHASH
+// opcode.
+//
+// All calls share a single RPC gas cap budget, which bounds the gas used by
+// the whole simulation.
+func (s *PublicBlockChainAPI) SimulateV1(ctx context.Context, opts SimOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]*SimBlockResult, error) {
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, errors.New("empty simulation")
+	}
+	if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, fmt.Errorf("too many blocks: have %d, max %d", len(opts.BlockStateCalls), maxSimulateBlocks)
+	}
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	state, parent, err := s.b.StateAndHeaderByNumberOrHash(ctx, bNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, simulateTimeout)
+	defer cancel()
+
+	// The gas used by the calls of all blocks is taken from the same budget
+	gasCap := s.b.RPCGasCap()
+	if gasCap == 0 {
+		gasCap = math.MaxUint64
+	}
+	gp := new(core.GasPool).AddGas(gasCap)
+
+	results := make([]*SimBlockResult, 0, len(opts.BlockStateCalls))
+	for i, block := range opts.BlockStateCalls {
+		header, err := simulatedHeader(s.b.ChainConfig(), parent, block.BlockOverrides)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", i, err)
+		}
+		// Headers before Gingerbread don't carry the gas limit
+		if header.GasLimit == 0 {
+			header.GasLimit = s.b.GetBlockGasLimit(ctx, bNrOrHash)
+		}
+		if err := block.StateOverrides.Apply(state); err != nil {
+			return nil, fmt.Errorf("block %d: %w", i, err)
+		}
+		result, err := simulateBlock(ctx, s.b, state, header, block.BlockOverrides, block.Calls, gp)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", i, err)
+		}
+		results = append(results, result)
+		parent = header
+	}
+	return results, nil
+}
+
+// simulatedHeader derives the header of a simulated block from its parent and
+// the block overrides.
+func simulatedHeader(config *params.ChainConfig, parent *types.Header, overrides *BlockOverrides) (*types.Header, error) {
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Coinbase:   parent.Coinbase,
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + 1,
+		BaseFee:    parent.BaseFee,
+	}
+	if overrides == nil {
+		return header, nil
+	}
+	if overrides.Number != nil {
+		if overrides.Number.ToInt().Cmp(parent.Number) <= 0 {
+			return nil, fmt.Errorf("block number %v not above parent %v", overrides.Number.ToInt(), parent.Number)
+		}
+		header.Number = new(big.Int).Set(overrides.Number.ToInt())
+	}
+	if overrides.Time != nil {
+		if uint64(*overrides.Time) <= parent.Time {
+			return nil, fmt.Errorf("timestamp %d not above parent %d", uint64(*overrides.Time), parent.Time)
+		}
+		header.Time = uint64(*overrides.Time)
+	}
+	if overrides.FeeRecipient != nil {
+		header.Coinbase = *overrides.FeeRecipient
+	}
+	if overrides.BaseFeePerGas != nil {
+		if !config.IsGingerbread(header.Number) {
+			return nil, errors.New("base fee overrides are not supported before the Gingerbread fork")
+		}
+		header.BaseFee = new(big.Int).Set(overrides.BaseFeePerGas.ToInt())
+	}
+	return header, nil
+}
+
+// simulateBlock executes the calls of a simulated block in order on top of
+// the given state, charging their gas to the simulation's gas pool.
+func simulateBlock(ctx context.Context, b Backend, state *state.StateDB, header *types.Header, overrides *BlockOverrides, calls []TransactionArgs, gp *core.GasPool) (*SimBlockResult, error) {
+	var sysCtx *core.SysContractCallCtx
+	if b.ChainConfig().IsEspresso(header.Number) {
+		sysCtx = core.NewSysContractCallCtx(header, state, b)
+		// An overridden base fee is the gas price minimum of CELO, whatever the
+		// system contracts say. Those of the fee currencies are derived from
+		// the header's base fee, which is already overridden.
+		if overrides != nil && overrides.BaseFeePerGas != nil {
+			sysCtx.OverrideGasPriceMinimum(nil, header.BaseFee)
+		}
+	}
+	result := &SimBlockResult{
+		Number:        hexutil.Uint64(header.Number.Uint64()),
+		Time:          hexutil.Uint64(header.Time),
+		FeeRecipient:  header.Coinbase,
+		BaseFeePerGas: (*hexutil.Big)(header.BaseFee),
+		Calls:         make([]SimCallResult, 0, len(calls)),
+	}
+	var (
+		blockHash = header.Hash()
+		gasUsed   uint64
+	)
+	for i, args := range calls {
+		// Calls get the gas left in the block unless told otherwise, so that
+		// they can pay for it when a gas price is set
+		if args.Gas == nil {
+			gas := hexutil.Uint64(header.GasLimit - gasUsed)
+			args.Gas = &gas
+		}
+		if uint64(*args.Gas) > header.GasLimit-gasUsed {
+			return nil, fmt.Errorf("call %d: %w: have %d, want %d", i, core.ErrGasLimitReached, header.GasLimit-gasUsed, uint64(*args.Gas))
+		}
+		if gp.Gas() == 0 {
+			return nil, fmt.Errorf("call %d: %w: gas budget exhausted", i, core.ErrGasLimitReached)
+		}
+		// Calls pay the gas price minimum of their fee currency as base fee and
+		// can't use more gas than what's left of the budget
+		baseFee := header.BaseFee
+		if sysCtx != nil && baseFee != nil {
+			baseFee = sysCtx.GetGasPriceMinimum(args.FeeCurrency)
+		}
+		msg, err := args.ToMessage(gp.Gas(), baseFee)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		evm, vmError, err := b.GetEVM(ctx, msg, state, header, &vm.Config{NoBaseFee: true})
+		if err != nil {
+			return nil, err
+		}
+		// Abort the call once the simulation runs out of time
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				evm.Cancel()
+			case <-done:
+			}
+		}()
+		callHash := crypto.Keccak256Hash(common.BigToHash(header.Number).Bytes(), common.BigToHash(big.NewInt(int64(i))).Bytes())
+		state.Prepare(callHash, i)
+
+		res, err := core.ApplyMessage(evm, msg, gp, b.NewEVMRunner(header, state), sysCtx)
+		close(done)
+		if err := vmError(); err != nil {
+			return nil, err
+		}
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", simulateTimeout)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		state.Finalise(true)
+
+		logs := state.GetLogs(callHash, blockHash)
+		for _, l := range logs {
+			l.BlockNumber = header.Number.Uint64()
+		}
+		call := SimCallResult{
+			ReturnData: res.Return(),
+			Logs:       logs,
+			GasUsed:    hexutil.Uint64(res.UsedGas),
+			Status:     hexutil.Uint64(types.ReceiptStatusSuccessful),
+		}
+		if res.Failed() {
+			call.Status = hexutil.Uint64(types.ReceiptStatusFailed)
+			call.ReturnData = res.Revert()
+			if len(res.Revert()) > 0 {
+				call.Error = newRevertError(res).Error()
+			} else {
+				call.Error = res.Err.Error()
+			}
+		}
+		if call.Logs == nil {
+			call.Logs = []*types.Log{}
+		}
+		result.Calls = append(result.Calls, call)
+		gasUsed += res.UsedGas
+	}
+	result.GasUsed = hexutil.Uint64(gasUsed)
+	return result, nil
+}
@@ -19,6 +19,7 @@ package web3ext
 
 var Modules = map[string]string{
 	"admin":    AdminJs,
+	"celo":     CeloJs,
 	"debug":    DebugJs,
 	"eth":      EthJs,
 	"istanbul": Istanbul_JS,
@@ -26,6 +27,7 @@ var Modules = map[string]string{
 	"net":      NetJs,
 	"personal": PersonalJs,
 	"rpc":      RpcJs,
+	"trace":    TraceJs,
 	"txpool":   TxpoolJs,
 	"les":      LESJs,
 	"vflux":    VfluxJs,
@@ -492,6 +494,28 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter],
 		}),
+		new web3._extend.Method({
+			name: 'feeHistory',
+			call: 'eth_feeHistory',
+			params: 4,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'sendRawTransactionConditional',
+			call: 'eth_sendRawTransactionConditional',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'simulateV1',
+			call: 'eth_simulateV1',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'sendBundle',
+			call: 'eth_sendBundle',
+			params: 1
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -641,10 +665,42 @@ web3._extend({
 });
 `
 
+const TraceJs = `
+web3._extend({
+	property: 'trace',
+	methods: [
+		new web3._extend.Method({
+			name: 'block',
+			call: 'trace_block',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'filter',
+			call: 'trace_filter',
+			params: 1
+		}),
+	]
+});
+`
+
 const TxpoolJs = `
 web3._extend({
 	property: 'txpool',
-	methods: [],
+	methods: [
+		new web3._extend.Method({
+			name: 'removeTransaction',
+			call: 'txpool_removeTransaction',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'evictAccount',
+			call: 'txpool_evictAccount',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null],
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
+	],
 	properties:
 	[
 		new web3._extend.Property({
@@ -655,6 +711,10 @@ web3._extend({
 			name: 'inspect',
 			getter: 'txpool_inspect'
 		}),
+		new web3._extend.Property({
+			name: 'contentByFeeCurrency',
+			getter: 'txpool_contentByFeeCurrency'
+		}),
 		new web3._extend.Property({
 			name: 'status',
 			getter: 'txpool_status',
@@ -792,6 +852,57 @@ web3._extend({
 });
 `
 
+const CeloJs = `
+web3._extend({
+	property: 'celo',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'getNextEpochElection',
+			call: 'celo_getNextEpochElection',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getEpochRewards',
+			call: 'celo_getEpochRewards',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'getEpochInfo',
+			call: 'celo_getEpochInfo',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getRandomness',
+			call: 'celo_getRandomness',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'estimateFee',
+			call: 'celo_estimateFee',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputCallFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'getFeeCurrencies',
+			call: 'celo_getFeeCurrencies',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getBalances',
+			call: 'celo_getBalances',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter, null]
+		}),
+	],
+	properties: []
+});
+`
+
 const LESJs = `
 web3._extend({
 	property: 'les',
@@ -21,11 +21,13 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	ethereum "github.com/celo-org/celo-blockchain"
 	"github.com/celo-org/celo-blockchain/accounts"
 	"github.com/celo-org/celo-blockchain/common"
 	"github.com/celo-org/celo-blockchain/consensus"
+	"github.com/celo-org/celo-blockchain/consensus/istanbul"
 	"github.com/celo-org/celo-blockchain/contracts/blockchain_parameters"
 	"github.com/celo-org/celo-blockchain/core"
 	"github.com/celo-org/celo-blockchain/core/bloombits"
@@ -47,6 +49,7 @@ type LesApiBackend struct {
 	extRPCEnabled       bool
 	allowUnprotectedTxs bool
 	eth                 *LightEthereum
+	gpo                 *gp.Oracle
 }
 
 func (b *LesApiBackend) ChainConfig() *params.ChainConfig {
@@ -72,6 +75,14 @@ func (b *LesApiBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 	if number == rpc.LatestBlockNumber {
 		return b.eth.blockchain.CurrentHeader(), nil
 	}
+	if number == rpc.LatestEpochBlockNumber {
+		head := b.eth.blockchain.CurrentHeader().Number.Uint64()
+		if config := b.ChainConfig().Istanbul; config != nil {
+			number = rpc.BlockNumber(istanbul.GetLatestEpochLastBlockNumber(head, config.Epoch))
+		} else {
+			number = rpc.BlockNumber(head)
+		}
+	}
 	return b.eth.blockchain.GetHeaderByNumberOdr(ctx, uint64(number))
 }
 
@@ -194,9 +205,16 @@ func (b *LesApiBackend) GetEVM(ctx context.Context, msg core.Message, state *sta
 }
 
 func (b *LesApiBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	if signedTx.Conditional() != nil {
+		return errors.New("conditional transactions are not supported by light clients")
+	}
 	return b.eth.txPool.Add(ctx, signedTx)
 }
 
+func (b *LesApiBackend) SendBundle(ctx context.Context, bundle *core.Bundle) error {
+	return errors.New("bundles are not supported by light clients")
+}
+
 func (b *LesApiBackend) RemoveTx(txHash common.Hash) {
 	b.eth.txPool.RemoveTx(txHash)
 }
@@ -225,10 +243,33 @@ func (b *LesApiBackend) TxPoolContent() (map[common.Address]types.Transactions,
 	return b.eth.txPool.Content()
 }
 
+func (b *LesApiBackend) TxPoolFeeCurrencyStats() map[common.Address]*core.FeeCurrencyStats {
+	return nil
+}
+
 func (b *LesApiBackend) TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
 	return b.eth.txPool.ContentFrom(addr)
 }
 
+func (b *LesApiBackend) TxPoolRemoveTransaction(hash common.Hash) bool {
+	if b.eth.txPool.GetTransaction(hash) == nil {
+		return false
+	}
+	b.eth.txPool.RemoveTx(hash)
+	return true
+}
+
+func (b *LesApiBackend) TxPoolEvictAccount(addr common.Address, ban time.Duration) (int, error) {
+	if ban > 0 {
+		return 0, errors.New("banning accounts is not supported by light clients")
+	}
+	pending, _ := b.eth.txPool.ContentFrom(addr)
+	for _, tx := range pending {
+		b.eth.txPool.RemoveTx(tx.Hash())
+	}
+	return len(pending), nil
+}
+
 func (b *LesApiBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
 	return b.eth.txPool.SubscribeNewTxsEvent(ch)
 }
@@ -336,11 +377,11 @@ func (b *LesApiBackend) NewEVMRunner(header *types.Header, state vm.StateDB) vm.
 }
 
 func (b *LesApiBackend) SuggestGasTipCap(ctx context.Context, currencyAddress *common.Address) (*big.Int, error) {
-	vmRunner, err := b.eth.BlockChain().NewEVMRunnerForCurrentBlock()
-	if err != nil {
-		return nil, err
-	}
-	return gp.GetGasTipCapSuggestion(vmRunner, currencyAddress)
+	return b.gpo.SuggestTipCap(ctx, currencyAddress)
+}
+
+func (b *LesApiBackend) FeeHistory(ctx context.Context, blockCount int, lastBlock rpc.BlockNumber, rewardPercentiles []float64, feeCurrency *common.Address) (firstBlock *big.Int, reward [][]*big.Int, baseFee []*big.Int, gasUsedRatio []float64, err error) {
+	return b.gpo.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles, feeCurrency)
 }
 
 func (b *LesApiBackend) CurrentGasPriceMinimum(ctx context.Context, currencyAddress *common.Address) (*big.Int, error) {
@@ -445,6 +486,10 @@ func (b *LesApiBackend) GatewayFee() *big.Int {
 	return ethconfig.Defaults.GatewayFee
 }
 
+func (b *LesApiBackend) FeeCurrencyLimits() (map[common.Address]float64, float64) {
+	return b.eth.config.Miner.FeeCurrencyLimits, b.eth.config.Miner.FeeCurrencyDefault
+}
+
 func (b *LesApiBackend) Engine() consensus.Engine {
 	return b.eth.engine
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/celo-org/celo-blockchain/core/types"
 	"github.com/celo-org/celo-blockchain/eth/ethconfig"
 	"github.com/celo-org/celo-blockchain/eth/filters"
+	"github.com/celo-org/celo-blockchain/eth/gasprice"
 	"github.com/celo-org/celo-blockchain/event"
 	"github.com/celo-org/celo-blockchain/internal/ethapi"
 	"github.com/celo-org/celo-blockchain/les/downloader"
@@ -109,6 +110,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*LightEthereum, error) {
 		return nil, genesisErr
 	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
+	config.TrustedEpoch = params.TrustedEpochFor(genesisHash, config.TrustedEpoch)
 
 	peers := newServerPeerSet()
 	leth := &LightEthereum{
@@ -187,7 +189,8 @@ func New(stack *node.Node, config *ethconfig.Config) (*LightEthereum, error) {
 		rawdb.WriteChainConfig(chainDb, genesisHash, chainConfig)
 	}
 
-	leth.ApiBackend = &LesApiBackend{stack.Config().ExtRPCEnabled(), true, leth}
+	leth.ApiBackend = &LesApiBackend{stack.Config().ExtRPCEnabled(), true, leth, nil}
+	leth.ApiBackend.gpo = gasprice.NewOracle(leth.ApiBackend, config.GPO)
 
 	leth.chainreader = &LightChainReader{
 		config:     leth.chainConfig,
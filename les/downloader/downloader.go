@@ -150,6 +150,7 @@ type Downloader struct {
 	quitCh        chan struct{} // Quit channel to signal termination
 	quitLock      sync.Mutex    // Lock to prevent double closes
 	epoch         uint64        // Epoch value is useful in IBFT consensus
+	trustedEpoch  uint64        // Block number of the trusted epoch header lightest sync starts from (0 = genesis)
 	ibftConsensus bool          // True if we are in IBFT consensus mode
 
 	// Testing hooks
@@ -222,7 +223,7 @@ type BlockChain interface {
 // TODO(tim) previously passing mode here!
 
 // New creates a new downloader to fetch hashes and blocks from remote peers.
-func New(checkpoint uint64, stateDb ethdb.Database, stateBloom *trie.SyncBloom, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
+func New(checkpoint uint64, trustedEpoch *params.TrustedEpoch, stateDb ethdb.Database, stateBloom *trie.SyncBloom, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
 	if lightchain == nil {
 		lightchain = chain
 	}
@@ -239,6 +240,10 @@ func New(checkpoint uint64, stateDb ethdb.Database, stateBloom *trie.SyncBloom,
 	if epoch > math.MaxInt32 {
 		panic(fmt.Sprintf("epoch is too big(%d), the code to fetch epoch headers casts epoch to an int to calculate value for skip variable", epoch))
 	}
+	trustedEpochBlock := uint64(0)
+	if trustedEpoch != nil && ibftConsensus {
+		trustedEpochBlock = trustedEpoch.BlockNumber(epoch)
+	}
 
 	dl := &Downloader{
 		stateDB:        stateDb,
@@ -266,6 +271,7 @@ func New(checkpoint uint64, stateDb ethdb.Database, stateBloom *trie.SyncBloom,
 		trackStateReq: make(chan *stateReq),
 		ibftConsensus: ibftConsensus,
 		epoch:         epoch,
+		trustedEpoch:  trustedEpochBlock,
 	}
 	go dl.stateFetcher()
 	return dl
@@ -511,6 +517,15 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 	if err != nil {
 		return err
 	}
+	// Lightest sync doesn't need the epochs before the trusted one, as the consensus
+	// engine accepts the trusted epoch header as the root of the validator set chain.
+	// Other sync modes always start from the common ancestor: fast sync imports
+	// the bodies and receipts of every block into a chain that can't have gaps,
+	// so starting it from a trusted epoch is not supported.
+	if mode == LightestSync && origin < d.trustedEpoch && d.trustedEpoch <= height {
+		log.Info("Starting lightest sync from the trusted epoch", "number", d.trustedEpoch)
+		origin = d.trustedEpoch - 1
+	}
 	d.syncStatsLock.Lock()
 	if d.syncStatsChainHeight <= origin || d.syncStatsChainOrigin > origin {
 		d.syncStatsChainOrigin = origin
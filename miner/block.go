@@ -196,6 +196,11 @@ func prepareBlock(w *worker) (*blockState, error) {
 
 // selectAndApplyTransactions selects and applies transactions to the in flight block state.
 func (b *blockState) selectAndApplyTransactions(ctx context.Context, w *worker) error {
+	// Bundles go to the top of the block. They are only applied to blocks we
+	// propose, so their transactions never show up in the pending block.
+	if w.isRunning() {
+		b.commitBundles(w)
+	}
 	// Fill the block with all available pending transactions.
 	pending, err := w.eth.TxPool().Pending(true)
 
@@ -304,6 +309,20 @@ loop:
 			txs.Pop()
 			continue
 		}
+		// Skip the account if the conditions of the transaction do not hold on
+		// top of the transactions included so far
+		if cond := tx.Conditional(); cond != nil {
+			if err := cond.CheckBlock(b.header); err != nil {
+				log.Trace("Ignoring conditional transaction", "hash", tx.Hash(), "err", err)
+				txs.Pop()
+				continue
+			}
+			if err := cond.CheckStorage(b.state); err != nil {
+				log.Trace("Ignoring conditional transaction", "hash", tx.Hash(), "err", err)
+				txs.Pop()
+				continue
+			}
+		}
 		// Start executing the transaction
 		b.state.Prepare(tx.Hash(), b.tcount)
 
@@ -0,0 +1,133 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/celo-org/celo-blockchain/core"
+	"github.com/celo-org/celo-blockchain/core/types"
+	"github.com/celo-org/celo-blockchain/log"
+)
+
+// maxBundlesPerBlock is the maximum number of bundles tried for each block, as
+// every attempt copies the block state.
+const maxBundlesPerBlock = 16
+
+var (
+	errBundleReverted     = errors.New("bundle transaction reverted")
+	errBundleUnprofitable = errors.New("bundle tip below the minimum")
+)
+
+// commitBundle applies all transactions of the bundle on top of the block state,
+// in order. If any of them can't be applied, reverts, or the bundle pays less
+// than minTip per unit of gas in CELO, every change made by the bundle is undone.
+func (b *blockState) commitBundle(w *worker, bundle *core.Bundle, minTip *big.Int) error {
+	var (
+		state      = b.state.Copy()
+		gasPool    = *b.gasPool
+		gasUsed    = b.header.GasUsed
+		txCount    = len(b.txs)
+		tcount     = b.tcount
+		bytesBlock core.BytesBlock
+
+		currencyGas = make(map[*core.GasPool]uint64)
+	)
+	if b.bytesBlock != nil {
+		bytesBlock = *b.bytesBlock
+	}
+	// Transactions are finalised into the state one by one, so journal snapshots
+	// can't span the bundle. Roll back to a copy of the state instead.
+	revert := func() {
+		b.state.StopPrefetcher()
+		b.state = state
+		*b.gasPool = gasPool
+		b.header.GasUsed = gasUsed
+		b.txs = b.txs[:txCount]
+		b.receipts = b.receipts[:txCount]
+		b.tcount = tcount
+		if b.bytesBlock != nil {
+			*b.bytesBlock = bytesBlock
+		}
+		for pool, gas := range currencyGas {
+			pool.AddGas(gas)
+		}
+	}
+	baseFeeFn, toCELOFn := createConversionFunctions(b.sysCtx, w.chain, b.header, b.state)
+
+	var (
+		totalGas = new(big.Int)
+		totalTip = new(big.Int)
+	)
+	for _, tx := range bundle.Txs {
+		pool := b.multiGasPool.PoolFor(tx.FeeCurrency())
+		if pool.Gas() < tx.Gas() {
+			revert()
+			return fmt.Errorf("%w: fee currency gas limit reached by %s", core.ErrGasLimitReached, tx.Hash().Hex())
+		}
+		if b.bytesBlock != nil && b.bytesBlock.BytesLeft() < uint64(tx.Size()) {
+			revert()
+			return fmt.Errorf("%w: by %s", core.ErrBytesLimitReached, tx.Hash().Hex())
+		}
+		if tx.GatewaySet() && w.chainConfig.IsGingerbread(b.header.Number) {
+			revert()
+			return fmt.Errorf("transaction %s sets a gateway fee", tx.Hash().Hex())
+		}
+		b.state.Prepare(tx.Hash(), b.tcount)
+
+		if _, err := b.commitTransaction(w, tx, b.txFeeRecipient); err != nil {
+			revert()
+			return fmt.Errorf("transaction %s: %w", tx.Hash().Hex(), err)
+		}
+		receipt := b.receipts[len(b.receipts)-1]
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			revert()
+			return fmt.Errorf("%w: %s", errBundleReverted, tx.Hash().Hex())
+		}
+		b.tcount++
+		if b.bytesBlock != nil && w.chainConfig.IsGingerbreadP2(b.header.Number) {
+			if err := b.bytesBlock.SubBytes(uint64(tx.Size())); err != nil {
+				revert()
+				return err
+			}
+		}
+		if err := pool.SubGas(receipt.GasUsed); err != nil {
+			revert()
+			return err
+		}
+		currencyGas[pool] += receipt.GasUsed
+
+		gas := new(big.Int).SetUint64(receipt.GasUsed)
+		tip, err := toCELOFn(new(big.Int).Mul(gas, tx.EffectiveGasTipValue(baseFeeFn(tx.DenominatedFeeCurrency()))), tx.DenominatedFeeCurrency())
+		if err != nil {
+			revert()
+			return err
+		}
+		totalGas.Add(totalGas, gas)
+		totalTip.Add(totalTip, tip)
+	}
+	if want := new(big.Int).Mul(totalGas, minTip); totalTip.Cmp(want) < 0 {
+		revert()
+		return fmt.Errorf("%w: tip %v, want %v", errBundleUnprofitable, totalTip, want)
+	}
+	return nil
+}
+
+// commitBundles applies the most profitable bundles targeting the block ahead of
+// any pending transaction, trying at most maxBundlesPerBlock of them. Bundles
+// that can't be applied are skipped.
+func (b *blockState) commitBundles(w *worker) {
+	minTip := w.eth.TxPool().GasPrice()
+	bundles := w.eth.BundlePool().Bundles(b.header)
+	if len(bundles) > maxBundlesPerBlock {
+		log.Debug("Too many bundles for the block", "bundles", len(bundles), "tried", maxBundlesPerBlock)
+		bundles = bundles[:maxBundlesPerBlock]
+	}
+	for _, bundle := range bundles {
+		if err := b.commitBundle(w, bundle, minTip); err != nil {
+			log.Debug("Skipping bundle", "hash", bundle.Hash(), "txs", len(bundle.Txs), "err", err)
+			continue
+		}
+		log.Debug("Committed bundle", "hash", bundle.Hash(), "txs", len(bundle.Txs))
+	}
+}
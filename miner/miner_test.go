@@ -37,14 +37,16 @@ import (
 )
 
 type mockBackend struct {
-	bc     *core.BlockChain
-	txPool *core.TxPool
+	bc         *core.BlockChain
+	txPool     *core.TxPool
+	bundlePool *core.BundlePool
 }
 
 func NewMockBackend(bc *core.BlockChain, txPool *core.TxPool) *mockBackend {
 	return &mockBackend{
-		bc:     bc,
-		txPool: txPool,
+		bc:         bc,
+		txPool:     txPool,
+		bundlePool: core.NewBundlePool(bc.Config(), 16),
 	}
 }
 
@@ -56,6 +58,10 @@ func (m *mockBackend) TxPool() *core.TxPool {
 	return m.txPool
 }
 
+func (m *mockBackend) BundlePool() *core.BundlePool {
+	return m.bundlePool
+}
+
 type testBlockChain struct {
 	statedb       *state.StateDB
 	gasLimit      uint64
@@ -17,6 +17,9 @@
 package miner
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
 	"math/big"
 	"math/rand"
 	"testing"
@@ -30,6 +33,7 @@ import (
 	"github.com/celo-org/celo-blockchain/consensus/istanbul"
 	"github.com/celo-org/celo-blockchain/consensus/istanbul/backend"
 	istanbulBackend "github.com/celo-org/celo-blockchain/consensus/istanbul/backend"
+	"github.com/celo-org/celo-blockchain/contracts/currency"
 	"github.com/celo-org/celo-blockchain/core"
 	"github.com/celo-org/celo-blockchain/core/rawdb"
 	"github.com/celo-org/celo-blockchain/core/state"
@@ -102,6 +106,7 @@ type testWorkerBackend struct {
 	accountManager *accounts.Manager
 	db             ethdb.Database
 	txPool         *core.TxPool
+	bundlePool     *core.BundlePool
 	chain          *core.BlockChain
 	genesis        *core.Genesis
 }
@@ -158,6 +163,7 @@ func newTestWorkerBackend(t *testing.T, chainConfig *params.ChainConfig, engine
 		db:             db,
 		chain:          chain,
 		txPool:         txpool,
+		bundlePool:     core.NewBundlePool(chainConfig, 16),
 		genesis:        &gspec,
 	}
 }
@@ -165,6 +171,7 @@ func newTestWorkerBackend(t *testing.T, chainConfig *params.ChainConfig, engine
 func (b *testWorkerBackend) AccountManager() *accounts.Manager { return b.accountManager }
 func (b *testWorkerBackend) BlockChain() *core.BlockChain      { return b.chain }
 func (b *testWorkerBackend) TxPool() *core.TxPool              { return b.txPool }
+func (b *testWorkerBackend) BundlePool() *core.BundlePool      { return b.bundlePool }
 
 func (b *testWorkerBackend) newRandomTx(creation bool) *types.Transaction {
 	signer := types.LatestSigner(b.chain.Config())
@@ -415,3 +422,149 @@ func testNoTxChDeadlock(t *testing.T, validating bool) {
 		t.Error("Deadlock in mainLoop's select statement")
 	}
 }
+
+func TestCommitBundle(t *testing.T) {
+	engine := mockEngine.NewFaker()
+	w, _ := newTestWorker(t, params.IstanbulTestChainConfig, engine, rawdb.NewMemoryDatabase(), 0, false)
+	defer w.close()
+
+	b, err := prepareBlock(w)
+	if err != nil {
+		t.Fatalf("failed to prepare block: %v", err)
+	}
+	defer b.close()
+
+	signer := types.LatestSigner(params.IstanbulTestChainConfig)
+	transfer := func(nonce uint64) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, testUserAddress, big.NewInt(1000), params.TxGas, big.NewInt(params.InitialBaseFee), nil), signer, testBankKey)
+		return tx
+	}
+	gas := b.gasPool.Gas()
+
+	// A bundle with a failing transaction must leave no trace
+	bundle := &core.Bundle{Txs: types.Transactions{transfer(0), transfer(2)}, BlockNumber: b.header.Number}
+	if err := b.commitBundle(w, bundle, common.Big0); !errors.Is(err, core.ErrNonceTooHigh) {
+		t.Fatalf("failing bundle error mismatch: have %v, want %v", err, core.ErrNonceTooHigh)
+	}
+	if len(b.txs) != 0 || len(b.receipts) != 0 || b.tcount != 0 {
+		t.Fatalf("failing bundle included: %d txs, %d receipts, tcount %d", len(b.txs), len(b.receipts), b.tcount)
+	}
+	if b.gasPool.Gas() != gas || b.header.GasUsed != 0 {
+		t.Fatalf("failing bundle used gas: pool %d, want %d, header %d", b.gasPool.Gas(), gas, b.header.GasUsed)
+	}
+	if nonce := b.state.GetNonce(testBankAddress); nonce != 0 {
+		t.Fatalf("failing bundle changed nonce: have %d, want 0", nonce)
+	}
+	// A bundle paying less than the minimum tip must be reverted too
+	bundle = &core.Bundle{Txs: types.Transactions{transfer(0), transfer(1)}, BlockNumber: b.header.Number}
+	if err := b.commitBundle(w, bundle, new(big.Int).Lsh(common.Big1, 128)); !errors.Is(err, errBundleUnprofitable) {
+		t.Fatalf("unprofitable bundle error mismatch: have %v, want %v", err, errBundleUnprofitable)
+	}
+	if len(b.txs) != 0 || b.state.GetNonce(testBankAddress) != 0 {
+		t.Fatalf("unprofitable bundle included: %d txs", len(b.txs))
+	}
+	// A valid bundle is included in order
+	if err := b.commitBundle(w, bundle, common.Big0); err != nil {
+		t.Fatalf("failed to commit bundle: %v", err)
+	}
+	if len(b.txs) != 2 || b.txs[0].Hash() != bundle.Txs[0].Hash() || b.txs[1].Hash() != bundle.Txs[1].Hash() {
+		t.Fatalf("bundle transactions mismatch: have %d txs", len(b.txs))
+	}
+	if have, want := b.gasPool.Gas(), gas-2*params.TxGas; have != want {
+		t.Fatalf("gas pool mismatch: have %d, want %d", have, want)
+	}
+	if balance := b.state.GetBalance(testUserAddress); balance.Cmp(big.NewInt(2000)) != 0 {
+		t.Fatalf("account balance mismatch: have %v, want 2000", balance)
+	}
+}
+
+func TestCommitConditionalTransactions(t *testing.T) {
+	engine := mockEngine.NewFaker()
+	w, _ := newTestWorker(t, params.IstanbulTestChainConfig, engine, rawdb.NewMemoryDatabase(), 0, false)
+	defer w.close()
+
+	b, err := prepareBlock(w)
+	if err != nil {
+		t.Fatalf("failed to prepare block: %v", err)
+	}
+	defer b.close()
+
+	contract, slot := common.HexToAddress("0xc0ffee"), common.HexToHash("0x01")
+	b.state.SetState(contract, slot, common.HexToHash("0x02"))
+
+	signer := types.LatestSigner(params.IstanbulTestChainConfig)
+	commit := func(cond *types.TransactionConditional) {
+		tx, _ := types.SignTx(types.NewTransaction(0, testUserAddress, big.NewInt(1000), params.TxGas, big.NewInt(params.InitialBaseFee), nil), signer, testBankKey)
+		tx.SetConditional(cond)
+
+		baseFeeFn, toCELOFn := createConversionFunctions(b.sysCtx, w.chain, b.header, b.state)
+		txs := types.NewTransactionsByPriceAndNonce(b.signer, map[common.Address]types.Transactions{testBankAddress: {tx}}, baseFeeFn, toCELOFn)
+		if err := b.commitTransactions(context.Background(), w, txs, b.txFeeRecipient); err != nil {
+			t.Fatalf("failed to commit transactions: %v", err)
+		}
+	}
+	// Transactions whose storage or block conditions do not hold are skipped
+	commit(&types.TransactionConditional{
+		KnownAccounts: map[common.Address]map[common.Hash]common.Hash{contract: {slot: common.HexToHash("0x03")}},
+	})
+	if len(b.txs) != 0 {
+		t.Fatalf("transaction with unmet storage condition included")
+	}
+	commit(&types.TransactionConditional{BlockNumberMin: new(big.Int).Add(b.header.Number, common.Big1)})
+	if len(b.txs) != 0 {
+		t.Fatalf("transaction with unmet block number condition included")
+	}
+	// Transactions whose conditions hold are included
+	commit(&types.TransactionConditional{
+		KnownAccounts:  map[common.Address]map[common.Hash]common.Hash{contract: {slot: common.HexToHash("0x02")}},
+		BlockNumberMax: b.header.Number,
+	})
+	if len(b.txs) != 1 {
+		t.Fatalf("transaction with met conditions not included")
+	}
+}
+
+func TestCommitBundlesLimit(t *testing.T) {
+	engine := mockEngine.NewFaker()
+	w, backend := newTestWorker(t, params.IstanbulTestChainConfig, engine, rawdb.NewMemoryDatabase(), 0, false)
+	defer w.close()
+
+	b, err := prepareBlock(w)
+	if err != nil {
+		t.Fatalf("failed to prepare block: %v", err)
+	}
+	var (
+		signer     = types.LatestSigner(params.IstanbulTestChainConfig)
+		head       = &types.Header{Number: new(big.Int).Sub(b.header.Number, common.Big1)}
+		currencies = currency.NewCacheOnlyManager(nil)
+	)
+	addBundle := func(key *ecdsa.PrivateKey, nonce uint64, gasPrice *big.Int) {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, testBankAddress, big.NewInt(1000), params.TxGas, gasPrice, nil), signer, key)
+		if err := backend.bundlePool.Add(&core.Bundle{Txs: types.Transactions{tx}, BlockNumber: b.header.Number}, head, currencies); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+	// Better paying bundles of an account without funds are tried first and fail
+	for i := 0; i < maxBundlesPerBlock-1; i++ {
+		addBundle(testUserKey, uint64(i), big.NewInt(2*params.InitialBaseFee))
+	}
+	addBundle(testBankKey, 0, big.NewInt(params.InitialBaseFee))
+
+	b.commitBundles(w)
+	if len(b.txs) != 1 {
+		t.Fatalf("bundle within the limit not included")
+	}
+	b.close()
+
+	// Once more bundles are pooled, the least profitable one isn't tried
+	addBundle(testBankKey, 1, big.NewInt(2*params.InitialBaseFee))
+	if b, err = prepareBlock(w); err != nil {
+		t.Fatalf("failed to prepare block: %v", err)
+	}
+	defer b.close()
+
+	b.commitBundles(w)
+	if len(b.txs) != 0 {
+		t.Fatalf("bundle beyond the limit included")
+	}
+}
@@ -18,10 +18,12 @@ package params
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/celo-org/celo-blockchain/common"
+	"github.com/celo-org/celo-blockchain/common/hexutil"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -48,6 +50,11 @@ var TrustedCheckpoints = map[common.Hash]*TrustedCheckpoint{}
 // the chain it belongs to.
 var CheckpointOracles = map[common.Hash]*CheckpointOracleConfig{}
 
+// TrustedEpochs associates each known trusted epoch header with the genesis hash
+// of the chain it belongs to. No epochs are embedded for the public networks yet,
+// so a trusted epoch has to be supplied with --sync.trustedepoch.
+var TrustedEpochs = map[common.Hash]*TrustedEpoch{}
+
 var (
 	// MainnetChainConfig is the chain parameters to run a node on the main network.
 	MainnetChainConfig = &ChainConfig{
@@ -270,6 +277,56 @@ func (c *TrustedCheckpoint) Empty() bool {
 	return c.SectionHead == (common.Hash{}) || c.CHTRoot == (common.Hash{}) || c.BloomRoot == (common.Hash{})
 }
 
+// TrustedEpoch identifies the last header of an epoch together with the validator
+// set it elects for the following epoch. Lightest sync uses it as the root of the
+// validator set chain instead of the genesis block, skipping all earlier epochs.
+// Fast sync downloads the whole chain but only verifies the headers from it on.
+// Full sync verifies the whole chain and doesn't use it.
+type TrustedEpoch struct {
+	Epoch      uint64                  `json:"epoch"`
+	Hash       common.Hash             `json:"hash"`
+	Validators []TrustedEpochValidator `json:"validators"`
+}
+
+// TrustedEpochValidator is a member of the validator set of a trusted epoch.
+type TrustedEpochValidator struct {
+	Address      common.Address `json:"address"`
+	BLSPublicKey hexutil.Bytes  `json:"blsPublicKey"`
+}
+
+// BlockNumber returns the number of the trusted epoch header.
+func (e *TrustedEpoch) BlockNumber(epochSize uint64) uint64 {
+	return e.Epoch * epochSize
+}
+
+// Validate checks that the trusted epoch identifies a header and its validator set.
+func (e *TrustedEpoch) Validate() error {
+	if e.Epoch == 0 {
+		return errors.New("trusted epoch must be above 0")
+	}
+	if e.Hash == (common.Hash{}) {
+		return errors.New("trusted epoch hash is required")
+	}
+	if len(e.Validators) == 0 {
+		return errors.New("trusted epoch validators are required")
+	}
+	for _, v := range e.Validators {
+		if len(v.BLSPublicKey) == 0 {
+			return fmt.Errorf("trusted epoch validator %s has no BLS public key", v.Address)
+		}
+	}
+	return nil
+}
+
+// TrustedEpochFor returns the trusted epoch to sync the chain with the given
+// genesis hash from: the configured one if any, the embedded one otherwise.
+func TrustedEpochFor(genesisHash common.Hash, configured *TrustedEpoch) *TrustedEpoch {
+	if configured != nil {
+		return configured
+	}
+	return TrustedEpochs[genesisHash]
+}
+
 // CheckpointOracleConfig represents a set of checkpoint contract(which acts as an oracle)
 // config which used for light client checkpoint syncing.
 type CheckpointOracleConfig struct {
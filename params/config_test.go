@@ -20,6 +20,8 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/celo-org/celo-blockchain/common"
 )
 
 func TestCheckCompatible(t *testing.T) {
@@ -96,3 +98,52 @@ func TestCheckCompatible(t *testing.T) {
 		}
 	}
 }
+
+func TestTrustedEpochFor(t *testing.T) {
+	genesis := common.HexToHash("0x01")
+	embedded := &TrustedEpoch{Epoch: 10, Hash: common.HexToHash("0x0a")}
+	TrustedEpochs[genesis] = embedded
+	defer delete(TrustedEpochs, genesis)
+
+	if have := TrustedEpochFor(genesis, nil); have != embedded {
+		t.Errorf("embedded trusted epoch not found: have %v, want %v", have, embedded)
+	}
+	if have := TrustedEpochFor(common.HexToHash("0x02"), nil); have != nil {
+		t.Errorf("trusted epoch found for unknown genesis: %v", have)
+	}
+	configured := &TrustedEpoch{Epoch: 20, Hash: common.HexToHash("0x14")}
+	if have := TrustedEpochFor(genesis, configured); have != configured {
+		t.Errorf("configured trusted epoch not preferred: have %v, want %v", have, configured)
+	}
+}
+
+func TestTrustedEpochValidate(t *testing.T) {
+	valid := func() *TrustedEpoch {
+		return &TrustedEpoch{
+			Epoch:      1,
+			Hash:       common.HexToHash("0x01"),
+			Validators: []TrustedEpochValidator{{Address: common.HexToAddress("0x01"), BLSPublicKey: []byte{1}}},
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("valid trusted epoch rejected: %v", err)
+	}
+	tests := []func(e *TrustedEpoch){
+		func(e *TrustedEpoch) { e.Epoch = 0 },
+		func(e *TrustedEpoch) { e.Hash = common.Hash{} },
+		func(e *TrustedEpoch) { e.Validators = nil },
+		func(e *TrustedEpoch) { e.Validators[0].BLSPublicKey = nil },
+	}
+	for i, modify := range tests {
+		e := valid()
+		modify(e)
+		if err := e.Validate(); err == nil {
+			t.Errorf("test %d: invalid trusted epoch accepted", i)
+		}
+	}
+	for genesis, e := range TrustedEpochs {
+		if err := e.Validate(); err != nil {
+			t.Errorf("trusted epoch of genesis %x: %v", genesis, err)
+		}
+	}
+}
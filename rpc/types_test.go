@@ -48,6 +48,7 @@ func TestBlockNumberJSONUnmarshal(t *testing.T) {
 		14: {`someString`, true, BlockNumber(0)},
 		15: {`""`, true, BlockNumber(0)},
 		16: {``, true, BlockNumber(0)},
+		17: {`"latestEpoch"`, false, LatestEpochBlockNumber},
 	}
 
 	for i, test := range tests {
@@ -99,6 +100,8 @@ func TestBlockNumberOrHash_UnmarshalJSON(t *testing.T) {
 		23: {`{"blockNumber":"latest"}`, false, BlockNumberOrHashWithNumber(LatestBlockNumber)},
 		24: {`{"blockNumber":"earliest"}`, false, BlockNumberOrHashWithNumber(EarliestBlockNumber)},
 		25: {`{"blockNumber":"0x1", "blockHash":"0x0000000000000000000000000000000000000000000000000000000000000000"}`, true, BlockNumberOrHash{}},
+		26: {`"latestEpoch"`, false, BlockNumberOrHashWithNumber(LatestEpochBlockNumber)},
+		27: {`{"blockNumber":"latestEpoch"}`, false, BlockNumberOrHashWithNumber(LatestEpochBlockNumber)},
 	}
 
 	for i, test := range tests {
@@ -132,6 +135,7 @@ func TestBlockNumberOrHash_WithNumber_MarshalAndUnmarshal(t *testing.T) {
 		{"max", math.MaxInt64},
 		{"pending", int64(PendingBlockNumber)},
 		{"latest", int64(LatestBlockNumber)},
+		{"latestEpoch", int64(LatestEpochBlockNumber)},
 		{"earliest", int64(EarliestBlockNumber)},
 	}
 	for _, test := range tests {